@@ -1,18 +1,32 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"tracerstudy-auth-service/common/certs"
 	"tracerstudy-auth-service/common/config"
 
 	gormConn "tracerstudy-auth-service/common/gorm"
 	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/mailer"
 	"tracerstudy-auth-service/common/mysql"
+	"tracerstudy-auth-service/common/password"
+	"tracerstudy-auth-service/common/upstream"
 	"tracerstudy-auth-service/server"
 
 	authModule "tracerstudy-auth-service/modules/auth"
+	authEntity "tracerstudy-auth-service/modules/auth/entity"
+	authRepo "tracerstudy-auth-service/modules/auth/repository"
+	authSvc "tracerstudy-auth-service/modules/auth/service"
+	authorizationModule "tracerstudy-auth-service/modules/authorization"
+	roleModule "tracerstudy-auth-service/modules/role"
+	roleEntity "tracerstudy-auth-service/modules/role/entity"
+	roleSvc "tracerstudy-auth-service/modules/role/service"
 	userModule "tracerstudy-auth-service/modules/user"
+	userEntity "tracerstudy-auth-service/modules/user/entity"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"gorm.io/gorm"
 )
 
@@ -30,20 +44,113 @@ func main() {
 	checkError(gerr)
 	// errUtils.ConvertToRestError(gerr)
 
-	jwtManager := commonJwt.NewJWT(cfg.JWT.JwtSecretKey, cfg.JWT.TokenDuration)
+	checkError(migrate(db))
 
-	grpcServer := server.NewGrpcServer(cfg.Port.GRPC, jwtManager)
-	grpcConn := server.InitGRPCConn(fmt.Sprintf("127.0.0.1:%v", cfg.Port.GRPC), false, "")
+	revocationStore := commonJwt.NewCachedRevocationStore(authRepo.NewRevocationRepository(db), cfg.JWT.RevocationCacheTTL)
+	signingKey, kerr := commonJwt.LoadSigningKey(cfg.JWT.SigningAlg, cfg.JWT.KeyId, cfg.JWT.JwtSecretKey, cfg.JWT.PrivateKeyPath)
+	checkError(kerr)
 
-	registerGrpcHandlers(grpcServer.Server, *cfg, db, jwtManager, grpcConn)
+	jwtManager := commonJwt.NewJWT(cfg.JWT, signingKey, revocationStore)
+
+	keyCipher, cerr := commonJwt.NewKeyCipher(cfg.JWT.KeyEncryptionKey)
+	checkError(cerr)
+
+	signingKeySvc := authSvc.NewSigningKeyService(*cfg, authRepo.NewSigningKeyRepository(db), jwtManager, keyCipher)
+	checkError(signingKeySvc.Bootstrap(context.Background()))
+	go signingKeySvc.Watch(context.Background(), cfg.JWT.KeyRefreshInterval)
+
+	policy, perr := roleModule.InitPolicy(context.Background(), *cfg, db)
+	checkError(perr)
+
+	passwordHasher, herr := password.NewHasher(cfg.Password)
+	checkError(herr)
+
+	mail, merr := mailer.NewMailer(cfg.Mail)
+	checkError(merr)
+
+	upstreams := upstream.NewRegistry(cfg.Upstream)
+
+	tlsReloader := certs.NewReloader()
+	serverTLS, terr := tlsReloader.ServerConfig(cfg.TLS)
+	checkError(terr)
+
+	var serverOptions []grpc.ServerOption
+	var loopbackOptions []server.DialOption
+	if serverTLS != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(serverTLS)))
+		loopbackOptions = append(loopbackOptions, server.WithTLS(tlsReloader, cfg.TLS, cfg.TLS.CAFile, cfg.TLS.ServerName))
+	}
+
+	grpcServer := server.NewGrpcServer(cfg.Port.GRPC, jwtManager, policy, serverOptions...)
+	grpcConn := server.InitGRPCConn(fmt.Sprintf("127.0.0.1:%v", cfg.Port.GRPC), loopbackOptions...)
+
+	registerGrpcHandlers(grpcServer.Server, *cfg, db, jwtManager, keyCipher, passwordHasher, mail, policy, upstreams, tlsReloader, grpcConn)
+	go tlsReloader.Watch(context.Background(), cfg.TLS.ReloadInterval)
+	server.RegisterHealth(grpcServer.Server, upstreams)
+
+	restServer := server.NewRest(cfg.Port.REST)
+	checkError(registerRestHandlers(restServer, jwtManager, upstreams))
 
 	_ = grpcServer.Run()
+	_ = restServer.Run()
 	_ = grpcServer.AwaitTermination()
 }
 
-func registerGrpcHandlers(server *grpc.Server, cfg config.Config, db *gorm.DB, jwtManager *commonJwt.JWT, grpcConn *grpc.ClientConn) {
-	authModule.InitGrpc(server, cfg, db, jwtManager, grpcConn)
-	userModule.InitGrpc(server, cfg, db, grpcConn)
+func registerGrpcHandlers(server *grpc.Server, cfg config.Config, db *gorm.DB, jwtManager *commonJwt.JWT, keyCipher *commonJwt.KeyCipher, passwordHasher password.Hasher, mail mailer.Mailer, policy *roleSvc.PolicyService, upstreams *upstream.Registry, tlsReloader *certs.Reloader, grpcConn *grpc.ClientConn) {
+	authModule.InitGrpc(server, cfg, db, jwtManager, keyCipher, passwordHasher, mail, upstreams, tlsReloader, grpcConn)
+	userModule.InitGrpc(server, cfg, db, jwtManager, passwordHasher, mail, grpcConn)
+	authorizationModule.InitGrpc(server, cfg, jwtManager, policy)
+	roleModule.InitGrpc(server, cfg, db, policy)
+}
+
+func registerRestHandlers(rest *server.Rest, jwtManager *commonJwt.JWT, upstreams *upstream.Registry) error {
+	if err := rest.HandlePath("GET", server.HealthPath, server.NewHealthRestHandler(upstreams)); err != nil {
+		return err
+	}
+
+	return authModule.InitRest(rest, jwtManager)
+}
+
+func migrate(db *gorm.DB) error {
+	if err := migrateEmailVerifiedAt(db); err != nil {
+		return err
+	}
+
+	return db.AutoMigrate(
+		&authEntity.RefreshToken{},
+		&authEntity.RevokedToken{},
+		&authEntity.SubjectRevocation{},
+		&authEntity.SigningKey{},
+		&authEntity.PasswordResetToken{},
+		&authEntity.UserStudyLoginToken{},
+		&authEntity.UserStudyGrant{},
+		&authEntity.LoginAttempt{},
+		&authEntity.MfaCredential{},
+		&authEntity.MfaRecoveryCode{},
+		&authEntity.MfaChallenge{},
+		&roleEntity.Role{},
+		&roleEntity.Permission{},
+		&roleEntity.RolePermission{},
+		&userEntity.UserScope{},
+		&userEntity.Alumni{},
+		&userEntity.EmailVerificationToken{},
+	)
+}
+
+// migrateEmailVerifiedAt adds users.email_verified_at, which AutoMigrate
+// can't do for the users table. Accounts that existed before verification
+// was introduced are treated as verified.
+func migrateEmailVerifiedAt(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if migrator.HasColumn(&userEntity.User{}, "EmailVerifiedAt") {
+		return nil
+	}
+
+	if err := migrator.AddColumn(&userEntity.User{}, "EmailVerifiedAt"); err != nil {
+		return err
+	}
+
+	return db.Unscoped().Model(&userEntity.User{}).Where("1 = 1").UpdateColumn("email_verified_at", gorm.Expr("created_at")).Error
 }
 
 func checkError(err error) {
@@ -55,7 +162,7 @@ func checkError(err error) {
 func splash(cfg *config.Config) {
 	version := "1.0.0"
 	colorReset := "\033[0m"
-	// colorBlue := "\033[34m"
+	colorBlue := "\033[34m"
 	colorCyan := "\033[36m"
 
 	fmt.Printf(`
@@ -68,7 +175,7 @@ func splash(cfg *config.Config) {
                                                                                   / ___/
 	`, version)
 
-	// fmt.Println(colorBlue, fmt.Sprintf(`⇨ REST server started on port :%s`, cfg.Port.REST))
+	fmt.Println(colorBlue, fmt.Sprintf(`⇨ REST server started on port :%s`, cfg.Port.REST))
 	fmt.Println(colorCyan, fmt.Sprintf(`⇨ GRPC auth service server started on port :%s`, cfg.Port.GRPC))
 	fmt.Println(colorReset, "")
 }
@@ -0,0 +1,22 @@
+package authorization
+
+import (
+	"context"
+	"strings"
+)
+
+// Policy decides which roles may perform an action on a resource. The
+// AuthInterceptor and the AuthorizationService share it, so our own RPCs and
+// the decisions other services delegate to us always agree.
+type Policy interface {
+	// Governs reports whether the policy has a rule for resource and action.
+	Governs(ctx context.Context, resource, action string) (bool, error)
+	Allows(ctx context.Context, resource, action string, role uint32) (bool, error)
+}
+
+// SplitFullMethod splits a gRPC method such as
+// "/tracer_study_grpc.UserService/GetAllUsers" into its resource and action.
+func SplitFullMethod(fullMethod string) (string, string) {
+	resource, action, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return resource, action
+}
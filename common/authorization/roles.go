@@ -1,47 +1,132 @@
 package authorization
 
+import "strings"
+
 type AccessibleRoles map[string]map[string][]uint32
 
-/*
-	1. Super Admin
-	2. Admin
-	3. Manager
-	4. Executive
-	5. Admin Prodi
-	6. Alumni
-	7. Pengguna Alumni
-	8. Admin Post
-*/
+const (
+	RoleSuperAdmin     uint32 = 1
+	RoleAdmin          uint32 = 2
+	RoleManager        uint32 = 3
+	RoleExecutive      uint32 = 4
+	RoleAdminProdi     uint32 = 5
+	RoleAlumni         uint32 = 6
+	RolePenggunaAlumni uint32 = 7
+	RoleAdminPost      uint32 = 8
+)
+
+// defaultRoles are the built-in roles seeded into the roles table. Code
+// refers to them by id, so they cannot be deleted.
+var defaultRoles = map[uint32]string{
+	RoleSuperAdmin:     "Super Admin",
+	RoleAdmin:          "Admin",
+	RoleManager:        "Manager",
+	RoleExecutive:      "Executive",
+	RoleAdminProdi:     "Admin Prodi",
+	RoleAlumni:         "Alumni",
+	RolePenggunaAlumni: "Pengguna Alumni",
+	RoleAdminPost:      "Admin Post",
+}
 
 const (
 	BasePath = "tracer_study_grpc"
 	AuthSvc  = "AuthService"
 	UserSvc  = "UserService"
+	RoleSvc  = "RoleService"
+
+	AuthorizationSvc = "AuthorizationService"
 )
 
+// roles maps each resource (a fully qualified gRPC service name) and action
+// (an RPC name) to the roles allowed to perform it by default. They are
+// seeded into the permissions tables, where Super Admins can change them.
 var roles = AccessibleRoles{
-	"/" + BasePath + "." + AuthSvc + "/": {
-		"RegisterUser":   {1, 2},
-		"GetCurrentUser": {1, 2, 3, 4, 5, 6, 7, 8},
+	BasePath + "." + AuthSvc: {
+		"RegisterUser":            {1, 2},
+		"GetCurrentUser":          {1, 2, 3, 4, 5, 6, 7, 8},
+		"Logout":                  {1, 2, 3, 4, 5, 6, 7, 8},
+		"ChangePassword":          {1, 2, 3, 4, 5, 8},
+		"DisableMfa":              {1, 2, 3, 4, 5, 8},
+		"RegenerateRecoveryCodes": {1, 2, 3, 4, 5, 8},
+		"ListSigningKeys":         {1},
+		"CreateSigningKey":        {1},
+		"PromoteSigningKey":       {1},
+		"RetireSigningKey":        {1},
+		"ListLoginLockouts":       {1, 2},
+		"ClearLoginLockout":       {1, 2},
+	},
+	BasePath + "." + UserSvc: {
+		"GetAllUsers":   {1, 2},
+		"GetUserById":   {1, 2},
+		"CreateUser":    {1, 2},
+		"UpdateUser":    {1, 2},
+		"DeleteUser":    {1, 2},
+		"GetUserScopes": {1, 2},
+		"SetUserScopes": {1, 2},
 	},
-	"/" + BasePath + "." + UserSvc + "/": {
-		"GetAllUser":  {1, 2},
-		"GetUserById": {1, 2},
-		"CreateUser":  {1, 2},
-		"UpdateUser":  {1, 2},
-		"DeleteUser":  {1, 2},
+	BasePath + "." + RoleSvc: {
+		"GetAllRoles":        {1},
+		"GetRoleById":        {1},
+		"CreateRole":         {1},
+		"UpdateRole":         {1},
+		"DeleteRole":         {1},
+		"GetAllPermissions":  {1},
+		"CreatePermission":   {1},
+		"UpdatePermission":   {1},
+		"DeletePermission":   {1},
+		"GetRolePermissions": {1},
+		"SetRolePermissions": {1},
 	},
 }
 
-func GetAccessibleRoles() map[string][]uint32 {
-	routes := make(map[string][]uint32)
+// publicActions are the RPCs of our services that need no access token,
+// because they issue one or authenticate the caller themselves. Any other
+// RPC of our services without a permission is denied.
+var publicActions = map[string]map[string]bool{
+	BasePath + "." + AuthSvc: {
+		"LoginAlumni":               true,
+		"LoginUserStudy":            true,
+		"RequestUserStudyLoginLink": true,
+		"ConsumeUserStudyLoginLink": true,
+		"LoginUser":                 true,
+		"VerifyMfa":                 true,
+		"BeginMfaEnrollment":        true,
+		"ConfirmMfaEnrollment":      true,
+		"RequestPasswordReset":      true,
+		"ResetPassword":             true,
+		"VerifyEmail":               true,
+		"ResendVerification":        true,
+		"RefreshToken":              true,
+		"GetJwks":                   true,
+		"IntrospectToken":           true,
+	},
+	BasePath + "." + AuthorizationSvc: {
+		"CheckPermission":      true,
+		"BatchCheckPermission": true,
+	},
+}
+
+// IsPublic reports whether action on resource needs no access token.
+func IsPublic(resource, action string) bool {
+	return publicActions[resource][action]
+}
+
+// IsOwnResource reports whether resource is one of our gRPC services.
+func IsOwnResource(resource string) bool {
+	return strings.HasPrefix(resource, BasePath+".")
+}
+
+// IsDefaultPermission reports whether the permission for action on
+// resource is seeded from roles.
+func IsDefaultPermission(resource, action string) bool {
+	_, ok := roles[resource][action]
+	return ok
+}
 
-	for service, methods := range roles {
-		for method, methodRoles := range methods {
-			route := service + method
-			routes[route] = methodRoles
-		}
-	}
+func GetAccessibleRoles() AccessibleRoles {
+	return roles
+}
 
-	return routes
+func GetDefaultRoles() map[uint32]string {
+	return defaultRoles
 }
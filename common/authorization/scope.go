@@ -0,0 +1,45 @@
+package authorization
+
+import (
+	commonJwt "tracerstudy-auth-service/common/jwt"
+)
+
+// InScope reports whether a caller with role and scopes may act on a
+// resource belonging to kodeprodi and kodefak. Resources that belong to no
+// prodi or faculty are always in scope.
+//
+// Admin Prodi users are limited to their assigned prodi and faculties and
+// reach nothing without an assignment. Managers and Executives are limited
+// only once they have an assignment, otherwise they act university wide.
+// Other roles are never limited.
+func InScope(role uint32, scopes commonJwt.Scopes, kodeprodi, kodefak string) bool {
+	if kodeprodi == "" && kodefak == "" {
+		return true
+	}
+
+	switch role {
+	case RoleAdminProdi:
+	case RoleManager, RoleExecutive:
+		if scopes.IsEmpty() {
+			return true
+		}
+	default:
+		return true
+	}
+
+	if kodeprodi != "" && contains(scopes.Kodeprodi, kodeprodi) {
+		return true
+	}
+
+	return kodefak != "" && contains(scopes.Kodefak, kodefak)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
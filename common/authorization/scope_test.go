@@ -0,0 +1,37 @@
+package authorization
+
+import (
+	"testing"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+)
+
+func TestInScope(t *testing.T) {
+	assigned := commonJwt.Scopes{Kodeprodi: []string{"0401"}, Kodefak: []string{"05"}}
+
+	tests := []struct {
+		name      string
+		role      uint32
+		scopes    commonJwt.Scopes
+		kodeprodi string
+		kodefak   string
+		want      bool
+	}{
+		{name: "resource without prodi or faculty", role: RoleAdminProdi, want: true},
+		{name: "admin prodi in assigned prodi", role: RoleAdminProdi, scopes: assigned, kodeprodi: "0401", want: true},
+		{name: "admin prodi in assigned faculty", role: RoleAdminProdi, scopes: assigned, kodeprodi: "0501", kodefak: "05", want: true},
+		{name: "admin prodi in other prodi", role: RoleAdminProdi, scopes: assigned, kodeprodi: "0402", kodefak: "04"},
+		{name: "admin prodi without assignment", role: RoleAdminProdi, kodeprodi: "0401"},
+		{name: "manager without assignment", role: RoleManager, kodeprodi: "0402", want: true},
+		{name: "manager in other prodi", role: RoleManager, scopes: assigned, kodeprodi: "0402"},
+		{name: "executive in assigned prodi", role: RoleExecutive, scopes: assigned, kodeprodi: "0401", want: true},
+		{name: "admin is never limited", role: RoleAdmin, scopes: assigned, kodeprodi: "0402", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InScope(tt.role, tt.scopes, tt.kodeprodi, tt.kodefak); got != tt.want {
+				t.Errorf("InScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
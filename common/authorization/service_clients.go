@@ -0,0 +1,60 @@
+package authorization
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"log"
+	"strings"
+	"tracerstudy-auth-service/common/utils"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceClients holds the credentials other tracer-study services present,
+// as HTTP Basic authorization, when calling service-to-service RPCs.
+type ServiceClients struct {
+	secrets map[string][sha256.Size]byte
+}
+
+// NewServiceClients parses a comma separated list of client_id:secret pairs.
+func NewServiceClients(clients string) *ServiceClients {
+	secrets := make(map[string][sha256.Size]byte)
+
+	for _, client := range strings.Split(clients, ",") {
+		client = strings.TrimSpace(client)
+		if client == "" {
+			continue
+		}
+
+		id, secret, ok := strings.Cut(client, ":")
+		if !ok || id == "" || secret == "" {
+			log.Println("WARNING: [Authorization - NewServiceClients] Ignoring malformed service client entry")
+			continue
+		}
+
+		secrets[id] = sha256.Sum256([]byte(secret))
+	}
+
+	return &ServiceClients{
+		secrets: secrets,
+	}
+}
+
+// Authenticate returns the id of the calling service client.
+func (s *ServiceClients) Authenticate(ctx context.Context) (string, error) {
+	id, secret, err := utils.GetBasicAuth(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	expected, ok := s.secrets[id]
+	actual := sha256.Sum256([]byte(secret))
+	if !ok || subtle.ConstantTimeCompare(expected[:], actual[:]) != 1 {
+		log.Println("WARNING: [Authorization - Authenticate] Invalid service client credentials for", id)
+		return "", status.Errorf(codes.Unauthenticated, "invalid service client credentials")
+	}
+
+	return id, nil
+}
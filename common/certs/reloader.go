@@ -0,0 +1,197 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reloader loads key pairs and CA bundles from files and reloads them when
+// the files change, so rotated certificates are used without a restart.
+// Files that fail to load keep their previous contents.
+type Reloader struct {
+	mu       sync.Mutex
+	keyPairs map[string]*KeyPair
+	caPools  map[string]*CAPool
+	files    []*watchedFiles
+}
+
+func NewReloader() *Reloader {
+	return &Reloader{
+		keyPairs: make(map[string]*KeyPair),
+		caPools:  make(map[string]*CAPool),
+	}
+}
+
+// KeyPair is a certificate and its private key, as last loaded.
+type KeyPair struct {
+	cert atomic.Pointer[tls.Certificate]
+}
+
+func (kp *KeyPair) Certificate() *tls.Certificate {
+	return kp.cert.Load()
+}
+
+// CAPool is a CA bundle, as last loaded.
+type CAPool struct {
+	pool atomic.Pointer[x509.CertPool]
+}
+
+func (p *CAPool) Pool() *x509.CertPool {
+	return p.pool.Load()
+}
+
+// KeyPair loads the key pair in certFile and keyFile, or returns the one
+// already loaded from them.
+func (r *Reloader) KeyPair(certFile, keyFile string) (*KeyPair, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := certFile + "\x00" + keyFile
+	if kp, ok := r.keyPairs[key]; ok {
+		return kp, nil
+	}
+
+	kp := &KeyPair{}
+	err := r.watch(func() error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		kp.cert.Store(&cert)
+		return nil
+	}, certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	r.keyPairs[key] = kp
+	return kp, nil
+}
+
+// CAPool loads the PEM encoded CA bundle in file, or returns the one
+// already loaded from it.
+func (r *Reloader) CAPool(file string) (*CAPool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p, ok := r.caPools[file]; ok {
+		return p, nil
+	}
+
+	p := &CAPool{}
+	err := r.watch(func() error {
+		pem, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", file)
+		}
+		p.pool.Store(pool)
+		return nil
+	}, file)
+	if err != nil {
+		return nil, err
+	}
+
+	r.caPools[file] = p
+	return p, nil
+}
+
+func (r *Reloader) watch(load func() error, paths ...string) error {
+	files := &watchedFiles{
+		paths:    paths,
+		modTimes: make([]time.Time, len(paths)),
+		load:     load,
+	}
+	if _, err := files.reload(); err != nil {
+		return err
+	}
+
+	r.files = append(r.files, files)
+	return nil
+}
+
+// Reload reloads the files changed since they were last loaded.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	files := r.files
+	r.mu.Unlock()
+
+	var errs []error
+	for _, f := range files {
+		reloaded, err := f.reload()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if reloaded {
+			log.Println("INFO: [Reloader - Reload] Reloaded", strings.Join(f.paths, ", "))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Watch reloads changed files every interval until ctx is done.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Reload(); err != nil {
+				log.Println("ERROR: [Reloader - Watch] Error while reloading certificates:", err)
+			}
+		}
+	}
+}
+
+type watchedFiles struct {
+	paths    []string
+	modTimes []time.Time
+	load     func() error
+}
+
+// reload loads the files again if any of them changed. The modification
+// times are only recorded once loading succeeds, so files that are half
+// replaced, such as a new certificate next to the old key, are retried on
+// the next call.
+func (f *watchedFiles) reload() (bool, error) {
+	modTimes := make([]time.Time, len(f.paths))
+	changed := false
+	for i, path := range f.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+		modTimes[i] = info.ModTime()
+		if !modTimes[i].Equal(f.modTimes[i]) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	if err := f.load(); err != nil {
+		return false, fmt.Errorf("failed to load %s: %v", strings.Join(f.paths, ", "), err)
+	}
+
+	f.modTimes = modTimes
+	return true, nil
+}
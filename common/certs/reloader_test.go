@@ -0,0 +1,281 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testPair is a self-signed certificate and its key, PEM encoded.
+type testPair struct {
+	cert []byte
+	key  []byte
+}
+
+func newTestPair(t *testing.T, commonName string) testPair {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              []string{commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+
+	return testPair{
+		cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// writeFile replaces path and sets its modification time, so changes are
+// seen regardless of the file system's timestamp resolution.
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+}
+
+func commonName(t *testing.T, kp *KeyPair) string {
+	t.Helper()
+
+	cert, err := x509.ParseCertificate(kp.Certificate().Certificate[0])
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return cert.Subject.CommonName
+}
+
+func (p testPair) name() string {
+	block, _ := pem.Decode(p.cert)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ""
+	}
+	return cert.Subject.CommonName
+}
+
+func trusts(t *testing.T, pool *CAPool, ca testPair) bool {
+	t.Helper()
+
+	block, _ := pem.Decode(ca.cert)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	_, err = cert.Verify(x509.VerifyOptions{Roots: pool.Pool()})
+	return err == nil
+}
+
+func TestReloaderKeyPairReload(t *testing.T) {
+	oldPair := newTestPair(t, "old.example.com")
+	newPair := newTestPair(t, "new.example.com")
+
+	tests := []struct {
+		name      string
+		writeCert bool
+		writeKey  bool
+		wantErr   bool
+		wantName  string
+	}{
+		{
+			name:     "unchanged files",
+			wantName: "old.example.com",
+		},
+		{
+			name:      "new certificate next to the old key",
+			writeCert: true,
+			wantErr:   true,
+			wantName:  "old.example.com",
+		},
+		{
+			name:     "new key next to the old certificate",
+			writeKey: true,
+			wantErr:  true,
+			wantName: "old.example.com",
+		},
+		{
+			name:      "new key pair",
+			writeCert: true,
+			writeKey:  true,
+			wantName:  "new.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+			loadedAt := time.Now().Add(-time.Hour)
+			writeFile(t, certFile, oldPair.cert, loadedAt)
+			writeFile(t, keyFile, oldPair.key, loadedAt)
+
+			r := NewReloader()
+			kp, err := r.KeyPair(certFile, keyFile)
+			if err != nil {
+				t.Fatalf("KeyPair() error = %v", err)
+			}
+			if again, err := r.KeyPair(certFile, keyFile); err != nil || again != kp {
+				t.Errorf("KeyPair() of the same files = %p, %v, want %p", again, err, kp)
+			}
+
+			if tt.writeCert {
+				writeFile(t, certFile, newPair.cert, time.Now())
+			}
+			if tt.writeKey {
+				writeFile(t, keyFile, newPair.key, time.Now())
+			}
+
+			if err := r.Reload(); (err != nil) != tt.wantErr {
+				t.Fatalf("Reload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := commonName(t, kp); got != tt.wantName {
+				t.Errorf("certificate = %s, want %s", got, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestReloaderKeyPairPartialReplace(t *testing.T) {
+	oldPair := newTestPair(t, "old.example.com")
+	newPair := newTestPair(t, "new.example.com")
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	loadedAt := time.Now().Add(-time.Hour)
+	writeFile(t, certFile, oldPair.cert, loadedAt)
+	writeFile(t, keyFile, oldPair.key, loadedAt)
+
+	r := NewReloader()
+	kp, err := r.KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("KeyPair() error = %v", err)
+	}
+
+	// the certificate is replaced first and the key only later, as a
+	// rotation copying one file at a time does
+	writeFile(t, certFile, newPair.cert, time.Now().Add(-time.Minute))
+	if err := r.Reload(); err == nil {
+		t.Fatalf("Reload() with a mismatched key succeeded")
+	}
+	if got := commonName(t, kp); got != "old.example.com" {
+		t.Fatalf("certificate after a failed reload = %s, want old.example.com", got)
+	}
+
+	// the mismatch keeps being retried and reported until it is resolved
+	if err := r.Reload(); err == nil {
+		t.Fatalf("second Reload() with a mismatched key succeeded")
+	}
+
+	writeFile(t, keyFile, newPair.key, time.Now())
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload() once the key is replaced error = %v", err)
+	}
+	if got := commonName(t, kp); got != "new.example.com" {
+		t.Errorf("certificate after the key is replaced = %s, want new.example.com", got)
+	}
+}
+
+func TestReloaderCAPool(t *testing.T) {
+	oldCA := newTestPair(t, "old-ca")
+	newCA := newTestPair(t, "new-ca")
+
+	tests := []struct {
+		name     string
+		contents []byte
+		wantErr  bool
+		wantCA   string
+	}{
+		{
+			name:     "new bundle",
+			contents: newCA.cert,
+			wantCA:   "new-ca",
+		},
+		{
+			name:     "bundle without certificates",
+			contents: []byte("not a certificate"),
+			wantErr:  true,
+			wantCA:   "old-ca",
+		},
+		{
+			name:    "removed bundle",
+			wantErr: true,
+			wantCA:  "old-ca",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "ca.crt")
+			writeFile(t, file, oldCA.cert, time.Now().Add(-time.Hour))
+
+			r := NewReloader()
+			pool, err := r.CAPool(file)
+			if err != nil {
+				t.Fatalf("CAPool() error = %v", err)
+			}
+
+			if tt.contents != nil {
+				writeFile(t, file, tt.contents, time.Now())
+			} else if err := os.Remove(file); err != nil {
+				t.Fatalf("Remove() error = %v", err)
+			}
+
+			if err := r.Reload(); (err != nil) != tt.wantErr {
+				t.Fatalf("Reload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for _, ca := range []testPair{oldCA, newCA} {
+				if got, want := trusts(t, pool, ca), ca.name() == tt.wantCA; got != want {
+					t.Errorf("pool trusts %s = %v, want %v", ca.name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReloaderMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	r := NewReloader()
+
+	if _, err := r.KeyPair(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")); err == nil {
+		t.Errorf("KeyPair() of missing files succeeded")
+	}
+	if _, err := r.CAPool(filepath.Join(dir, "ca.crt")); err == nil {
+		t.Errorf("CAPool() of a missing file succeeded")
+	}
+	if err := r.Reload(); err != nil {
+		t.Errorf("Reload() after failed loads error = %v, want nil", err)
+	}
+}
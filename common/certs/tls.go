@@ -0,0 +1,96 @@
+package certs
+
+import (
+	"crypto/tls"
+	"errors"
+	"tracerstudy-auth-service/common/config"
+)
+
+// ServerConfig returns the TLS config of the gRPC server, or nil when no
+// certificate is configured. The config of each handshake is built from
+// the key pair and client CAs as last loaded.
+func (r *Reloader) ServerConfig(cfg config.TLS) (*tls.Config, error) {
+	if cfg.CertFile == "" && cfg.KeyFile == "" {
+		if cfg.ClientCAFile != "" {
+			return nil, errors.New("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		return nil, nil
+	}
+
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	keyPair, err := r.KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var clientCAs *CAPool
+	clientAuth := tls.RequireAndVerifyClientCert
+	if cfg.ClientCAFile != "" {
+		if clientCAs, err = r.CAPool(cfg.ClientCAFile); err != nil {
+			return nil, err
+		}
+		if cfg.ClientAuthOptional {
+			clientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   []string{"h2"},
+				Certificates: []tls.Certificate{*keyPair.Certificate()},
+			}
+			if clientCAs != nil {
+				c.ClientAuth = clientAuth
+				c.ClientCAs = clientCAs.Pool()
+			}
+			return c, nil
+		},
+	}, nil
+}
+
+// ClientConfig returns a function building the TLS config for dialing a
+// service whose certificate is issued by a CA in caFile, or by a system
+// root when caFile is empty, for serverName, or the dialed host when it is
+// empty. The client certificate is presented when one is configured. Each
+// call uses the files as last loaded.
+func (r *Reloader) ClientConfig(cfg config.TLS, caFile, serverName string) (func() *tls.Config, error) {
+	if (cfg.ClientCertFile == "") != (cfg.ClientKeyFile == "") {
+		return nil, errors.New("TLS_CLIENT_CERT_FILE and TLS_CLIENT_KEY_FILE must be set together")
+	}
+
+	var keyPair *KeyPair
+	if cfg.ClientCertFile != "" {
+		var err error
+		if keyPair, err = r.KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var rootCAs *CAPool
+	if caFile != "" {
+		var err error
+		if rootCAs, err = r.CAPool(caFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return func() *tls.Config {
+		c := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ServerName: serverName,
+		}
+		if keyPair != nil {
+			c.Certificates = []tls.Certificate{*keyPair.Certificate()}
+		}
+		if rootCAs != nil {
+			c.RootCAs = rootCAs.Pool()
+		}
+		return c
+	}, nil
+}
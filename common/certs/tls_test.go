@@ -0,0 +1,159 @@
+package certs
+
+import (
+	"crypto/tls"
+	"path/filepath"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+)
+
+func TestReloaderServerConfig(t *testing.T) {
+	dir := t.TempDir()
+	pair := newTestPair(t, "auth.example.com")
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	writeFile(t, certFile, pair.cert, time.Now())
+	writeFile(t, keyFile, pair.key, time.Now())
+	writeFile(t, caFile, pair.cert, time.Now())
+
+	tests := []struct {
+		name           string
+		cfg            config.TLS
+		wantNil        bool
+		wantErr        bool
+		wantClientAuth tls.ClientAuthType
+	}{
+		{
+			name:    "plaintext",
+			wantNil: true,
+		},
+		{
+			name:           "TLS",
+			cfg:            config.TLS{CertFile: certFile, KeyFile: keyFile},
+			wantClientAuth: tls.NoClientCert,
+		},
+		{
+			name:           "mTLS",
+			cfg:            config.TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile},
+			wantClientAuth: tls.RequireAndVerifyClientCert,
+		},
+		{
+			name:           "optional client certificates",
+			cfg:            config.TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuthOptional: true},
+			wantClientAuth: tls.VerifyClientCertIfGiven,
+		},
+		{
+			name:    "certificate without key",
+			cfg:     config.TLS{CertFile: certFile},
+			wantNil: true,
+			wantErr: true,
+		},
+		{
+			name:    "client CA without certificate",
+			cfg:     config.TLS{ClientCAFile: caFile},
+			wantNil: true,
+			wantErr: true,
+		},
+		{
+			name:    "missing certificate",
+			cfg:     config.TLS{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile},
+			wantNil: true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewReloader().ServerConfig(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ServerConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("ServerConfig() = %v, wantNil %v", got, tt.wantNil)
+			}
+			if got == nil {
+				return
+			}
+
+			handshake, err := got.GetConfigForClient(&tls.ClientHelloInfo{})
+			if err != nil {
+				t.Fatalf("GetConfigForClient() error = %v", err)
+			}
+			if handshake.ClientAuth != tt.wantClientAuth {
+				t.Errorf("ClientAuth = %v, want %v", handshake.ClientAuth, tt.wantClientAuth)
+			}
+			if len(handshake.Certificates) != 1 || len(handshake.NextProtos) == 0 || handshake.NextProtos[0] != "h2" {
+				t.Errorf("handshake config = %+v, want the certificate and h2", handshake)
+			}
+		})
+	}
+}
+
+func TestReloaderClientConfig(t *testing.T) {
+	dir := t.TempDir()
+	pair := newTestPair(t, "gateway.example.com")
+	certFile, keyFile, caFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key"), filepath.Join(dir, "ca.crt")
+	writeFile(t, certFile, pair.cert, time.Now())
+	writeFile(t, keyFile, pair.key, time.Now())
+	writeFile(t, caFile, pair.cert, time.Now())
+
+	tests := []struct {
+		name            string
+		cfg             config.TLS
+		caFile          string
+		serverName      string
+		wantErr         bool
+		wantCertificate bool
+		wantRootCAs     bool
+	}{
+		{
+			name: "system roots",
+		},
+		{
+			name:        "CA bundle and server name",
+			caFile:      caFile,
+			serverName:  "auth.internal",
+			wantRootCAs: true,
+		},
+		{
+			name:            "client certificate",
+			cfg:             config.TLS{ClientCertFile: certFile, ClientKeyFile: keyFile},
+			caFile:          caFile,
+			wantCertificate: true,
+			wantRootCAs:     true,
+		},
+		{
+			name:    "client certificate without key",
+			cfg:     config.TLS{ClientCertFile: certFile},
+			wantErr: true,
+		},
+		{
+			name:    "missing CA bundle",
+			caFile:  filepath.Join(dir, "missing.crt"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newConfig, err := NewReloader().ClientConfig(tt.cfg, tt.caFile, tt.serverName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ClientConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got := newConfig()
+			if got.ServerName != tt.serverName {
+				t.Errorf("ServerName = %q, want %q", got.ServerName, tt.serverName)
+			}
+			if (len(got.Certificates) == 1) != tt.wantCertificate {
+				t.Errorf("Certificates = %d, want one %v", len(got.Certificates), tt.wantCertificate)
+			}
+			if (got.RootCAs != nil) != tt.wantRootCAs {
+				t.Errorf("RootCAs set = %v, want %v", got.RootCAs != nil, tt.wantRootCAs)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"net/url"
 	"time"
 
 	"github.com/joeshaw/envdecode"
@@ -14,6 +15,17 @@ type Config struct {
 	MySQL       MySQL
 	JWT         JWTConfig
 	ClientURL   ClientURL
+	ServiceAuth ServiceAuth
+	Policy      Policy
+	Mail        Mail
+	Password    Password
+	Throttle    Throttle
+	MFA         MFA
+	Email       EmailVerification
+	UserStudy   UserStudyLogin
+	Biodata     Biodata
+	Upstream    Upstream
+	TLS         TLS
 }
 
 type Port struct {
@@ -34,14 +46,176 @@ type ClientURL struct {
 	MhsBiodata string `env:"CLIENT_URL_MHSBIODATA"`
 }
 
+// ServiceAuth lists the client_id:secret pairs, comma separated, that other
+// tracer-study services authenticate with.
+type ServiceAuth struct {
+	Clients string `env:"SERVICE_CLIENTS"`
+}
+
+// Policy controls how long role permissions loaded from the database are
+// cached before they are read again.
+type Policy struct {
+	CacheTTL time.Duration `env:"POLICY_CACHE_TTL,default=1m"`
+}
+
+// Mail configures outgoing mail. Driver is smtp, which needs SMTPHost, or
+// for local development outbox, which appends messages to OutboxPath, or
+// log. The last two expose the links in messages, so they must be chosen
+// explicitly.
+type Mail struct {
+	Driver       string `env:"MAIL_DRIVER,default=smtp"`
+	SMTPHost     string `env:"MAIL_SMTP_HOST"`
+	SMTPPort     string `env:"MAIL_SMTP_PORT,default=587"`
+	SMTPUsername string `env:"MAIL_SMTP_USERNAME"`
+	SMTPPassword string `env:"MAIL_SMTP_PASSWORD"`
+	From         string `env:"MAIL_FROM,default=no-reply@tracer-study.local"`
+	OutboxPath   string `env:"MAIL_OUTBOX_PATH"`
+}
+
+// Password configures the password policy, password hashing and password
+// resets. CheckCommon rejects frequently used passwords of 8 or more
+// characters; shorter ones are left to MinLength. HashAlgorithm is argon2id
+// or bcrypt; hashes made with another algorithm or weaker parameters are
+// upgraded on the next login. ResetURL is the page that receives the reset
+// token as its token query parameter. Reset mails to one user are at least
+// ResetResendInterval apart.
+type Password struct {
+	MinLength           int           `env:"PASSWORD_MIN_LENGTH,default=8"`
+	MinCharClasses      int           `env:"PASSWORD_MIN_CHAR_CLASSES,default=3"`
+	CheckCommon         bool          `env:"PASSWORD_CHECK_COMMON,default=true"`
+	HashAlgorithm       string        `env:"PASSWORD_HASH_ALGORITHM,default=argon2id"`
+	Argon2Memory        uint32        `env:"PASSWORD_ARGON2_MEMORY,default=65536"`
+	Argon2Iterations    uint32        `env:"PASSWORD_ARGON2_ITERATIONS,default=3"`
+	Argon2Parallelism   uint8         `env:"PASSWORD_ARGON2_PARALLELISM,default=2"`
+	BcryptCost          int           `env:"PASSWORD_BCRYPT_COST,default=12"`
+	ResetURL            string        `env:"PASSWORD_RESET_URL"`
+	ResetTokenDuration  time.Duration `env:"PASSWORD_RESET_DURATION,default=30m"`
+	ResetResendInterval time.Duration `env:"PASSWORD_RESET_RESEND_INTERVAL,default=1m"`
+}
+
+// EmailVerification configures how users confirm their email address.
+// VerifyURL is the page that receives the token as its token query
+// parameter. Verification mails to one user are at least ResendInterval
+// apart. With RequiredForLogin, unverified users cannot log in.
+type EmailVerification struct {
+	VerifyURL        string        `env:"EMAIL_VERIFY_URL"`
+	TokenDuration    time.Duration `env:"EMAIL_VERIFY_DURATION,default=48h"`
+	ResendInterval   time.Duration `env:"EMAIL_VERIFY_RESEND_INTERVAL,default=1m"`
+	RequiredForLogin bool          `env:"EMAIL_VERIFY_REQUIRED_FOR_LOGIN,default=false"`
+}
+
+// UserStudyLogin configures login links for alumni employers. LoginURL is
+// the page that receives the token as its token query parameter. Links to
+// one email are at least ResendInterval apart. Without AllowDataLogin,
+// LoginUserStudy is disabled and employers can only log in by link. Tokens
+// embed up to MaxTokenNims alumni NIMs and reference a stored grant beyond
+// that.
+type UserStudyLogin struct {
+	LoginURL       string        `env:"USER_STUDY_LOGIN_URL"`
+	LinkDuration   time.Duration `env:"USER_STUDY_LOGIN_DURATION,default=15m"`
+	ResendInterval time.Duration `env:"USER_STUDY_LOGIN_RESEND_INTERVAL,default=1m"`
+	AllowDataLogin bool          `env:"USER_STUDY_ALLOW_DATA_LOGIN,default=true"`
+	MaxTokenNims   int           `env:"USER_STUDY_MAX_TOKEN_NIMS,default=50"`
+}
+
+// Biodata configures how alumni logins use the biodata API. Responses are
+// cached for CacheTTL, then served for up to StaleTTL more while they are
+// refreshed in the background. With DegradedLogin, alumni verified within
+// DegradedMaxAge can log in from their local record while the API is
+// unavailable.
+type Biodata struct {
+	CacheTTL       time.Duration `env:"BIODATA_CACHE_TTL,default=10m"`
+	StaleTTL       time.Duration `env:"BIODATA_STALE_TTL,default=1h"`
+	DegradedLogin  bool          `env:"BIODATA_DEGRADED_LOGIN,default=false"`
+	DegradedMaxAge time.Duration `env:"BIODATA_DEGRADED_MAX_AGE,default=720h"`
+}
+
+// Upstream configures calls to the PKTS and biodata services. Each attempt
+// gets the service's timeout, and attempts failing with Unavailable are
+// retried up to MaxRetries times after a jittered backoff growing from
+// RetryBaseDelay to RetryMaxDelay. BreakerThreshold consecutive failures
+// open a service's circuit breaker for BreakerCooldown.
+type Upstream struct {
+	PktsTimeout       time.Duration `env:"UPSTREAM_PKTS_TIMEOUT,default=3s"`
+	MhsBiodataTimeout time.Duration `env:"UPSTREAM_MHSBIODATA_TIMEOUT,default=3s"`
+	MaxRetries        int           `env:"UPSTREAM_MAX_RETRIES,default=2"`
+	RetryBaseDelay    time.Duration `env:"UPSTREAM_RETRY_BASE_DELAY,default=100ms"`
+	RetryMaxDelay     time.Duration `env:"UPSTREAM_RETRY_MAX_DELAY,default=1s"`
+	BreakerThreshold  uint32        `env:"UPSTREAM_BREAKER_THRESHOLD,default=5"`
+	BreakerCooldown   time.Duration `env:"UPSTREAM_BREAKER_COOLDOWN,default=30s"`
+}
+
+// TLS configures transport security. The gRPC server serves TLS when
+// CertFile and KeyFile are set, and with ClientCAFile also verifies client
+// certificates, requiring one unless ClientAuthOptional is set. Calls to
+// PKTS and the biodata API use TLS when enabled for them, verifying the
+// server against its CA bundle, or CAFile, or the system roots, and
+// presenting ClientCertFile when it is set. The service's connection to
+// itself verifies ServerName, a name in its own certificate, against
+// CAFile, and needs ClientCertFile when client certificates are required.
+// Changed files are reloaded every ReloadInterval.
+type TLS struct {
+	CertFile           string        `env:"TLS_CERT_FILE"`
+	KeyFile            string        `env:"TLS_KEY_FILE"`
+	ClientCAFile       string        `env:"TLS_CLIENT_CA_FILE"`
+	ClientAuthOptional bool          `env:"TLS_CLIENT_AUTH_OPTIONAL,default=false"`
+	CAFile             string        `env:"TLS_CA_FILE"`
+	ClientCertFile     string        `env:"TLS_CLIENT_CERT_FILE"`
+	ClientKeyFile      string        `env:"TLS_CLIENT_KEY_FILE"`
+	ServerName         string        `env:"TLS_SERVER_NAME"`
+	Pkts               bool          `env:"TLS_PKTS,default=false"`
+	PktsCAFile         string        `env:"TLS_PKTS_CA_FILE"`
+	MhsBiodata         bool          `env:"TLS_MHSBIODATA,default=false"`
+	MhsBiodataCAFile   string        `env:"TLS_MHSBIODATA_CA_FILE"`
+	ReloadInterval     time.Duration `env:"TLS_RELOAD_INTERVAL,default=30s"`
+}
+
+// Throttle configures login throttling. A key accrues failures until
+// ResetAfter passes without one. Past the free attempts each failure doubles
+// the wait from BaseDelay up to MaxDelay, and reaching the lockout threshold
+// blocks the key for LockoutDuration. Client addresses get higher limits,
+// since many users may share one. Store is memory or database; replicas
+// only share counters through the database.
+type Throttle struct {
+	Store                   string        `env:"THROTTLE_STORE,default=memory"`
+	BaseDelay               time.Duration `env:"THROTTLE_BASE_DELAY,default=1s"`
+	MaxDelay                time.Duration `env:"THROTTLE_MAX_DELAY,default=5m"`
+	LockoutDuration         time.Duration `env:"THROTTLE_LOCKOUT_DURATION,default=15m"`
+	ResetAfter              time.Duration `env:"THROTTLE_RESET_AFTER,default=1h"`
+	AccountFreeAttempts     uint32        `env:"THROTTLE_ACCOUNT_FREE_ATTEMPTS,default=3"`
+	AccountLockoutThreshold uint32        `env:"THROTTLE_ACCOUNT_LOCKOUT_THRESHOLD,default=10"`
+	ClientFreeAttempts      uint32        `env:"THROTTLE_CLIENT_FREE_ATTEMPTS,default=20"`
+	ClientLockoutThreshold  uint32        `env:"THROTTLE_CLIENT_LOCKOUT_THRESHOLD,default=100"`
+}
+
+// MFA configures TOTP two-factor authentication. RequiredRoles lists the
+// role ids, comma separated, whose users must enroll before they can log in.
+// A challenge allows MaxChallengeAttempts codes within ChallengeDuration.
+type MFA struct {
+	Issuer               string        `env:"MFA_ISSUER,default=Tracer Study"`
+	RequiredRoles        string        `env:"MFA_REQUIRED_ROLES"`
+	ChallengeDuration    time.Duration `env:"MFA_CHALLENGE_DURATION,default=5m"`
+	MaxChallengeAttempts uint32        `env:"MFA_MAX_CHALLENGE_ATTEMPTS,default=5"`
+}
+
 // type Redis struct {
 // 	Address  string `env:"REDIS_ADDRESS,required"`
 // 	Password string `env:"REDIS_PASSWORD"`
 // }
 
 type JWTConfig struct {
-	JwtSecretKey  string        `env:"JWT_SECRET_KEY"`
-	TokenDuration time.Duration `env:"JWT_DURATION,default=30m"`
+	JwtSecretKey         string        `env:"JWT_SECRET_KEY"`
+	SigningAlg           string        `env:"JWT_SIGNING_ALG,default=HS256"`
+	PrivateKeyPath       string        `env:"JWT_PRIVATE_KEY_PATH"`
+	KeyId                string        `env:"JWT_KEY_ID"`
+	KeyEncryptionKey     string        `env:"JWT_KEY_ENCRYPTION_KEY"`
+	Issuer               string        `env:"JWT_ISSUER,default=tracer-study-auth-service"`
+	Audience             string        `env:"JWT_AUDIENCE,default=tracer-study"`
+	Leeway               time.Duration `env:"JWT_LEEWAY,default=30s"`
+	TokenDuration        time.Duration `env:"JWT_DURATION,default=30m"`
+	RefreshTokenDuration time.Duration `env:"JWT_REFRESH_DURATION,default=168h"`
+	RevocationCacheTTL   time.Duration `env:"JWT_REVOCATION_CACHE_TTL,default=30s"`
+	KeyRefreshInterval   time.Duration `env:"JWT_KEY_REFRESH_INTERVAL,default=1m"`
 }
 
 func NewConfig(env string) (*Config, error) {
@@ -52,5 +226,59 @@ func NewConfig(env string) (*Config, error) {
 		return nil, errors.Wrap(err, "ERROR: [NewConfig] Error while decoding env")
 	}
 
+	if err := config.validate(); err != nil {
+		return nil, errors.Wrap(err, "ERROR: [NewConfig] Invalid config")
+	}
+
 	return &config, nil
 }
+
+// validate rejects values that would only fail once the service runs.
+func (c *Config) validate() error {
+	if c.JWT.KeyRefreshInterval <= 0 {
+		return errors.New("JWT_KEY_REFRESH_INTERVAL must be positive")
+	}
+
+	if c.TLS.ClientCAFile != "" && !c.TLS.ClientAuthOptional && c.TLS.ClientCertFile == "" {
+		return errors.New("TLS_CLIENT_CA_FILE requires TLS_CLIENT_CERT_FILE for the connection to this service itself")
+	}
+
+	if c.TLS.ReloadInterval <= 0 {
+		return errors.New("TLS_RELOAD_INTERVAL must be positive")
+	}
+
+	links := []struct {
+		name  string
+		value string
+	}{
+		{"PASSWORD_RESET_URL", c.Password.ResetURL},
+		{"EMAIL_VERIFY_URL", c.Email.VerifyURL},
+		{"USER_STUDY_LOGIN_URL", c.UserStudy.LoginURL},
+	}
+	for _, link := range links {
+		if err := validateLinkURL(link.value); err != nil {
+			return errors.Wrap(err, link.name)
+		}
+	}
+
+	return nil
+}
+
+// validateLinkURL checks a page URL that mailed tokens are appended to.
+// Without one, mails carry the bare token.
+func validateLinkURL(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an absolute http or https URL")
+	}
+
+	return nil
+}
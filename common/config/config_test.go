@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:    "zero key refresh interval",
+			modify:  func(c *Config) { c.JWT.KeyRefreshInterval = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "negative TLS reload interval",
+			modify:  func(c *Config) { c.TLS.ReloadInterval = -time.Second },
+			wantErr: true,
+		},
+		{
+			name:    "required client certificates without one for the connection to itself",
+			modify:  func(c *Config) { c.TLS.ClientCAFile = "ca.crt" },
+			wantErr: true,
+		},
+		{
+			name: "required client certificates with one for the connection to itself",
+			modify: func(c *Config) {
+				c.TLS.ClientCAFile = "ca.crt"
+				c.TLS.ClientCertFile = "client.crt"
+				c.TLS.ClientKeyFile = "client.key"
+			},
+		},
+		{
+			name:   "reset page URL",
+			modify: func(c *Config) { c.Password.ResetURL = "https://tracer.example.com/reset?lang=id" },
+		},
+		{
+			name:    "unparsable verify page URL",
+			modify:  func(c *Config) { c.Email.VerifyURL = "https://tracer.example.com/%zz" },
+			wantErr: true,
+		},
+		{
+			name:    "relative login page URL",
+			modify:  func(c *Config) { c.UserStudy.LoginURL = "/login" },
+			wantErr: true,
+		},
+		{
+			name: "optional client certificates",
+			modify: func(c *Config) {
+				c.TLS.ClientCAFile = "ca.crt"
+				c.TLS.ClientAuthOptional = true
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{
+				JWT: JWTConfig{KeyRefreshInterval: time.Minute},
+				TLS: TLS{ReloadInterval: 30 * time.Second},
+			}
+			if tt.modify != nil {
+				tt.modify(c)
+			}
+
+			if err := c.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -30,6 +31,11 @@ func (err *Error) Error() error {
 	return fmt.Errorf("%d:%s", err.Code, err.Message)
 }
 
+// HTTPStatus maps the gRPC code to the status reported in response bodies.
+func (err *Error) HTTPStatus() uint32 {
+	return uint32(runtime.HTTPStatusFromCode(err.Code))
+}
+
 func ParseError(err error) *Error {
 	var statusCode int32
 	if st, ok := status.FromError(err); ok {
@@ -80,3 +86,14 @@ func ParseError(err error) *Error {
 
 	return NewError(strToCode[int(statusCode)], outputmsg)
 }
+
+// Status returns err as a gRPC status error. Errors that already are one are
+// returned with their details, such as field violations, intact.
+func Status(err error) error {
+	if st, ok := status.FromError(err); ok {
+		return st.Err()
+	}
+
+	parseError := ParseError(err)
+	return status.Errorf(parseError.Code, parseError.Message)
+}
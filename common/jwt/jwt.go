@@ -1,85 +1,346 @@
 package jwt
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"sync"
 	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/utils"
 
 	"github.com/golang-jwt/jwt"
 )
 
+const (
+	tokenIdSize = 16
+)
+
 type JWT struct {
-	secretKey     string
-	tokenDuration time.Duration
+	bootstrapKey    *SigningKey
+	issuer          string
+	audience        string
+	tokenDuration   time.Duration
+	leeway          time.Duration
+	keyRefresh      time.Duration
+	revocationStore RevocationStore
+	mu              sync.RWMutex
+	keys            []*ManagedKey
 }
 
+// CustomClaims carries the registered claims alongside the caller's role
+// and scopes. Subject is a stable identifier built with the Subject helpers.
 type CustomClaims struct {
-	StandardClaims jwt.StandardClaims
-	Cred            string `json:"cred"`
-	Role           uint32 `json:"role"`
+	jwt.StandardClaims
+	Scopes
+	Role uint32 `json:"role"`
+}
+
+// Scopes are the study programs and faculties a user is assigned to, so
+// downstream services can limit the data they return to them. Employer
+// tokens instead carry the NIMs of the alumni they may evaluate, or the id
+// of a stored grant listing them when there are too many to embed.
+type Scopes struct {
+	Kodeprodi []string `json:"kodeprodi,omitempty"`
+	Kodefak   []string `json:"kodefak,omitempty"`
+	Nims      []string `json:"nims,omitempty"`
+	NimGrant  string   `json:"nim_grant,omitempty"`
 }
 
-func NewJWT(secretKey string, tokenDuration time.Duration) *JWT {
+// NewJWT signs with bootstrapKey until a key set is installed with SetKeys.
+// The bootstrap key also verifies tokens that carry no kid header.
+func NewJWT(cfg config.JWTConfig, bootstrapKey *SigningKey, revocationStore RevocationStore) *JWT {
+	now := time.Now()
 	return &JWT{
-		secretKey,
-		tokenDuration,
+		bootstrapKey:    bootstrapKey,
+		issuer:          cfg.Issuer,
+		audience:        cfg.Audience,
+		tokenDuration:   cfg.TokenDuration,
+		leeway:          cfg.Leeway,
+		keyRefresh:      cfg.KeyRefreshInterval,
+		revocationStore: revocationStore,
+		keys:            []*ManagedKey{{SigningKey: bootstrapKey, ActivatesAt: &now}},
+	}
+}
+
+func (j *JWT) BootstrapKey() *SigningKey {
+	return j.bootstrapKey
+}
+
+func (j *JWT) TokenDuration() time.Duration {
+	return j.tokenDuration
+}
+
+// SetKeys replaces the key set used for signing and verification.
+func (j *JWT) SetKeys(keys []*ManagedKey) {
+	j.mu.Lock()
+	j.keys = keys
+	j.mu.Unlock()
+}
+
+// ActiveKey returns the key that signs tokens at t: the most recently
+// activated key that has not been retired.
+func (j *JWT) ActiveKey(t time.Time) *SigningKey {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var active *ManagedKey
+	for _, k := range j.keys {
+		if k.signsAt(t) && (active == nil || k.ActivatesAt.After(*active.ActivatesAt)) {
+			active = k
+		}
+	}
+
+	if active == nil {
+		log.Println("WARNING: [JWT - ActiveKey] No active signing key, falling back to the bootstrap key")
+		return j.bootstrapKey
 	}
+
+	return active.SigningKey
 }
 
-func (j *JWT) GenerateToken(cred string, role uint32) (string, error) {
+func (j *JWT) GenerateToken(subject string, role uint32, scopes Scopes) (string, error) {
+	jti, err := utils.GenerateRandomToken(tokenIdSize)
+	if err != nil {
+		log.Println("ERROR: [JWT - GenerateToken] Error while generating token id:", err)
+		return "", err
+	}
+
+	now := time.Now()
 	claims := &CustomClaims{
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Local().Add(j.tokenDuration).Unix(),
+			Id:        jti,
+			Issuer:    j.issuer,
+			Audience:  j.audience,
+			Subject:   subject,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(j.tokenDuration).Unix(),
 		},
-		Cred:  cred,
-		Role: role,
+		Scopes: scopes,
+		Role:   role,
+	}
+
+	signingKey := j.ActiveKey(now)
+
+	token := jwt.NewWithClaims(signingKey.Method, claims)
+	token.Header["kid"] = signingKey.Kid
+	return token.SignedString(signingKey.PrivateKey)
+}
+
+func (j *JWT) Verify(ctx context.Context, accessToken string) (*CustomClaims, error) {
+	claims, err := j.Parse(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := j.IsRevoked(ctx, claims)
+	if err != nil {
+		log.Println("ERROR: [JWT - Verify] Error while checking token revocation:", err)
+		return nil, err
+	}
+
+	if revoked {
+		log.Println("WARNING: [JWT - Verify] Token has been revoked")
+		return nil, fmt.Errorf("token has been revoked")
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secretKey))
+	return claims, nil
 }
 
-func (j *JWT) Verify(accessToken string) (*CustomClaims, error) {
-	token, err := jwt.ParseWithClaims(
+// Parse checks the signature and claims of accessToken without consulting
+// the revocation store.
+func (j *JWT) Parse(accessToken string) (*CustomClaims, error) {
+	// claims are validated below, with leeway and the expected issuer and audience
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.ParseWithClaims(
 		accessToken,
 		&CustomClaims{},
-		func(token *jwt.Token) (interface{}, error) {
-			_, ok := token.Method.(*jwt.SigningMethodHMAC)
-			if !ok {
-				log.Println("ERROR: [JWT - Verify] Unexpected signing method")
-				return nil, fmt.Errorf("unexpected signing method")
-			}
-			return []byte(j.secretKey), nil
-		},
+		j.keyFunc,
 	)
 
 	if err != nil {
-		log.Println("ERROR: [JWT - Verify] Error while parsing token:", err)
+		log.Println("ERROR: [JWT - Parse] Error while parsing token:", err)
 		// return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
 		return nil, err
 	}
 
 	claims, ok := token.Claims.(*CustomClaims)
 	if !ok {
-		log.Println("ERROR: [JWT - Verify] Invalid token claims")
+		log.Println("ERROR: [JWT - Parse] Invalid token claims")
 		// return nil, status.Error(codes.Unauthenticated, "invalid token claims")
 		return nil, fmt.Errorf("invalid token claims")
 	}
 
-	if err := claims.Valid(); err != nil {
-		log.Println("ERROR: [JWT - Verify] Invalid token:", err)
+	if err := claims.validate(time.Now(), j.leeway, j.issuer, j.audience); err != nil {
+		log.Println("ERROR: [JWT - Parse] Invalid token:", err)
 		return nil, err
 	}
 
 	return claims, nil
 }
 
+// Jwks publishes the public keys tokens can be verified with, including
+// scheduled keys so verifiers can cache them before they start signing.
+func (j *JWT) Jwks() *JSONWebKeySet {
+	set := &JSONWebKeySet{Keys: []JSONWebKey{}}
+
+	for _, k := range j.verificationKeys(time.Now()) {
+		if k.IsSymmetric() {
+			continue
+		}
+
+		jwk, err := k.Jwk()
+		if err != nil {
+			log.Println("ERROR: [JWT - Jwks] Error while encoding public key:", err)
+			continue
+		}
+
+		set.Keys = append(set.Keys, *jwk)
+	}
+
+	return set
+}
+
+func (j *JWT) verificationKeys(t time.Time) []*SigningKey {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	// other replicas may sign with a retired key until their next reload,
+	// and their tokens are accepted for leeway past expiry
+	retirement := j.keyRefresh + j.tokenDuration + j.leeway
+
+	var keys []*SigningKey
+	for _, k := range j.keys {
+		if k.verifiesAt(t, retirement) {
+			keys = append(keys, k.SigningKey)
+		}
+	}
+
+	return keys
+}
+
+func (j *JWT) keyFunc(token *jwt.Token) (interface{}, error) {
+	// tokens issued before key ids were introduced carry no kid
+	kid, ok := token.Header["kid"]
+	if !ok {
+		kid = j.bootstrapKey.Kid
+	}
+
+	var key *SigningKey
+	for _, k := range j.verificationKeys(time.Now()) {
+		if k.Kid == kid {
+			key = k
+			break
+		}
+	}
+
+	if key == nil {
+		log.Println("ERROR: [JWT - Verify] Unknown key id:", kid)
+		return nil, fmt.Errorf("unknown key id")
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		log.Println("ERROR: [JWT - Verify] Unexpected signing method")
+		return nil, fmt.Errorf("unexpected signing method")
+	}
+
+	return key.verificationKey(), nil
+}
+
+// IsEmpty reports whether no prodi or faculty is assigned.
+func (s Scopes) IsEmpty() bool {
+	return len(s.Kodeprodi) == 0 && len(s.Kodefak) == 0
+}
+
+// IsRevoked reports whether the token itself was revoked or was issued
+// before every token of its subject was revoked.
+func (j *JWT) IsRevoked(ctx context.Context, claims *CustomClaims) (bool, error) {
+	if j.revocationStore == nil {
+		return false, nil
+	}
+
+	revoked, err := j.revocationStore.IsTokenRevoked(ctx, claims.Id)
+	if err != nil || revoked {
+		return revoked, err
+	}
+
+	return j.IsSubjectRevoked(ctx, claims.Subject, time.Unix(claims.IssuedAt, 0))
+}
+
+// IsSubjectRevoked reports whether credentials issued to subject at issuedAt
+// were invalidated by a later call to RevokeSubject.
+func (j *JWT) IsSubjectRevoked(ctx context.Context, subject string, issuedAt time.Time) (bool, error) {
+	if j.revocationStore == nil {
+		return false, nil
+	}
+
+	revokedAt, err := j.revocationStore.SubjectRevokedAt(ctx, subject)
+	if err != nil {
+		return false, err
+	}
+
+	return !revokedAt.IsZero() && issuedAt.Unix() <= revokedAt.Unix(), nil
+}
+
+func (j *JWT) RevokeToken(ctx context.Context, claims *CustomClaims) error {
+	if j.revocationStore == nil {
+		return fmt.Errorf("token revocation is not configured")
+	}
+
+	// the revocation is needed until the token fails verification anyway
+	return j.revocationStore.RevokeToken(ctx, claims.Id, claims.Subject, time.Unix(claims.ExpiresAt, 0).Add(j.leeway))
+}
+
+// RevokeSubject invalidates every token issued to subject before the current
+// second. Issue times only have second precision, so a token issued in the
+// same second, such as by a login straight after a password reset, has to
+// be assumed to come after the revocation.
+func (j *JWT) RevokeSubject(ctx context.Context, subject string) error {
+	return j.RevokeSubjectBefore(ctx, subject, time.Now())
+}
+
+// RevokeSubjectBefore invalidates every token issued to subject before the
+// second t falls in. Tokens issued at t itself stay valid, which lets a
+// caller revoke its other sessions and then issue itself a new token.
+func (j *JWT) RevokeSubjectBefore(ctx context.Context, subject string, t time.Time) error {
+	if j.revocationStore == nil {
+		return fmt.Errorf("token revocation is not configured")
+	}
+
+	return j.revocationStore.RevokeSubject(ctx, subject, t.Truncate(time.Second).Add(-time.Second))
+}
+
+// Valid checks the time based claims without leeway. Verify applies the
+// configured leeway, issuer and audience instead.
 func (c *CustomClaims) Valid() error {
-	// check if the token has expired.
-	if time.Now().Unix() > c.StandardClaims.ExpiresAt {
-		// return status.Error(codes.Unauthenticated, "token has expired")
+	return c.validate(time.Now(), 0, "", "")
+}
+
+func (c *CustomClaims) validate(now time.Time, leeway time.Duration, issuer, audience string) error {
+	if c.ExpiresAt == 0 || now.Add(-leeway).Unix() > c.ExpiresAt {
 		return fmt.Errorf("token has expired")
 	}
 
+	if c.NotBefore != 0 && now.Add(leeway).Unix() < c.NotBefore {
+		return fmt.Errorf("token is not valid yet")
+	}
+
+	if c.IssuedAt != 0 && now.Add(leeway).Unix() < c.IssuedAt {
+		return fmt.Errorf("token used before issued")
+	}
+
+	if issuer != "" && !c.VerifyIssuer(issuer, true) {
+		return fmt.Errorf("token issuer is invalid")
+	}
+
+	if audience != "" && !c.VerifyAudience(audience, true) {
+		return fmt.Errorf("token audience is invalid")
+	}
+
+	if c.Subject == "" {
+		return fmt.Errorf("token subject is missing")
+	}
+
 	return nil
 }
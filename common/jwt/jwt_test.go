@@ -0,0 +1,289 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+
+	"github.com/golang-jwt/jwt"
+)
+
+type fakeRevocationStore struct {
+	tokens   map[string]time.Time
+	subjects map[string]time.Time
+}
+
+func newFakeRevocationStore() *fakeRevocationStore {
+	return &fakeRevocationStore{
+		tokens:   make(map[string]time.Time),
+		subjects: make(map[string]time.Time),
+	}
+}
+
+func (s *fakeRevocationStore) RevokeToken(ctx context.Context, jti, subject string, expiresAt time.Time) error {
+	s.tokens[jti] = expiresAt
+	return nil
+}
+
+func (s *fakeRevocationStore) RevokeSubject(ctx context.Context, subject string, revokedAt time.Time) error {
+	s.subjects[subject] = revokedAt
+	return nil
+}
+
+func (s *fakeRevocationStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	_, ok := s.tokens[jti]
+	return ok, nil
+}
+
+func (s *fakeRevocationStore) SubjectRevokedAt(ctx context.Context, subject string) (time.Time, error) {
+	return s.subjects[subject], nil
+}
+
+func newTestJWT(store RevocationStore) *JWT {
+	cfg := config.JWTConfig{
+		Issuer:        "issuer",
+		Audience:      "audience",
+		TokenDuration: time.Minute,
+		Leeway:        30 * time.Second,
+	}
+	return NewJWT(cfg, NewHMACSigningKey("", []byte("secret")), store)
+}
+
+func TestJWTIsSubjectRevoked(t *testing.T) {
+	const subject = "user:1"
+	ctx := context.Background()
+	revokedAt := time.Date(2024, 1, 1, 10, 0, 0, 500_000_000, time.UTC)
+
+	tests := []struct {
+		name     string
+		revoke   func(*JWT) error
+		issuedAt time.Time
+		want     bool
+	}{
+		{
+			name:     "not revoked",
+			revoke:   func(*JWT) error { return nil },
+			issuedAt: revokedAt,
+			want:     false,
+		},
+		{
+			name:     "revoke before keeps tokens issued in the same second",
+			revoke:   func(j *JWT) error { return j.RevokeSubjectBefore(ctx, subject, revokedAt) },
+			issuedAt: revokedAt.Truncate(time.Second),
+			want:     false,
+		},
+		{
+			name:     "revoke before keeps later tokens",
+			revoke:   func(j *JWT) error { return j.RevokeSubjectBefore(ctx, subject, revokedAt) },
+			issuedAt: revokedAt.Add(time.Second),
+			want:     false,
+		},
+		{
+			name:     "revoke before revokes tokens of the previous second",
+			revoke:   func(j *JWT) error { return j.RevokeSubjectBefore(ctx, subject, revokedAt) },
+			issuedAt: revokedAt.Add(-time.Second),
+			want:     true,
+		},
+		{
+			name:     "revoke revokes tokens of the previous second",
+			revoke:   func(j *JWT) error { return j.RevokeSubject(ctx, subject) },
+			issuedAt: time.Now().Add(-time.Second),
+			want:     true,
+		},
+		{
+			name:     "revoke keeps tokens issued afterwards",
+			revoke:   func(j *JWT) error { return j.RevokeSubject(ctx, subject) },
+			issuedAt: time.Now().Add(time.Second),
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := newTestJWT(newFakeRevocationStore())
+			if err := tt.revoke(j); err != nil {
+				t.Fatalf("revoke error = %v", err)
+			}
+
+			got, err := j.IsSubjectRevoked(ctx, subject, tt.issuedAt)
+			if err != nil {
+				t.Fatalf("IsSubjectRevoked() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsSubjectRevoked() = %v, want %v", got, tt.want)
+			}
+
+			other, err := j.IsSubjectRevoked(ctx, "user:2", tt.issuedAt)
+			if err != nil || other {
+				t.Errorf("IsSubjectRevoked() of another subject = %v, %v, want false", other, err)
+			}
+		})
+	}
+}
+
+func TestJWTVerifyRevokedToken(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeRevocationStore()
+	j := newTestJWT(store)
+
+	token, err := j.GenerateToken("user:1", 1, Scopes{})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	claims, err := j.Verify(ctx, token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	if err := j.RevokeToken(ctx, claims); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+
+	// the revocation must outlive the leeway the token is still accepted in
+	if got, want := store.tokens[claims.Id], time.Unix(claims.ExpiresAt, 0).Add(j.leeway); !got.Equal(want) {
+		t.Errorf("revocation expires at %v, want %v", got, want)
+	}
+
+	if _, err := j.Verify(ctx, token); err == nil {
+		t.Errorf("Verify() of a revoked token succeeded")
+	}
+
+	if _, err := j.Parse(token); err != nil {
+		t.Errorf("Parse() of a revoked token error = %v, want nil", err)
+	}
+}
+
+func TestJWTVerifyAfterSubjectRevoked(t *testing.T) {
+	ctx := context.Background()
+	j := newTestJWT(newFakeRevocationStore())
+
+	// a password reset followed straight away by a login
+	if err := j.RevokeSubject(ctx, "user:1"); err != nil {
+		t.Fatalf("RevokeSubject() error = %v", err)
+	}
+
+	token, err := j.GenerateToken("user:1", 1, Scopes{})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	if _, err := j.Verify(ctx, token); err != nil {
+		t.Errorf("Verify() of a token issued after the revocation error = %v", err)
+	}
+}
+
+func TestCustomClaimsValidate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	leeway := 30 * time.Second
+
+	valid := func() *CustomClaims {
+		return &CustomClaims{StandardClaims: jwt.StandardClaims{
+			Issuer:    "issuer",
+			Audience:  "audience",
+			Subject:   "user:1",
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(time.Minute).Unix(),
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*CustomClaims)
+		wantErr string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name:   "expired within the leeway",
+			modify: func(c *CustomClaims) { c.ExpiresAt = now.Add(-20 * time.Second).Unix() },
+		},
+		{
+			name:    "expired beyond the leeway",
+			modify:  func(c *CustomClaims) { c.ExpiresAt = now.Add(-time.Minute).Unix() },
+			wantErr: "token has expired",
+		},
+		{
+			name:    "no expiry",
+			modify:  func(c *CustomClaims) { c.ExpiresAt = 0 },
+			wantErr: "token has expired",
+		},
+		{
+			name:   "not valid yet within the leeway",
+			modify: func(c *CustomClaims) { c.NotBefore = now.Add(20 * time.Second).Unix() },
+		},
+		{
+			name:    "not valid yet beyond the leeway",
+			modify:  func(c *CustomClaims) { c.NotBefore = now.Add(time.Minute).Unix() },
+			wantErr: "token is not valid yet",
+		},
+		{
+			name:    "issued in the future",
+			modify:  func(c *CustomClaims) { c.IssuedAt = now.Add(time.Minute).Unix() },
+			wantErr: "token used before issued",
+		},
+		{
+			name:    "other issuer",
+			modify:  func(c *CustomClaims) { c.Issuer = "other" },
+			wantErr: "token issuer is invalid",
+		},
+		{
+			name:    "other audience",
+			modify:  func(c *CustomClaims) { c.Audience = "pkts" },
+			wantErr: "token audience is invalid",
+		},
+		{
+			name:    "no audience",
+			modify:  func(c *CustomClaims) { c.Audience = "" },
+			wantErr: "token audience is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+
+			err := claims.validate(now, leeway, "issuer", "audience")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTParseRegisteredClaims(t *testing.T) {
+	issuer := newTestJWT(nil)
+	token, err := issuer.GenerateToken("user:1", 1, Scopes{Kodeprodi: []string{"0101"}})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	claims, err := issuer.Parse(token)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if claims.Id == "" || claims.Subject != "user:1" || claims.Issuer != "issuer" || claims.Audience != "audience" {
+		t.Errorf("Parse() claims = %+v, want jti, sub, iss and aud", claims.StandardClaims)
+	}
+	if claims.IssuedAt == 0 || claims.NotBefore != claims.IssuedAt || claims.ExpiresAt != claims.IssuedAt+60 {
+		t.Errorf("Parse() times = iat %d, nbf %d, exp %d, want a one minute token", claims.IssuedAt, claims.NotBefore, claims.ExpiresAt)
+	}
+
+	other := newTestJWT(nil)
+	other.audience = "pkts"
+	if _, err := other.Parse(token); err == nil {
+		t.Errorf("Parse() for another audience succeeded")
+	}
+}
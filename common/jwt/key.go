@@ -0,0 +1,205 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+
+	defaultHMACKeyId = "hs256"
+)
+
+// SigningKey is a key the JWT manager signs or verifies tokens with,
+// identified in token headers by Kid.
+type SigningKey struct {
+	Kid        string
+	Method     jwt.SigningMethod
+	PrivateKey interface{}
+	PublicKey  crypto.PublicKey
+}
+
+func NewHMACSigningKey(kid string, secret []byte) *SigningKey {
+	if kid == "" {
+		kid = defaultHMACKeyId
+	}
+
+	return &SigningKey{
+		Kid:        kid,
+		Method:     jwt.SigningMethodHS256,
+		PrivateKey: secret,
+	}
+}
+
+// ParseSigningKey builds an asymmetric signing key from a PEM encoded private
+// key. When kid is empty the RFC 7638 thumbprint of the public key is used.
+func ParseSigningKey(alg, kid string, privateKeyPEM []byte) (*SigningKey, error) {
+	key := &SigningKey{Kid: kid}
+
+	switch alg {
+	case AlgRS256:
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privateKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		key.Method, key.PrivateKey, key.PublicKey = jwt.SigningMethodRS256, privateKey, &privateKey.PublicKey
+	case AlgES256:
+		privateKey, err := jwt.ParseECPrivateKeyFromPEM(privateKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		if privateKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ES256 requires a P-256 key")
+		}
+		key.Method, key.PrivateKey, key.PublicKey = jwt.SigningMethodES256, privateKey, &privateKey.PublicKey
+	case AlgEdDSA:
+		parsed, err := jwt.ParseEdPrivateKeyFromPEM(privateKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("EdDSA requires an Ed25519 key")
+		}
+		key.Method, key.PrivateKey, key.PublicKey = jwt.SigningMethodEdDSA, privateKey, privateKey.Public()
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+
+	if key.Kid == "" {
+		thumbprint, err := key.Thumbprint()
+		if err != nil {
+			return nil, err
+		}
+		key.Kid = thumbprint
+	}
+
+	return key, nil
+}
+
+// LoadSigningKey returns the HMAC key for HS256, otherwise it reads the
+// private key of alg from privateKeyPath.
+func LoadSigningKey(alg, kid, secretKey, privateKeyPath string) (*SigningKey, error) {
+	if alg == "" || alg == AlgHS256 {
+		return NewHMACSigningKey(kid, []byte(secretKey)), nil
+	}
+
+	privateKeyPEM, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		log.Println("ERROR: [JWT - LoadSigningKey] Error while reading private key:", err)
+		return nil, err
+	}
+
+	return ParseSigningKey(alg, kid, privateKeyPEM)
+}
+
+func (k *SigningKey) IsSymmetric() bool {
+	_, ok := k.PrivateKey.([]byte)
+	return ok
+}
+
+func (k *SigningKey) verificationKey() interface{} {
+	if k.IsSymmetric() {
+		return k.PrivateKey
+	}
+	return k.PublicKey
+}
+
+// Jwk returns the public half of the key. Symmetric keys have no public
+// representation and are never published.
+func (k *SigningKey) Jwk() (*JSONWebKey, error) {
+	jwk := &JSONWebKey{
+		Kid: k.Kid,
+		Use: "sig",
+		Alg: k.Method.Alg(),
+	}
+
+	switch publicKey := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeSegment(publicKey.N.Bytes())
+		jwk.E = encodeSegment(bigEndian(uint64(publicKey.E)))
+	case *ecdsa.PublicKey:
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = publicKey.Curve.Params().Name
+		jwk.X = encodeSegment(publicKey.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeSegment(publicKey.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encodeSegment(publicKey)
+	default:
+		return nil, fmt.Errorf("key %s has no public representation", k.Kid)
+	}
+
+	return jwk, nil
+}
+
+// Thumbprint computes the RFC 7638 JWK thumbprint of the public key.
+func (k *SigningKey) Thumbprint() (string, error) {
+	jwk, err := k.Jwk()
+	if err != nil {
+		return "", err
+	}
+
+	// members must be serialized in lexicographic order
+	var members interface{}
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	encoded, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(encoded)
+	return encodeSegment(sum[:]), nil
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func bigEndian(v uint64) []byte {
+	var b []byte
+	for v > 0 {
+		b = append([]byte{byte(v)}, b...)
+		v >>= 8
+	}
+	return b
+}
@@ -0,0 +1,206 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func TestSigningKeyThumbprint(t *testing.T) {
+	// the examples of RFC 7638 section 3.1 and RFC 8037 appendix A.3
+	n, _ := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	x, _ := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+
+	tests := []struct {
+		name    string
+		key     *SigningKey
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "RSA",
+			key:  &SigningKey{Method: jwt.SigningMethodRS256, PublicKey: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}},
+			want: "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+		},
+		{
+			name: "Ed25519",
+			key:  &SigningKey{Method: jwt.SigningMethodEdDSA, PublicKey: ed25519.PublicKey(x)},
+			want: "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+		},
+		{
+			name:    "HMAC",
+			key:     NewHMACSigningKey("", []byte("secret")),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.key.Thumbprint()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Thumbprint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Thumbprint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSigningKey(t *testing.T) {
+	tests := []struct {
+		alg      string
+		otherAlg string
+	}{
+		{alg: AlgRS256, otherAlg: AlgES256},
+		{alg: AlgES256, otherAlg: AlgEdDSA},
+		{alg: AlgEdDSA, otherAlg: AlgRS256},
+	}
+
+	for _, tt := range tests {
+		alg := tt.alg
+		t.Run(alg, func(t *testing.T) {
+			generated, privateKeyPEM, err := GenerateSigningKey(alg)
+			if err != nil {
+				t.Fatalf("GenerateSigningKey() error = %v", err)
+			}
+
+			key, err := ParseSigningKey(alg, "", privateKeyPEM)
+			if err != nil {
+				t.Fatalf("ParseSigningKey() error = %v", err)
+			}
+			thumbprint, err := key.Thumbprint()
+			if err != nil {
+				t.Fatalf("Thumbprint() error = %v", err)
+			}
+			if key.Kid != thumbprint || key.Kid != generated.Kid {
+				t.Errorf("kid = %q, want the thumbprint %q", key.Kid, thumbprint)
+			}
+			if key.Method.Alg() != alg {
+				t.Errorf("alg = %q, want %q", key.Method.Alg(), alg)
+			}
+
+			named, err := ParseSigningKey(alg, "named", privateKeyPEM)
+			if err != nil || named.Kid != "named" {
+				t.Errorf("ParseSigningKey() with a kid = %v, %v, want kid %q", named, err, "named")
+			}
+
+			if _, err := ParseSigningKey(tt.otherAlg, "", privateKeyPEM); err == nil {
+				t.Errorf("ParseSigningKey() accepted a %s key as %s", alg, tt.otherAlg)
+			}
+		})
+	}
+}
+
+func TestJWTKeyFunc(t *testing.T) {
+	rsaKey, _, err := GenerateSigningKey(AlgRS256)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	edKey, _, err := GenerateSigningKey(AlgEdDSA)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	unknownKey, _, err := GenerateSigningKey(AlgEdDSA)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	rsaPublicDER, err := x509.MarshalPKIXPublicKey(rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+	}
+
+	j := newTestJWT(nil)
+	now := time.Now()
+	j.SetKeys([]*ManagedKey{
+		{SigningKey: j.BootstrapKey(), ActivatesAt: &now},
+		{SigningKey: rsaKey, ActivatesAt: &now},
+		{SigningKey: edKey, ActivatesAt: &now},
+	})
+
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		kid     interface{}
+		key     interface{}
+		wantErr bool
+	}{
+		{
+			name:   "RS256 key selected by kid",
+			method: jwt.SigningMethodRS256,
+			kid:    rsaKey.Kid,
+			key:    rsaKey.PrivateKey,
+		},
+		{
+			name:   "EdDSA key selected by kid",
+			method: jwt.SigningMethodEdDSA,
+			kid:    edKey.Kid,
+			key:    edKey.PrivateKey,
+		},
+		{
+			name:   "missing kid falls back to the bootstrap key",
+			method: jwt.SigningMethodHS256,
+			key:    j.BootstrapKey().PrivateKey,
+		},
+		{
+			name:    "unknown kid",
+			method:  jwt.SigningMethodEdDSA,
+			kid:     unknownKey.Kid,
+			key:     unknownKey.PrivateKey,
+			wantErr: true,
+		},
+		{
+			name:    "kid of another key",
+			method:  jwt.SigningMethodEdDSA,
+			kid:     edKey.Kid,
+			key:     unknownKey.PrivateKey,
+			wantErr: true,
+		},
+		{
+			name:    "HS256 signed with the public key of an RS256 kid",
+			method:  jwt.SigningMethodHS256,
+			kid:     rsaKey.Kid,
+			key:     rsaPublicDER,
+			wantErr: true,
+		},
+		{
+			name:    "alg of another key",
+			method:  jwt.SigningMethodEdDSA,
+			kid:     rsaKey.Kid,
+			key:     edKey.PrivateKey,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.NewWithClaims(tt.method, &CustomClaims{
+				StandardClaims: jwt.StandardClaims{
+					Issuer:    j.issuer,
+					Audience:  j.audience,
+					Subject:   "user:1",
+					IssuedAt:  now.Unix(),
+					ExpiresAt: now.Add(time.Minute).Unix(),
+				},
+			})
+			if tt.kid != nil {
+				token.Header["kid"] = tt.kid
+			}
+			signed, err := token.SignedString(tt.key)
+			if err != nil {
+				t.Fatalf("SignedString() error = %v", err)
+			}
+
+			_, err = j.Parse(signed)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
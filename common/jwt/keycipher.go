@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const (
+	keyEncryptionKeySize = 32
+	sealedKeyPrefix      = "enc:v1:"
+)
+
+// KeyCipher encrypts the private keys of the key set for storage with
+// AES-256-GCM. Each key is bound to its kid, so a sealed key copied to
+// another row does not open.
+type KeyCipher struct {
+	aead cipher.AEAD
+}
+
+// NewKeyCipher takes a base64 encoded 32 byte key. It returns nil without
+// error when encodedKey is empty.
+func NewKeyCipher(encodedKey string) (*KeyCipher, error) {
+	if encodedKey == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != keyEncryptionKeySize {
+		return nil, fmt.Errorf("key encryption key must be %d base64 encoded bytes", keyEncryptionKeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyCipher{
+		aead: aead,
+	}, nil
+}
+
+// IsSealedKey reports whether a stored private key was encrypted by Seal.
+func IsSealedKey(stored string) bool {
+	return strings.HasPrefix(stored, sealedKeyPrefix)
+}
+
+func (c *KeyCipher) Seal(kid string, privateKeyPEM []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, privateKeyPEM, []byte(kid))
+	return sealedKeyPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *KeyCipher) Open(kid, stored string) ([]byte, error) {
+	if !IsSealedKey(stored) {
+		return nil, fmt.Errorf("private key is not sealed")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, sealedKeyPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < c.aead.NonceSize() {
+		return nil, fmt.Errorf("sealed private key is too short")
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	return c.aead.Open(nil, nonce, ciphertext, []byte(kid))
+}
@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+const (
+	rsaKeyBits = 2048
+)
+
+// ManagedKey is a signing key with its rotation schedule. A key without
+// ActivatesAt is published for verification but never signs until promoted.
+type ManagedKey struct {
+	*SigningKey
+	ActivatesAt *time.Time
+	RetiredAt   *time.Time
+}
+
+// signsAt reports whether the key is eligible to sign tokens at t.
+func (k *ManagedKey) signsAt(t time.Time) bool {
+	return k.RetiredAt == nil && k.ActivatesAt != nil && !k.ActivatesAt.After(t)
+}
+
+// verifiesAt reports whether tokens signed by the key may still be valid at
+// t. Retired keys keep verifying for retirement, until the last token they
+// signed expires.
+func (k *ManagedKey) verifiesAt(t time.Time, retirement time.Duration) bool {
+	return k.RetiredAt == nil || t.Before(k.RetiredAt.Add(retirement))
+}
+
+// GenerateSigningKey creates a new asymmetric key for alg and returns it
+// together with its PKCS #8 PEM encoding for persistence.
+func GenerateSigningKey(alg string) (*SigningKey, []byte, error) {
+	var privateKey interface{}
+	var err error
+
+	switch alg {
+	case AlgRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	key, err := ParseSigningKey(alg, "", privateKeyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, privateKeyPEM, nil
+}
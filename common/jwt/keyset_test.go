@@ -0,0 +1,202 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJWTKeyRotation(t *testing.T) {
+	now := time.Now()
+	hourAgo := now.Add(-time.Hour)
+	minuteAgo := now.Add(-time.Minute)
+	elevenMinutesAgo := now.Add(-11 * time.Minute)
+	later := now.Add(time.Hour)
+
+	current := NewHMACSigningKey("current", []byte("current"))
+	previous := NewHMACSigningKey("previous", []byte("previous"))
+
+	tests := []struct {
+		name         string
+		keys         []*ManagedKey
+		wantActive   string
+		wantVerifies []string
+	}{
+		{
+			name: "most recently activated key signs",
+			keys: []*ManagedKey{
+				{SigningKey: previous, ActivatesAt: &hourAgo},
+				{SigningKey: current, ActivatesAt: &minuteAgo},
+			},
+			wantActive:   "current",
+			wantVerifies: []string{"previous", "current"},
+		},
+		{
+			name: "scheduled key is published but does not sign",
+			keys: []*ManagedKey{
+				{SigningKey: previous, ActivatesAt: &hourAgo},
+				{SigningKey: current, ActivatesAt: &later},
+			},
+			wantActive:   "previous",
+			wantVerifies: []string{"previous", "current"},
+		},
+		{
+			name: "pending key is published but does not sign",
+			keys: []*ManagedKey{
+				{SigningKey: previous, ActivatesAt: &hourAgo},
+				{SigningKey: current},
+			},
+			wantActive:   "previous",
+			wantVerifies: []string{"previous", "current"},
+		},
+		{
+			name: "retired key verifies until its tokens expire",
+			keys: []*ManagedKey{
+				{SigningKey: previous, ActivatesAt: &hourAgo, RetiredAt: &minuteAgo},
+				{SigningKey: current, ActivatesAt: &minuteAgo},
+			},
+			wantActive:   "current",
+			wantVerifies: []string{"previous", "current"},
+		},
+		{
+			name: "retired key verifies through the leeway and key refresh",
+			keys: []*ManagedKey{
+				{SigningKey: previous, ActivatesAt: &hourAgo, RetiredAt: &elevenMinutesAgo},
+				{SigningKey: current, ActivatesAt: &elevenMinutesAgo},
+			},
+			wantActive:   "current",
+			wantVerifies: []string{"previous", "current"},
+		},
+		{
+			name: "retired key stops verifying once its tokens expired",
+			keys: []*ManagedKey{
+				{SigningKey: previous, ActivatesAt: &hourAgo, RetiredAt: &hourAgo},
+				{SigningKey: current, ActivatesAt: &hourAgo},
+			},
+			wantActive:   "current",
+			wantVerifies: []string{"current"},
+		},
+		{
+			name: "bootstrap key signs when no key is active",
+			keys: []*ManagedKey{
+				{SigningKey: current, ActivatesAt: &later},
+			},
+			wantActive:   defaultHMACKeyId,
+			wantVerifies: []string{"current"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// tokens last ten minutes, with 30s leeway and keys reloaded
+			// every minute
+			j := newTestJWT(nil)
+			j.tokenDuration = 10 * time.Minute
+			j.keyRefresh = time.Minute
+			j.SetKeys(tt.keys)
+
+			if got := j.ActiveKey(now).Kid; got != tt.wantActive {
+				t.Errorf("ActiveKey() = %q, want %q", got, tt.wantActive)
+			}
+
+			var verifies []string
+			for _, k := range j.verificationKeys(now) {
+				verifies = append(verifies, k.Kid)
+			}
+			if len(verifies) != len(tt.wantVerifies) {
+				t.Fatalf("verification keys = %v, want %v", verifies, tt.wantVerifies)
+			}
+			for i := range verifies {
+				if verifies[i] != tt.wantVerifies[i] {
+					t.Errorf("verification keys = %v, want %v", verifies, tt.wantVerifies)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestJWTVerifyAfterRotation(t *testing.T) {
+	previous, _, err := GenerateSigningKey(AlgEdDSA)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	current, _, err := GenerateSigningKey(AlgEdDSA)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+
+	j := newTestJWT(nil)
+	hourAgo := time.Now().Add(-time.Hour)
+	j.SetKeys([]*ManagedKey{{SigningKey: previous, ActivatesAt: &hourAgo}})
+
+	token, err := j.GenerateToken("user:1", 1, Scopes{})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	now := time.Now()
+	j.SetKeys([]*ManagedKey{
+		{SigningKey: previous, ActivatesAt: &hourAgo, RetiredAt: &now},
+		{SigningKey: current, ActivatesAt: &now},
+	})
+
+	if _, err := j.Parse(token); err != nil {
+		t.Errorf("Parse() of a token signed by the retired key error = %v", err)
+	}
+
+	if len(j.Jwks().Keys) != 2 {
+		t.Errorf("Jwks() published %d keys, want 2", len(j.Jwks().Keys))
+	}
+
+	retired := now.Add(-j.tokenDuration - j.leeway)
+	j.SetKeys([]*ManagedKey{
+		{SigningKey: previous, ActivatesAt: &hourAgo, RetiredAt: &retired},
+		{SigningKey: current, ActivatesAt: &now},
+	})
+
+	if _, err := j.Parse(token); err == nil {
+		t.Errorf("Parse() of a token signed by an expired key succeeded")
+	}
+}
+
+func TestJWTVerifyFromStaleReplica(t *testing.T) {
+	previous := NewHMACSigningKey("previous", []byte("previous"))
+	current := NewHMACSigningKey("current", []byte("current"))
+
+	j := newTestJWT(nil)
+	j.keyRefresh = time.Minute
+	hourAgo := time.Now().Add(-time.Hour)
+
+	// another replica had not reloaded its keys yet and signed with the
+	// previous key almost a refresh interval after it was retired
+	signedAt := time.Now()
+	retired := signedAt.Add(-j.keyRefresh + time.Second)
+	j.SetKeys([]*ManagedKey{
+		{SigningKey: previous, ActivatesAt: &hourAgo, RetiredAt: &retired},
+		{SigningKey: current, ActivatesAt: &retired},
+	})
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{name: "while the token is valid", at: signedAt.Add(j.tokenDuration - time.Second), want: true},
+		{name: "within the leeway", at: signedAt.Add(j.tokenDuration + j.leeway - time.Second), want: true},
+		{name: "after the leeway", at: signedAt.Add(j.tokenDuration + j.leeway + time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			for _, k := range j.verificationKeys(tt.at) {
+				if k.Kid == previous.Kid {
+					got = true
+				}
+			}
+			if got != tt.want {
+				t.Errorf("previous key verifies = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	maxCachedLookups = 10000
+)
+
+type RevocationStore interface {
+	RevokeToken(ctx context.Context, jti, subject string, expiresAt time.Time) error
+	RevokeSubject(ctx context.Context, subject string, revokedAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	SubjectRevokedAt(ctx context.Context, subject string) (time.Time, error)
+}
+
+type cachedLookup struct {
+	revoked   bool
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+// CachedRevocationStore keeps revocation lookups in memory so Verify does not
+// hit the backing store on every request. Revocations made through this
+// store are visible immediately; revocations made by other replicas become
+// visible once the cached lookup expires.
+type CachedRevocationStore struct {
+	store    RevocationStore
+	ttl      time.Duration
+	mu       sync.RWMutex
+	tokens   map[string]cachedLookup
+	subjects map[string]cachedLookup
+}
+
+func NewCachedRevocationStore(store RevocationStore, ttl time.Duration) *CachedRevocationStore {
+	return &CachedRevocationStore{
+		store:    store,
+		ttl:      ttl,
+		tokens:   make(map[string]cachedLookup),
+		subjects: make(map[string]cachedLookup),
+	}
+}
+
+func (c *CachedRevocationStore) RevokeToken(ctx context.Context, jti, subject string, expiresAt time.Time) error {
+	if err := c.store.RevokeToken(ctx, jti, subject, expiresAt); err != nil {
+		return err
+	}
+
+	c.save(c.tokens, jti, cachedLookup{revoked: true, expiresAt: expiresAt})
+
+	return nil
+}
+
+func (c *CachedRevocationStore) RevokeSubject(ctx context.Context, subject string, revokedAt time.Time) error {
+	if err := c.store.RevokeSubject(ctx, subject, revokedAt); err != nil {
+		return err
+	}
+
+	// the store keeps the latest revocation, which may predate revokedAt
+	c.mu.Lock()
+	delete(c.subjects, subject)
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *CachedRevocationStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	if entry, ok := c.lookup(c.tokens, jti); ok {
+		return entry.revoked, nil
+	}
+
+	revoked, err := c.store.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	c.save(c.tokens, jti, cachedLookup{revoked: revoked, expiresAt: time.Now().Add(c.ttl)})
+
+	return revoked, nil
+}
+
+func (c *CachedRevocationStore) SubjectRevokedAt(ctx context.Context, subject string) (time.Time, error) {
+	if entry, ok := c.lookup(c.subjects, subject); ok {
+		return entry.revokedAt, nil
+	}
+
+	revokedAt, err := c.store.SubjectRevokedAt(ctx, subject)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c.save(c.subjects, subject, cachedLookup{revokedAt: revokedAt, expiresAt: time.Now().Add(c.ttl)})
+
+	return revokedAt, nil
+}
+
+func (c *CachedRevocationStore) lookup(entries map[string]cachedLookup, key string) (cachedLookup, bool) {
+	c.mu.RLock()
+	entry, ok := entries[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		if ok {
+			c.mu.Lock()
+			delete(entries, key)
+			c.mu.Unlock()
+		}
+		return cachedLookup{}, false
+	}
+
+	return entry, true
+}
+
+func (c *CachedRevocationStore) save(entries map[string]cachedLookup, key string, entry cachedLookup) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(entries) >= maxCachedLookups {
+		now := time.Now()
+		for k, e := range entries {
+			if now.After(e.expiresAt) {
+				delete(entries, k)
+			}
+		}
+	}
+
+	entries[key] = entry
+}
@@ -0,0 +1,81 @@
+package jwt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	SubjectTypeUser      = "user"
+	SubjectTypeAlumni    = "alumni"
+	SubjectTypeUserStudy = "userstudy"
+)
+
+// Subjects are "<type>:<id>" so that identifiers from different principal
+// kinds never collide.
+
+func UserSubject(id uint64) string {
+	return SubjectTypeUser + ":" + strconv.FormatUint(id, 10)
+}
+
+// AlumniSubject is built from the id of the local alumni record, not the
+// NIM.
+func AlumniSubject(id uint64) string {
+	return SubjectTypeAlumni + ":" + strconv.FormatUint(id, 10)
+}
+
+func UserStudySubject(email string) string {
+	return SubjectTypeUserStudy + ":" + strings.ToLower(email)
+}
+
+func ParseSubject(subject string) (string, string, error) {
+	subjectType, id, ok := strings.Cut(subject, ":")
+	if !ok || id == "" {
+		return "", "", fmt.Errorf("invalid subject %q", subject)
+	}
+
+	return subjectType, id, nil
+}
+
+// ParseUserSubject returns the users table id of a staff subject.
+func ParseUserSubject(subject string) (uint64, error) {
+	subjectType, id, err := ParseSubject(subject)
+	if err != nil {
+		return 0, err
+	}
+
+	if subjectType != SubjectTypeUser {
+		return 0, fmt.Errorf("subject %q is not a user", subject)
+	}
+
+	return strconv.ParseUint(id, 10, 64)
+}
+
+// ParseAlumniSubject returns the alumni table id of an alumni subject.
+func ParseAlumniSubject(subject string) (uint64, error) {
+	subjectType, id, err := ParseSubject(subject)
+	if err != nil {
+		return 0, err
+	}
+
+	if subjectType != SubjectTypeAlumni {
+		return 0, fmt.Errorf("subject %q is not an alumni", subject)
+	}
+
+	return strconv.ParseUint(id, 10, 64)
+}
+
+// ParseUserStudySubject returns the email of an alumni employer subject.
+func ParseUserStudySubject(subject string) (string, error) {
+	subjectType, email, err := ParseSubject(subject)
+	if err != nil {
+		return "", err
+	}
+
+	if subjectType != SubjectTypeUserStudy {
+		return "", fmt.Errorf("subject %q is not a user study", subject)
+	}
+
+	return email, nil
+}
@@ -0,0 +1,42 @@
+package jwt
+
+import (
+	"testing"
+)
+
+func TestParseSubjects(t *testing.T) {
+	tests := []struct {
+		name          string
+		subject       string
+		wantUser      uint64
+		wantAlumni    uint64
+		wantUserStudy string
+	}{
+		{name: "user", subject: UserSubject(42), wantUser: 42},
+		{name: "alumni", subject: AlumniSubject(7), wantAlumni: 7},
+		{name: "employer email is lower cased", subject: UserStudySubject("HRD@Example.com"), wantUserStudy: "hrd@example.com"},
+		{name: "no type", subject: "42"},
+		{name: "no id", subject: "user:"},
+		{name: "id is not a number", subject: "user:budi"},
+		{name: "unknown type", subject: "service:pkts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userId, err := ParseUserSubject(tt.subject)
+			if (err == nil) != (tt.wantUser != 0) || userId != tt.wantUser {
+				t.Errorf("ParseUserSubject(%q) = %d, %v, want %d", tt.subject, userId, err, tt.wantUser)
+			}
+
+			alumniId, err := ParseAlumniSubject(tt.subject)
+			if (err == nil) != (tt.wantAlumni != 0) || alumniId != tt.wantAlumni {
+				t.Errorf("ParseAlumniSubject(%q) = %d, %v, want %d", tt.subject, alumniId, err, tt.wantAlumni)
+			}
+
+			email, err := ParseUserStudySubject(tt.subject)
+			if (err == nil) != (tt.wantUserStudy != "") || email != tt.wantUserStudy {
+				t.Errorf("ParseUserStudySubject(%q) = %q, %v, want %q", tt.subject, email, err, tt.wantUserStudy)
+			}
+		})
+	}
+}
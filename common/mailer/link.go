@@ -0,0 +1,25 @@
+package mailer
+
+import (
+	"net/url"
+)
+
+// Link returns the page at baseURL with token as its token query parameter,
+// or the bare token when no page is configured. Config validation rejects
+// page URLs that do not parse.
+func Link(baseURL, token string) string {
+	if baseURL == "" {
+		return token
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return token
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"tracerstudy-auth-service/common/config"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers plain text messages to users.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+const (
+	DriverSMTP   = "smtp"
+	DriverOutbox = "outbox"
+	DriverLog    = "log"
+)
+
+// NewMailer returns the mailer of the configured driver.
+func NewMailer(cfg config.Mail) (Mailer, error) {
+	switch cfg.Driver {
+	case DriverSMTP:
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("mail driver %s requires MAIL_SMTP_HOST", DriverSMTP)
+		}
+		return NewSMTPMailer(cfg), nil
+	case DriverOutbox:
+		if cfg.OutboxPath == "" {
+			return nil, fmt.Errorf("mail driver %s requires MAIL_OUTBOX_PATH", DriverOutbox)
+		}
+		return NewOutboxMailer(cfg.OutboxPath), nil
+	case DriverLog:
+		return NewOutboxMailer(""), nil
+	}
+
+	return nil, fmt.Errorf("unsupported mail driver %q", cfg.Driver)
+}
+
+func (m *Message) validate() error {
+	if m.To == "" {
+		return fmt.Errorf("message has no recipient")
+	}
+
+	// header values must not smuggle in extra headers
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return fmt.Errorf("message headers contain line breaks")
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+)
+
+func TestNewMailer(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Mail
+		wantErr bool
+	}{
+		{name: "smtp", cfg: config.Mail{Driver: DriverSMTP, SMTPHost: "smtp.example.com", SMTPPort: "587"}},
+		{name: "smtp without host", cfg: config.Mail{Driver: DriverSMTP}, wantErr: true},
+		{name: "outbox", cfg: config.Mail{Driver: DriverOutbox, OutboxPath: "outbox.txt"}},
+		{name: "outbox without path", cfg: config.Mail{Driver: DriverOutbox}, wantErr: true},
+		{name: "log", cfg: config.Mail{Driver: DriverLog}},
+		{name: "no driver", cfg: config.Mail{}, wantErr: true},
+		{name: "unknown driver", cfg: config.Mail{Driver: "sendmail"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMailer(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewMailer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got == nil) != tt.wantErr {
+				t.Errorf("NewMailer() = %v, want a mailer %v", got, !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMessageValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     *Message
+		wantErr bool
+	}{
+		{name: "valid", msg: &Message{To: "budi@example.com", Subject: "Reset your password"}},
+		{name: "no recipient", msg: &Message{Subject: "Reset your password"}, wantErr: true},
+		{name: "header in recipient", msg: &Message{To: "budi@example.com\r\nBcc: siti@example.com"}, wantErr: true},
+		{name: "header in subject", msg: &Message{To: "budi@example.com", Subject: "Hi\nBcc: siti@example.com"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.msg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSMTPMailerSendHonoursContext(t *testing.T) {
+	// a relay that accepts connections but never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	m := NewSMTPMailer(config.Mail{SMTPHost: host, SMTPPort: port, From: "no-reply@example.com"})
+	msg := &Message{To: "budi@example.com", Subject: "Reset your password", Body: "link"}
+
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+	}{
+		{
+			name: "deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 100*time.Millisecond)
+			},
+		},
+		{
+			name: "cancellation",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(100*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			start := time.Now()
+			if err := m.Send(ctx, msg); err == nil {
+				t.Fatalf("Send() to a silent relay succeeded")
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("Send() returned after %v, want it to stop with ctx", elapsed)
+			}
+		})
+	}
+}
+
+func TestOutboxMailerSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.txt")
+	m, err := NewMailer(config.Mail{Driver: DriverOutbox, OutboxPath: path})
+	if err != nil {
+		t.Fatalf("NewMailer() error = %v", err)
+	}
+
+	if err := m.Send(context.Background(), &Message{To: "budi@example.com", Subject: "Hi", Body: "link"}); err != nil {
+		t.Errorf("Send() error = %v", err)
+	}
+	if err := m.Send(context.Background(), &Message{Subject: "Hi"}); err == nil {
+		t.Errorf("Send() without recipient succeeded")
+	}
+}
+
+func TestLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+	}{
+		{name: "no page", want: "abc-123"},
+		{name: "page", baseURL: "https://tracer.example.com/reset", want: "https://tracer.example.com/reset?token=abc-123"},
+		{name: "page with query", baseURL: "https://tracer.example.com/reset?lang=id", want: "https://tracer.example.com/reset?lang=id&token=abc-123"},
+		{name: "page with a token", baseURL: "https://tracer.example.com/reset?token=old", want: "https://tracer.example.com/reset?token=abc-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Link(tt.baseURL, "abc-123"); got != tt.want {
+				t.Errorf("Link() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// OutboxMailer stands in for SMTP during local development. It appends
+// every message to the file at path, or writes it to the log when path is
+// empty. Messages may carry secrets such as reset links, so it must not be
+// used in production.
+type OutboxMailer struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewOutboxMailer(path string) *OutboxMailer {
+	return &OutboxMailer{
+		path: path,
+	}
+}
+
+func (m *OutboxMailer) Send(ctx context.Context, msg *Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	entry := fmt.Sprintf("Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+
+	if m.path == "" {
+		log.Println("INFO: [OutboxMailer - Send] Mail not delivered, mail driver is log:\n" + entry)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		log.Println("ERROR: [OutboxMailer - Send] Error while opening outbox:", err)
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(entry); err != nil {
+		log.Println("ERROR: [OutboxMailer - Send] Error while writing outbox:", err)
+		return err
+	}
+
+	return nil
+}
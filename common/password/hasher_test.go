@@ -0,0 +1,204 @@
+package password
+
+import (
+	"strings"
+	"testing"
+	"tracerstudy-auth-service/common/config"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestHasher(t *testing.T, cfg config.Password) *PHCHasher {
+	t.Helper()
+
+	hasher, err := NewHasher(cfg)
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	return hasher
+}
+
+func argon2Config(memory, iterations uint32) config.Password {
+	return config.Password{
+		HashAlgorithm:     AlgorithmArgon2id,
+		Argon2Memory:      memory,
+		Argon2Iterations:  iterations,
+		Argon2Parallelism: 1,
+	}
+}
+
+func bcryptConfig(cost int) config.Password {
+	return config.Password{
+		HashAlgorithm: AlgorithmBcrypt,
+		BcryptCost:    cost,
+	}
+}
+
+func TestNewHasher(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Password
+		wantErr bool
+	}{
+		{name: "argon2id", cfg: argon2Config(64, 1)},
+		{name: "argon2id without memory", cfg: argon2Config(0, 1), wantErr: true},
+		{name: "argon2id without iterations", cfg: argon2Config(64, 0), wantErr: true},
+		{name: "bcrypt", cfg: bcryptConfig(bcrypt.MinCost)},
+		{name: "bcrypt cost too low", cfg: bcryptConfig(bcrypt.MinCost - 1), wantErr: true},
+		{name: "bcrypt cost too high", cfg: bcryptConfig(bcrypt.MaxCost + 1), wantErr: true},
+		{name: "unknown algorithm", cfg: config.Password{HashAlgorithm: "md5"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHasher(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewHasher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPHCHasherVerify(t *testing.T) {
+	const password = "correct horse battery staple"
+
+	weakArgon2 := newTestHasher(t, argon2Config(32, 1))
+	argon2Hasher := newTestHasher(t, argon2Config(64, 1))
+	weakBcrypt := newTestHasher(t, bcryptConfig(bcrypt.MinCost))
+	bcryptHasher := newTestHasher(t, bcryptConfig(bcrypt.MinCost+1))
+
+	hash := func(h *PHCHasher) string {
+		encoded, err := h.Hash(password)
+		if err != nil {
+			t.Fatalf("Hash() error = %v", err)
+		}
+		return encoded
+	}
+
+	argon2Hash := hash(argon2Hasher)
+	weakArgon2Hash := hash(weakArgon2)
+	bcryptHash := hash(bcryptHasher)
+	weakBcryptHash := hash(weakBcrypt)
+
+	tests := []struct {
+		name            string
+		hasher          *PHCHasher
+		password        string
+		encoded         string
+		wantMatch       bool
+		wantNeedsRehash bool
+		wantErr         bool
+	}{
+		{
+			name:      "argon2id with the configured parameters",
+			hasher:    argon2Hasher,
+			password:  password,
+			encoded:   argon2Hash,
+			wantMatch: true,
+		},
+		{
+			name:     "argon2id with another password",
+			hasher:   argon2Hasher,
+			password: "wrong",
+			encoded:  argon2Hash,
+		},
+		{
+			name:            "argon2id with weaker parameters",
+			hasher:          argon2Hasher,
+			password:        password,
+			encoded:         weakArgon2Hash,
+			wantMatch:       true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:            "bcrypt when argon2id is configured",
+			hasher:          argon2Hasher,
+			password:        password,
+			encoded:         bcryptHash,
+			wantMatch:       true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:      "bcrypt with the configured cost",
+			hasher:    bcryptHasher,
+			password:  password,
+			encoded:   bcryptHash,
+			wantMatch: true,
+		},
+		{
+			name:            "bcrypt with a lower cost",
+			hasher:          bcryptHasher,
+			password:        password,
+			encoded:         weakBcryptHash,
+			wantMatch:       true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:     "bcrypt with another password",
+			hasher:   bcryptHasher,
+			password: "wrong",
+			encoded:  bcryptHash,
+		},
+		{
+			name:            "argon2id when bcrypt is configured",
+			hasher:          bcryptHasher,
+			password:        password,
+			encoded:         argon2Hash,
+			wantMatch:       true,
+			wantNeedsRehash: true,
+		},
+		{
+			name:     "unknown format",
+			hasher:   argon2Hasher,
+			password: password,
+			encoded:  "5f4dcc3b5aa765d61d8327deb882cf99",
+			wantErr:  true,
+		},
+		{
+			name:     "malformed argon2id hash",
+			hasher:   argon2Hasher,
+			password: password,
+			encoded:  argon2Hash[:strings.LastIndex(argon2Hash, "$")],
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported argon2id version",
+			hasher:   argon2Hasher,
+			password: password,
+			encoded:  strings.Replace(argon2Hash, "$v=19$", "$v=16$", 1),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, needsRehash, err := tt.hasher.Verify(tt.password, tt.encoded)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if match != tt.wantMatch || needsRehash != tt.wantNeedsRehash {
+				t.Errorf("Verify() = %v, %v, want %v, %v", match, needsRehash, tt.wantMatch, tt.wantNeedsRehash)
+			}
+		})
+	}
+}
+
+func TestPHCHasherHash(t *testing.T) {
+	hasher := newTestHasher(t, argon2Config(64, 1))
+
+	first, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	second, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	if !strings.HasPrefix(first, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("Hash() = %q, want a PHC argon2id hash", first)
+	}
+	if first == second {
+		t.Errorf("Hash() returned the same hash twice, want a random salt")
+	}
+}
@@ -0,0 +1,170 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"tracerstudy-auth-service/common/config"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// MaxBytes is the longest password bcrypt hashes in full.
+	MaxBytes = 72
+
+	minIdentifierLength = 3
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+var commonPasswords = parseCommonPasswords(commonPasswordList)
+
+// Policy decides which passwords users may choose.
+type Policy struct {
+	minLength      int
+	minCharClasses int
+	checkCommon    bool
+}
+
+// Owner describes the account a password is chosen for, so the password
+// can be checked against the account's identifiers.
+type Owner struct {
+	Username string
+	Email    string
+}
+
+// Violation explains why a password was rejected.
+type Violation struct {
+	Field       string
+	Description string
+}
+
+func NewPolicy(cfg config.Password) *Policy {
+	return &Policy{
+		minLength:      cfg.MinLength,
+		minCharClasses: cfg.MinCharClasses,
+		checkCommon:    cfg.CheckCommon,
+	}
+}
+
+// Validate lists every rule password breaks, reporting each under field.
+func (p *Policy) Validate(field, password string, owner Owner) []Violation {
+	var violations []Violation
+	add := func(format string, args ...any) {
+		violations = append(violations, Violation{Field: field, Description: fmt.Sprintf(format, args...)})
+	}
+
+	if password == "" {
+		add("is required")
+		return violations
+	}
+
+	if utf8.RuneCountInString(password) < p.minLength {
+		add("must be at least %d characters long", p.minLength)
+	}
+
+	if len(password) > MaxBytes {
+		add("must be at most %d bytes long", MaxBytes)
+	}
+
+	if classes := charClasses(password); classes < p.minCharClasses {
+		add("must mix at least %d of lowercase letters, uppercase letters, digits and symbols", p.minCharClasses)
+	}
+
+	lower := strings.ToLower(password)
+	for _, identifier := range owner.identifiers() {
+		if strings.Contains(lower, identifier) {
+			add("must not contain the username or email")
+			break
+		}
+	}
+
+	if p.checkCommon && commonPasswords[lower] {
+		add("is too common")
+	}
+
+	return violations
+}
+
+// Check validates password and returns an InvalidArgument status carrying
+// the violations as BadRequest field violations, or nil.
+func (p *Policy) Check(field, password string, owner Owner) error {
+	return Error(p.Validate(field, password, owner))
+}
+
+// Error converts violations into an InvalidArgument status, or nil when
+// there are none.
+func Error(violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	descriptions := make([]string, 0, len(violations))
+	details := &errdetails.BadRequest{}
+	for _, v := range violations {
+		descriptions = append(descriptions, v.Field+" "+v.Description)
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	st := status.New(codes.InvalidArgument, strings.Join(descriptions, "; "))
+	if withDetails, err := st.WithDetails(details); err == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+func (o Owner) identifiers() []string {
+	var identifiers []string
+	localPart, _, _ := strings.Cut(o.Email, "@")
+	for _, id := range []string{o.Username, localPart} {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if len(id) >= minIdentifierLength {
+			identifiers = append(identifiers, id)
+		}
+	}
+
+	return identifiers
+}
+
+func charClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	return lower + upper + digit + symbol
+}
+
+func parseCommonPasswords(list string) map[string]bool {
+	passwords := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = true
+	}
+
+	return passwords
+}
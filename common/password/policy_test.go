@@ -0,0 +1,107 @@
+package password
+
+import (
+	"strings"
+	"testing"
+	"tracerstudy-auth-service/common/config"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	policy := NewPolicy(config.Password{MinLength: 8, MinCharClasses: 3, CheckCommon: true})
+	owner := Owner{Username: "budi", Email: "budi.santoso@example.com"}
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{
+			name:     "strong password",
+			password: "Kopi-Tubruk-42",
+		},
+		{
+			name:     "empty password",
+			password: "",
+			want:     []string{"is required"},
+		},
+		{
+			name:     "short password",
+			password: "Ab1!",
+			want:     []string{"must be at least 8 characters long"},
+		},
+		{
+			name:     "too few character classes",
+			password: "kopitubrukenak",
+			want:     []string{"must mix at least 3 of lowercase letters, uppercase letters, digits and symbols"},
+		},
+		{
+			name:     "contains the username",
+			password: "Xbudi-2024!",
+			want:     []string{"must not contain the username or email"},
+		},
+		{
+			name:     "contains the email local part",
+			password: "Budi.Santoso#1",
+			want:     []string{"must not contain the username or email"},
+		},
+		{
+			name:     "common password",
+			password: "Password1",
+			want:     []string{"is too common"},
+		},
+		{
+			name:     "longer than bcrypt hashes",
+			password: "Aa1-" + strings.Repeat("x", MaxBytes),
+			want:     []string{"must be at most 72 bytes long"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Validate("password", tt.password, owner)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Validate() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Field != "password" || got[i].Description != tt.want[i] {
+					t.Errorf("Validate()[%d] = %+v, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	policy := NewPolicy(config.Password{MinLength: 8, MinCharClasses: 3})
+
+	if err := policy.Check("new_password", "Kopi-Tubruk-42", Owner{}); err != nil {
+		t.Errorf("Check() error = %v, want nil", err)
+	}
+
+	err := policy.Check("new_password", "short", Owner{})
+	st := status.Convert(err)
+	if len(st.Details()) != 1 {
+		t.Fatalf("Check() details = %v, want a BadRequest", st.Details())
+	}
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || len(badRequest.FieldViolations) != 2 || badRequest.FieldViolations[0].Field != "new_password" {
+		t.Errorf("Check() details = %v, want two violations of new_password", st.Details())
+	}
+}
+
+func TestCommonPasswords(t *testing.T) {
+	if len(commonPasswords) < 1000 {
+		t.Errorf("common passwords = %d, want a real list", len(commonPasswords))
+	}
+
+	// shorter entries are already rejected by the minimum length
+	for password := range commonPasswords {
+		if utf8.RuneCountInString(password) < 8 {
+			t.Errorf("common password %q is shorter than the default minimum length", password)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package redact
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// sensitiveFields are cleared from every outgoing message, however deeply
+// they are nested, so a credential added to a response by mistake never
+// reaches a client.
+var sensitiveFields = map[protoreflect.Name]bool{
+	"password":      true,
+	"password_hash": true,
+	"private_key":   true,
+	"client_secret": true,
+}
+
+// Message clears the sensitive fields of msg and of every message it holds.
+func Message(msg proto.Message) {
+	if msg == nil {
+		return
+	}
+
+	redact(msg.ProtoReflect())
+}
+
+func redact(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if sensitiveFields[fd.Name()] {
+			m.Clear(fd)
+			return true
+		}
+
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				redact(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				redact(mv.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			redact(v.Message())
+		}
+
+		return true
+	})
+}
@@ -0,0 +1,107 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StoreMemory   = "memory"
+	StoreDatabase = "database"
+
+	maxMemoryEntries = 10000
+)
+
+// Attempts counts the consecutive failed logins recorded under a key.
+type Attempts struct {
+	Key           string
+	Failures      uint32
+	LastFailureAt time.Time
+}
+
+// Store keeps failed-login counters. Fail must count atomically, since
+// replicas sharing a store may record failures for the same key at once.
+type Store interface {
+	// Get returns nil when no failure is recorded under key.
+	Get(ctx context.Context, key string) (*Attempts, error)
+	// Fail adds a failure at the given time, starting the count over when
+	// the previous failure is older than resetAfter.
+	Fail(ctx context.Context, key string, at time.Time, resetAfter time.Duration) (*Attempts, error)
+	Clear(ctx context.Context, key string) error
+	// FindSince returns the counters whose last failure is at or after since.
+	FindSince(ctx context.Context, since time.Time) ([]*Attempts, error)
+}
+
+// MemoryStore keeps counters in process memory, so each replica throttles
+// on its own.
+type MemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]Attempts
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		attempts: make(map[string]Attempts),
+	}
+}
+
+func (m *MemoryStore) Get(ctx context.Context, key string) (*Attempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempts, ok := m.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &attempts, nil
+}
+
+func (m *MemoryStore) Fail(ctx context.Context, key string, at time.Time, resetAfter time.Duration) (*Attempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.attempts) >= maxMemoryEntries {
+		for k, a := range m.attempts {
+			if at.Sub(a.LastFailureAt) > resetAfter {
+				delete(m.attempts, k)
+			}
+		}
+	}
+
+	attempts, ok := m.attempts[key]
+	if !ok || at.Sub(attempts.LastFailureAt) > resetAfter {
+		attempts = Attempts{Key: key}
+	}
+
+	attempts.Failures++
+	attempts.LastFailureAt = at
+	m.attempts[key] = attempts
+
+	return &attempts, nil
+}
+
+func (m *MemoryStore) Clear(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, key)
+
+	return nil
+}
+
+func (m *MemoryStore) FindSince(ctx context.Context, since time.Time) ([]*Attempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var res []*Attempts
+	for _, a := range m.attempts {
+		if !a.LastFailureAt.Before(since) {
+			attempts := a
+			res = append(res, &attempts)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,234 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tracerstudy-auth-service/common/config"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	RetryAfterHeader = "retry-after"
+
+	accountPrefix = "account:"
+	clientPrefix  = "client:"
+)
+
+// Lockout describes a key that may not attempt to log in until BlockedUntil.
+// LockedOut is set once the key reached the lockout threshold, as opposed to
+// waiting out a backoff delay.
+type Lockout struct {
+	Key          string
+	Failures     uint32
+	BlockedUntil time.Time
+	LockedOut    bool
+}
+
+// Throttler slows down and eventually locks out accounts and client
+// addresses that keep failing to log in.
+type Throttler struct {
+	cfg   config.Throttle
+	store Store
+}
+
+func NewThrottler(cfg config.Throttle, store Store) *Throttler {
+	return &Throttler{
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+// AccountKey names the counter of an account, kind telling apart the
+// different login methods, e.g. user or alumni.
+func AccountKey(kind, id string) string {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if id == "" {
+		return ""
+	}
+
+	return accountPrefix + kind + ":" + id
+}
+
+// ClientKey names the counter of a client address.
+func ClientKey(addr string) string {
+	if addr == "" {
+		return ""
+	}
+
+	return clientPrefix + addr
+}
+
+// Check returns a ResourceExhausted error carrying the remaining wait as
+// RetryInfo when any of keys is blocked. Empty keys are ignored.
+func (t *Throttler) Check(ctx context.Context, keys ...string) error {
+	now := time.Now()
+	var wait time.Duration
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		attempts, err := t.store.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if attempts == nil {
+			continue
+		}
+
+		if lockout, blocked := t.lockout(attempts, now); blocked {
+			wait = maxDuration(wait, lockout.BlockedUntil.Sub(now))
+		}
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+
+	return tooManyAttempts(wait)
+}
+
+// Fail records a failed login against each non-empty key.
+func (t *Throttler) Fail(ctx context.Context, keys ...string) error {
+	now := time.Now()
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		if _, err := t.store.Fail(ctx, key, now, t.cfg.ResetAfter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Succeed forgets the failures of key after a successful login. Client
+// counters are left alone so one valid account cannot clear them.
+func (t *Throttler) Succeed(ctx context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+
+	return t.store.Clear(ctx, key)
+}
+
+// Clear lifts the lockout of key.
+func (t *Throttler) Clear(ctx context.Context, key string) error {
+	return t.store.Clear(ctx, key)
+}
+
+// Lockouts lists the keys that are currently blocked, longest block first.
+func (t *Throttler) Lockouts(ctx context.Context) ([]*Lockout, error) {
+	now := time.Now()
+	attempts, err := t.store.FindSince(ctx, now.Add(-maxDuration(t.cfg.LockoutDuration, t.cfg.MaxDelay)))
+	if err != nil {
+		return nil, err
+	}
+
+	var lockouts []*Lockout
+	for _, a := range attempts {
+		if lockout, blocked := t.lockout(a, now); blocked {
+			lockouts = append(lockouts, lockout)
+		}
+	}
+
+	sort.Slice(lockouts, func(i, j int) bool {
+		return lockouts[i].BlockedUntil.After(lockouts[j].BlockedUntil)
+	})
+
+	return lockouts, nil
+}
+
+func (t *Throttler) lockout(attempts *Attempts, now time.Time) (*Lockout, bool) {
+	freeAttempts, lockoutThreshold := t.cfg.AccountFreeAttempts, t.cfg.AccountLockoutThreshold
+	if strings.HasPrefix(attempts.Key, clientPrefix) {
+		freeAttempts, lockoutThreshold = t.cfg.ClientFreeAttempts, t.cfg.ClientLockoutThreshold
+	}
+
+	lockout := &Lockout{
+		Key:      attempts.Key,
+		Failures: attempts.Failures,
+	}
+
+	switch {
+	case lockoutThreshold > 0 && attempts.Failures >= lockoutThreshold:
+		lockout.LockedOut = true
+		lockout.BlockedUntil = attempts.LastFailureAt.Add(t.cfg.LockoutDuration)
+	case attempts.Failures > freeAttempts:
+		lockout.BlockedUntil = attempts.LastFailureAt.Add(t.backoff(attempts.Failures - freeAttempts))
+	default:
+		return lockout, false
+	}
+
+	return lockout, lockout.BlockedUntil.After(now)
+}
+
+// backoff doubles BaseDelay for every failure past the free attempts.
+func (t *Throttler) backoff(excess uint32) time.Duration {
+	delay := float64(t.cfg.BaseDelay) * math.Pow(2, float64(excess-1))
+	if delay > float64(t.cfg.MaxDelay) {
+		return t.cfg.MaxDelay
+	}
+
+	return time.Duration(delay)
+}
+
+// RetryAfter extracts the wait carried by an error from Check.
+func RetryAfter(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.ResourceExhausted {
+		return 0, false
+	}
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+
+	return 0, false
+}
+
+// SetRetryAfterHeader sends the wait carried by an error from Check as the
+// retry-after response header, in whole seconds.
+func SetRetryAfterHeader(ctx context.Context, err error) {
+	if wait, ok := RetryAfter(err); ok {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, RetryAfterSeconds(wait)))
+	}
+}
+
+// RetryAfterSeconds formats wait for a Retry-After header, rounding up.
+func RetryAfterSeconds(wait time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(wait.Seconds())), 10)
+}
+
+func tooManyAttempts(wait time.Duration) error {
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf("too many failed login attempts, retry in %s", wait.Round(time.Second)))
+	if withDetails, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+
+	return b
+}
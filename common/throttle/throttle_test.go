@@ -0,0 +1,221 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func testConfig() config.Throttle {
+	return config.Throttle{
+		BaseDelay:               time.Second,
+		MaxDelay:                30 * time.Second,
+		LockoutDuration:         15 * time.Minute,
+		ResetAfter:              time.Hour,
+		AccountFreeAttempts:     3,
+		AccountLockoutThreshold: 10,
+		ClientFreeAttempts:      20,
+		ClientLockoutThreshold:  100,
+	}
+}
+
+func TestThrottlerLockout(t *testing.T) {
+	now := time.Now()
+	account := AccountKey("user", "budi")
+	client := ClientKey("10.0.0.1")
+
+	tests := []struct {
+		name          string
+		key           string
+		failures      uint32
+		lastFailure   time.Time
+		wantBlocked   bool
+		wantLockedOut bool
+		wantUntil     time.Time
+	}{
+		{
+			name:        "free attempts",
+			key:         account,
+			failures:    3,
+			lastFailure: now,
+		},
+		{
+			name:        "first failure past the free attempts waits the base delay",
+			key:         account,
+			failures:    4,
+			lastFailure: now,
+			wantBlocked: true,
+			wantUntil:   now.Add(time.Second),
+		},
+		{
+			name:        "each further failure doubles the wait",
+			key:         account,
+			failures:    6,
+			lastFailure: now,
+			wantBlocked: true,
+			wantUntil:   now.Add(4 * time.Second),
+		},
+		{
+			name:        "wait is capped at the max delay",
+			key:         account,
+			failures:    9,
+			lastFailure: now,
+			wantBlocked: true,
+			wantUntil:   now.Add(30 * time.Second),
+		},
+		{
+			name:        "wait already passed",
+			key:         account,
+			failures:    6,
+			lastFailure: now.Add(-5 * time.Second),
+		},
+		{
+			name:          "lockout threshold",
+			key:           account,
+			failures:      10,
+			lastFailure:   now,
+			wantBlocked:   true,
+			wantLockedOut: true,
+			wantUntil:     now.Add(15 * time.Minute),
+		},
+		{
+			name:        "lockout expired",
+			key:         account,
+			failures:    12,
+			lastFailure: now.Add(-16 * time.Minute),
+		},
+		{
+			name:        "client addresses get more free attempts",
+			key:         client,
+			failures:    20,
+			lastFailure: now,
+		},
+		{
+			name:          "client lockout threshold",
+			key:           client,
+			failures:      100,
+			lastFailure:   now,
+			wantBlocked:   true,
+			wantLockedOut: true,
+			wantUntil:     now.Add(15 * time.Minute),
+		},
+	}
+
+	throttler := NewThrottler(testConfig(), NewMemoryStore())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lockout, blocked := throttler.lockout(&Attempts{Key: tt.key, Failures: tt.failures, LastFailureAt: tt.lastFailure}, now)
+			if blocked != tt.wantBlocked {
+				t.Fatalf("lockout() blocked = %v, want %v", blocked, tt.wantBlocked)
+			}
+			if !blocked {
+				return
+			}
+			if lockout.LockedOut != tt.wantLockedOut || !lockout.BlockedUntil.Equal(tt.wantUntil) {
+				t.Errorf("lockout() = %+v, want locked out %v until %v", lockout, tt.wantLockedOut, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestThrottlerCheck(t *testing.T) {
+	ctx := context.Background()
+	account := AccountKey("user", "Budi ")
+	client := ClientKey("10.0.0.1")
+
+	throttler := NewThrottler(testConfig(), NewMemoryStore())
+
+	for i := 0; i < 3; i++ {
+		if err := throttler.Check(ctx, account, client, ""); err != nil {
+			t.Fatalf("Check() after %d failures error = %v, want nil", i, err)
+		}
+		if err := throttler.Fail(ctx, account, client, ""); err != nil {
+			t.Fatalf("Fail() error = %v", err)
+		}
+	}
+
+	if err := throttler.Check(ctx, account, client); err != nil {
+		t.Fatalf("Check() after the free attempts error = %v, want nil", err)
+	}
+	if err := throttler.Fail(ctx, account, client); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+
+	err := throttler.Check(ctx, AccountKey("user", "budi"), client)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Check() error = %v, want ResourceExhausted", err)
+	}
+	if wait, ok := RetryAfter(err); !ok || wait <= 0 || wait > time.Second {
+		t.Errorf("RetryAfter() = %v, %v, want at most the base delay", wait, ok)
+	}
+
+	if err := throttler.Check(ctx, AccountKey("user", "siti"), ClientKey("10.0.0.2")); err != nil {
+		t.Errorf("Check() of other keys error = %v, want nil", err)
+	}
+
+	if err := throttler.Succeed(ctx, account); err != nil {
+		t.Fatalf("Succeed() error = %v", err)
+	}
+	if err := throttler.Check(ctx, account, client); err != nil {
+		t.Errorf("Check() after a successful login error = %v, want nil", err)
+	}
+
+	lockouts, err := throttler.Lockouts(ctx)
+	if err != nil {
+		t.Fatalf("Lockouts() error = %v", err)
+	}
+	if len(lockouts) != 0 {
+		t.Errorf("Lockouts() = %v, want none", lockouts)
+	}
+}
+
+func TestMemoryStoreFail(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	start := time.Now()
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want uint32
+	}{
+		{name: "first failure", at: start, want: 1},
+		{name: "failure within the reset window", at: start.Add(30 * time.Minute), want: 2},
+		{name: "failure at the end of the reset window", at: start.Add(90 * time.Minute), want: 3},
+		{name: "failure after the reset window", at: start.Add(3 * time.Hour), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts, err := store.Fail(ctx, "key", tt.at, time.Hour)
+			if err != nil {
+				t.Fatalf("Fail() error = %v", err)
+			}
+			if attempts.Failures != tt.want || !attempts.LastFailureAt.Equal(tt.at) {
+				t.Errorf("Fail() = %+v, want %d failures at %v", attempts, tt.want, tt.at)
+			}
+		})
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	tests := []struct {
+		wait time.Duration
+		want string
+	}{
+		{wait: time.Second, want: "1"},
+		{wait: 1500 * time.Millisecond, want: "2"},
+		{wait: time.Millisecond, want: "1"},
+		{wait: 15 * time.Minute, want: "900"},
+	}
+
+	for _, tt := range tests {
+		if got := RetryAfterSeconds(tt.wait); got != tt.want {
+			t.Errorf("RetryAfterSeconds(%v) = %q, want %q", tt.wait, got, tt.want)
+		}
+	}
+}
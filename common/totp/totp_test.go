@@ -0,0 +1,158 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// the ASCII secret "12345678901234567890" of the RFC 6238 SHA1 test vectors
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Code() at %d = %q, want %q", tt.unix, got, tt.want)
+		}
+	}
+
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Errorf("Code() of a malformed secret succeeded")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := Step(now)
+
+	code := func(step int64) string {
+		c, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatalf("Code() error = %v", err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name      string
+		code      string
+		afterStep int64
+		wantStep  int64
+		wantOk    bool
+	}{
+		{
+			name:     "current step",
+			code:     code(step),
+			wantStep: step,
+			wantOk:   true,
+		},
+		{
+			name:     "code with surrounding spaces",
+			code:     " " + code(step) + "\n",
+			wantStep: step,
+			wantOk:   true,
+		},
+		{
+			name:     "previous step within the skew",
+			code:     code(step - 1),
+			wantStep: step - 1,
+			wantOk:   true,
+		},
+		{
+			name:     "next step within the skew",
+			code:     code(step + 1),
+			wantStep: step + 1,
+			wantOk:   true,
+		},
+		{
+			name: "two steps behind",
+			code: code(step - 2),
+		},
+		{
+			name: "two steps ahead",
+			code: code(step + 2),
+		},
+		{
+			name:      "replay of the last used step",
+			code:      code(step),
+			afterStep: step,
+		},
+		{
+			name:      "earlier step than the last used one",
+			code:      code(step - 1),
+			afterStep: step,
+		},
+		{
+			name:      "later step than the last used one",
+			code:      code(step + 1),
+			afterStep: step,
+			wantStep:  step + 1,
+			wantOk:    true,
+		},
+		{
+			name: "wrong length",
+			code: code(step)[:Digits-1],
+		},
+		{
+			name: "wrong code",
+			code: "000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok, err := Validate(rfcSecret, tt.code, now, tt.afterStep)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if ok != tt.wantOk || gotStep != tt.wantStep {
+				t.Errorf("Validate() = %d, %v, want %d, %v", gotStep, ok, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	key, err := encoding.DecodeString(secret)
+	if err != nil || len(key) != secretSize {
+		t.Errorf("GenerateSecret() = %q, want %d base32 encoded bytes", secret, secretSize)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("Tracer Study", "budi@example.com", rfcSecret))
+	if err != nil {
+		t.Fatalf("URI() is not a URL: %v", err)
+	}
+
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Tracer Study:budi@example.com" {
+		t.Errorf("URI() = %q, want an otpauth totp URI labelled with issuer and account", uri)
+	}
+
+	query := uri.Query()
+	if query.Get("secret") != rfcSecret || query.Get("issuer") != "Tracer Study" || query.Get("digits") != "6" || query.Get("period") != "30" {
+		t.Errorf("URI() query = %v", query)
+	}
+}
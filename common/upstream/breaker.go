@@ -0,0 +1,150 @@
+package upstream
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker stops calls to an upstream after threshold consecutive failures.
+// Once open, calls fail fast for cooldown; then a single probe call is let
+// through, which closes the breaker if it succeeds and reopens it if not. A
+// zero threshold disables the breaker.
+type Breaker struct {
+	name      string
+	threshold uint32
+	cooldown  time.Duration
+	onChange  func(name string, state State)
+	mu        sync.Mutex
+	state     State
+	failures  uint32
+	openedAt  time.Time
+	probing   bool
+}
+
+func NewBreaker(name string, threshold uint32, cooldown time.Duration, onChange func(name string, state State)) *Breaker {
+	return &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		onChange:  onChange,
+	}
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Allow reports whether a call may go ahead, returning an Unavailable error
+// when it may not. Every allowed call must be followed by Success, Failure
+// or Cancel.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	changed := false
+
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.cooldown {
+		changed = b.setState(StateHalfOpen)
+	}
+
+	var err error
+	switch {
+	case b.state == StateOpen:
+		err = status.Errorf(codes.Unavailable, "%s is unavailable: circuit breaker is open", b.name)
+	case b.state == StateHalfOpen && b.probing:
+		err = status.Errorf(codes.Unavailable, "%s is unavailable: circuit breaker is probing", b.name)
+	case b.state == StateHalfOpen:
+		b.probing = true
+	}
+
+	b.unlock(changed)
+
+	return err
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	b.failures = 0
+	b.probing = false
+	changed := b.setState(StateClosed)
+	b.unlock(changed)
+}
+
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	b.probing = false
+
+	changed := false
+	switch b.state {
+	case StateHalfOpen:
+		changed = b.open()
+	case StateClosed:
+		b.failures++
+		if b.threshold > 0 && b.failures >= b.threshold {
+			changed = b.open()
+		}
+	}
+
+	b.unlock(changed)
+}
+
+// Cancel ends a call that told nothing about the upstream, such as one the
+// caller gave up on.
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *Breaker) open() bool {
+	b.openedAt = time.Now()
+	b.failures = 0
+	return b.setState(StateOpen)
+}
+
+func (b *Breaker) setState(state State) bool {
+	if b.state == state {
+		return false
+	}
+
+	b.state = state
+	return true
+}
+
+// unlock releases the lock, then reports a state change, so onChange may
+// call back into the breaker.
+func (b *Breaker) unlock(changed bool) {
+	state := b.state
+	b.mu.Unlock()
+
+	if changed && b.onChange != nil {
+		b.onChange(b.name, state)
+	}
+}
@@ -0,0 +1,130 @@
+package upstream
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	opAllow    = "allow"
+	opDeny     = "deny"
+	opSuccess  = "success"
+	opFailure  = "failure"
+	opCancel   = "cancel"
+	opCooldown = "cooldown"
+)
+
+func TestBreaker(t *testing.T) {
+	tests := []struct {
+		name        string
+		threshold   uint32
+		ops         []string
+		wantState   State
+		wantChanges []State
+	}{
+		{
+			name:      "failures below the threshold keep it closed",
+			threshold: 3,
+			ops:       []string{opAllow, opFailure, opAllow, opFailure, opAllow},
+			wantState: StateClosed,
+		},
+		{
+			name:      "success resets the failure count",
+			threshold: 3,
+			ops:       []string{opAllow, opFailure, opAllow, opFailure, opAllow, opSuccess, opAllow, opFailure, opAllow, opFailure, opAllow},
+			wantState: StateClosed,
+		},
+		{
+			name:        "threshold consecutive failures open it",
+			threshold:   2,
+			ops:         []string{opAllow, opFailure, opAllow, opFailure, opDeny},
+			wantState:   StateOpen,
+			wantChanges: []State{StateOpen},
+		},
+		{
+			name:        "cooldown lets a single probe through",
+			threshold:   1,
+			ops:         []string{opAllow, opFailure, opCooldown, opAllow, opDeny},
+			wantState:   StateHalfOpen,
+			wantChanges: []State{StateOpen, StateHalfOpen},
+		},
+		{
+			name:        "successful probe closes it",
+			threshold:   1,
+			ops:         []string{opAllow, opFailure, opCooldown, opAllow, opSuccess, opAllow},
+			wantState:   StateClosed,
+			wantChanges: []State{StateOpen, StateHalfOpen, StateClosed},
+		},
+		{
+			name:        "failed probe reopens it",
+			threshold:   1,
+			ops:         []string{opAllow, opFailure, opCooldown, opAllow, opFailure, opDeny},
+			wantState:   StateOpen,
+			wantChanges: []State{StateOpen, StateHalfOpen, StateOpen},
+		},
+		{
+			name:        "cancelled probe lets another probe through",
+			threshold:   1,
+			ops:         []string{opAllow, opFailure, opCooldown, opAllow, opCancel, opAllow, opDeny},
+			wantState:   StateHalfOpen,
+			wantChanges: []State{StateOpen, StateHalfOpen},
+		},
+		{
+			name:      "zero threshold never opens",
+			threshold: 0,
+			ops:       []string{opAllow, opFailure, opAllow, opFailure, opAllow, opFailure, opAllow},
+			wantState: StateClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changes []State
+			b := NewBreaker("pkts", tt.threshold, time.Minute, func(name string, state State) {
+				if name != "pkts" {
+					t.Errorf("onChange() name = %q, want pkts", name)
+				}
+				changes = append(changes, state)
+			})
+
+			for i, op := range tt.ops {
+				switch op {
+				case opAllow:
+					if err := b.Allow(); err != nil {
+						t.Fatalf("op %d: Allow() error = %v, want nil", i, err)
+					}
+				case opDeny:
+					if err := b.Allow(); status.Code(err) != codes.Unavailable {
+						t.Fatalf("op %d: Allow() error = %v, want Unavailable", i, err)
+					}
+				case opSuccess:
+					b.Success()
+				case opFailure:
+					b.Failure()
+				case opCancel:
+					b.Cancel()
+				case opCooldown:
+					b.mu.Lock()
+					b.openedAt = b.openedAt.Add(-b.cooldown)
+					b.mu.Unlock()
+				}
+			}
+
+			if got := b.State(); got != tt.wantState {
+				t.Errorf("State() = %s, want %s", got, tt.wantState)
+			}
+			if len(changes) != len(tt.wantChanges) {
+				t.Fatalf("state changes = %v, want %v", changes, tt.wantChanges)
+			}
+			for i := range changes {
+				if changes[i] != tt.wantChanges[i] {
+					t.Errorf("state changes = %v, want %v", changes, tt.wantChanges)
+					break
+				}
+			}
+		})
+	}
+}
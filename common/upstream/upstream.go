@@ -0,0 +1,145 @@
+package upstream
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+	"tracerstudy-auth-service/common/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Registry keeps a circuit breaker per upstream service and builds the
+// client interceptors that apply deadlines, retries and the breakers.
+type Registry struct {
+	cfg       config.Upstream
+	mu        sync.Mutex
+	breakers  map[string]*Breaker
+	listeners []func(name string, state State)
+}
+
+func NewRegistry(cfg config.Upstream) *Registry {
+	return &Registry{
+		cfg:      cfg,
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// Breaker returns the breaker of the named upstream, creating it on first
+// use.
+func (r *Registry) Breaker(name string) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[name]
+	if !ok {
+		b = NewBreaker(name, r.cfg.BreakerThreshold, r.cfg.BreakerCooldown, r.notify)
+		r.breakers[name] = b
+	}
+
+	return b
+}
+
+// Breakers returns every breaker, sorted by name.
+func (r *Registry) Breakers() []*Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+
+	sort.Slice(breakers, func(i, j int) bool {
+		return breakers[i].Name() < breakers[j].Name()
+	})
+
+	return breakers
+}
+
+// OnStateChange calls fn whenever a breaker opens, half-opens or closes.
+func (r *Registry) OnStateChange(fn func(name string, state State)) {
+	r.mu.Lock()
+	r.listeners = append(r.listeners, fn)
+	r.mu.Unlock()
+}
+
+func (r *Registry) notify(name string, state State) {
+	log.Printf("WARNING: [Upstream - %s] Circuit breaker is %s", name, state)
+
+	r.mu.Lock()
+	listeners := append([]func(string, State){}, r.listeners...)
+	r.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(name, state)
+	}
+}
+
+// UnaryClientInterceptor gives each call to the named upstream at most
+// timeout, and retries calls that fail with Unavailable after a jittered
+// exponential backoff, as long as the breaker and the caller's context
+// allow.
+func (r *Registry) UnaryClientInterceptor(name string, timeout time.Duration) grpc.UnaryClientInterceptor {
+	breaker := r.Breaker(name)
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		for attempt := 0; ; attempt++ {
+			if err := breaker.Allow(); err != nil {
+				return err
+			}
+
+			callCtx := ctx
+			var cancel context.CancelFunc
+			if timeout > 0 {
+				callCtx, cancel = context.WithTimeout(ctx, timeout)
+			}
+			err := invoker(callCtx, method, req, reply, cc, opts...)
+			if cancel != nil {
+				cancel()
+			}
+
+			code := status.Code(err)
+			switch {
+			case ctx.Err() != nil:
+				breaker.Cancel()
+			case code == codes.Unavailable || code == codes.DeadlineExceeded:
+				breaker.Failure()
+			default:
+				breaker.Success()
+			}
+
+			if code != codes.Unavailable || attempt >= r.cfg.MaxRetries {
+				return err
+			}
+
+			log.Printf("WARNING: [Upstream - %s] Retrying %s after error: %v", name, method, err)
+
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(r.backoff(attempt)):
+			}
+		}
+	}
+}
+
+// backoff picks a random delay below RetryBaseDelay doubled for each
+// attempt, capped at RetryMaxDelay, so callers retrying at once spread out.
+func (r *Registry) backoff(attempt int) time.Duration {
+	delay := r.cfg.RetryBaseDelay << attempt
+	if delay <= 0 || delay > r.cfg.RetryMaxDelay {
+		delay = r.cfg.RetryMaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
@@ -0,0 +1,176 @@
+package upstream
+
+import (
+	"context"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func testConfig() config.Upstream {
+	return config.Upstream{
+		MaxRetries:       2,
+		RetryBaseDelay:   time.Millisecond,
+		RetryMaxDelay:    5 * time.Millisecond,
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Minute,
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          func(*config.Upstream)
+		results      []codes.Code
+		wantCode     codes.Code
+		wantAttempts int
+		wantState    State
+	}{
+		{
+			name:         "success",
+			results:      []codes.Code{codes.OK},
+			wantCode:     codes.OK,
+			wantAttempts: 1,
+			wantState:    StateClosed,
+		},
+		{
+			name:         "unavailable is retried",
+			results:      []codes.Code{codes.Unavailable, codes.Unavailable, codes.OK},
+			wantCode:     codes.OK,
+			wantAttempts: 3,
+			wantState:    StateClosed,
+		},
+		{
+			name:         "retries are limited",
+			results:      []codes.Code{codes.Unavailable, codes.Unavailable, codes.Unavailable, codes.OK},
+			wantCode:     codes.Unavailable,
+			wantAttempts: 3,
+			wantState:    StateClosed,
+		},
+		{
+			name:         "deadline exceeded is not retried",
+			results:      []codes.Code{codes.DeadlineExceeded, codes.OK},
+			wantCode:     codes.DeadlineExceeded,
+			wantAttempts: 1,
+			wantState:    StateClosed,
+		},
+		{
+			name:         "application errors are not retried",
+			results:      []codes.Code{codes.NotFound, codes.OK},
+			wantCode:     codes.NotFound,
+			wantAttempts: 1,
+			wantState:    StateClosed,
+		},
+		{
+			name:         "open breaker stops retrying",
+			cfg:          func(c *config.Upstream) { c.BreakerThreshold = 2 },
+			results:      []codes.Code{codes.Unavailable, codes.Unavailable, codes.OK},
+			wantCode:     codes.Unavailable,
+			wantAttempts: 2,
+			wantState:    StateOpen,
+		},
+		{
+			name:         "no retries",
+			cfg:          func(c *config.Upstream) { c.MaxRetries = 0 },
+			results:      []codes.Code{codes.Unavailable, codes.OK},
+			wantCode:     codes.Unavailable,
+			wantAttempts: 1,
+			wantState:    StateClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			if tt.cfg != nil {
+				tt.cfg(&cfg)
+			}
+			registry := NewRegistry(cfg)
+			interceptor := registry.UnaryClientInterceptor("pkts", time.Second)
+
+			attempts := 0
+			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Second {
+					t.Errorf("attempt %d deadline = %v, %v, want at most the timeout", attempts, deadline, ok)
+				}
+				code := tt.results[attempts]
+				attempts++
+				if code == codes.OK {
+					return nil
+				}
+				return status.Error(code, code.String())
+			}
+
+			err := interceptor(context.Background(), "/pkts.PktsService/Get", nil, nil, nil, invoker)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("interceptor error = %v, want %v", err, tt.wantCode)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if got := registry.Breaker("pkts").State(); got != tt.wantState {
+				t.Errorf("breaker state = %s, want %s", got, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptorCancelled(t *testing.T) {
+	cfg := testConfig()
+	cfg.BreakerThreshold = 1
+	registry := NewRegistry(cfg)
+	interceptor := registry.UnaryClientInterceptor("pkts", 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		attempts++
+		cancel()
+		return status.Error(codes.Unavailable, "cancelled")
+	}
+
+	if err := interceptor(ctx, "/pkts.PktsService/Get", nil, nil, nil, invoker); status.Code(err) != codes.Unavailable {
+		t.Errorf("interceptor error = %v, want Unavailable", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+	// a call the caller gave up on says nothing about the upstream
+	if got := registry.Breaker("pkts").State(); got != StateClosed {
+		t.Errorf("breaker state = %s, want closed", got)
+	}
+}
+
+func TestRegistryBackoff(t *testing.T) {
+	registry := NewRegistry(config.Upstream{
+		RetryBaseDelay: 100 * time.Millisecond,
+		RetryMaxDelay:  time.Second,
+	})
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 0, max: 100 * time.Millisecond},
+		{attempt: 1, max: 200 * time.Millisecond},
+		{attempt: 3, max: 800 * time.Millisecond},
+		{attempt: 4, max: time.Second},
+		{attempt: 70, max: time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if got := registry.backoff(tt.attempt); got <= 0 || got > tt.max {
+				t.Fatalf("backoff(%d) = %v, want within (0, %v]", tt.attempt, got, tt.max)
+			}
+		}
+	}
+
+	if got := NewRegistry(config.Upstream{}).backoff(3); got != 0 {
+		t.Errorf("backoff() without delays = %v, want 0", got)
+	}
+}
@@ -2,10 +2,14 @@ package utils
 
 import (
 	"context"
+	"encoding/base64"
 	"log"
+	"net"
+	"strings"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -23,6 +27,84 @@ func GetMetadataAuthorization(ctx context.Context) (string, error) {
 	}
 
 	authHeader := values[0]
-	
+
 	return authHeader, nil
 }
+
+func GetBasicAuth(ctx context.Context) (string, string, error) {
+	authHeader, err := GetMetadataAuthorization(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	parts := strings.Fields(authHeader)
+	if len(parts) != 2 || parts[0] != "Basic" {
+		log.Println("ERROR: [Utils - GetBasicAuth] Authorization credentials in wrong format")
+		return "", "", status.Errorf(codes.Unauthenticated, "authorization credentials are invalid")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		log.Println("ERROR: [Utils - GetBasicAuth] Error while decoding authorization credentials:", err)
+		return "", "", status.Errorf(codes.Unauthenticated, "authorization credentials are invalid")
+	}
+
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		log.Println("ERROR: [Utils - GetBasicAuth] Authorization credentials in wrong format")
+		return "", "", status.Errorf(codes.Unauthenticated, "authorization credentials are invalid")
+	}
+
+	return username, password, nil
+}
+
+func GetBearerToken(ctx context.Context) (string, error) {
+	authHeader, err := GetMetadataAuthorization(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	parts := strings.Fields(authHeader)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		log.Println("ERROR: [Utils - GetBearerToken] Authorization token in wrong format")
+		return "", status.Errorf(codes.Unauthenticated, "authorization token is invalid")
+	}
+
+	return parts[1], nil
+}
+
+// GetClientIP returns the address of the caller. Calls relayed by the local
+// REST gateway carry the client address as the last x-forwarded-for entry;
+// earlier entries come from the client and are not trusted.
+func GetClientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return host
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return host
+	}
+
+	values := md.Get("x-forwarded-for")
+	if len(values) == 0 {
+		return host
+	}
+
+	entries := strings.Split(values[len(values)-1], ",")
+	if forwarded := strings.TrimSpace(entries[len(entries)-1]); forwarded != "" {
+		return forwarded
+	}
+
+	return host
+}
@@ -1,27 +0,0 @@
-package utils
-
-import (
-	"log"
-
-	"golang.org/x/crypto/bcrypt"
-)
-
-func HashPassword(password string) string {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-
-	if err != nil {
-		log.Println("ERROR: [Utils - HashPassword] Error while generate password hash:", err)
-	}
-
-	return string(bytes)
-}
-
-func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-
-	if err != nil {
-		log.Println("ERROR: [Utils - CheckPasswordHash] Error while compare password hash:", err)
-	}
-
-	return err == nil
-}
@@ -1,16 +1,26 @@
 package auth
 
 import (
+	"tracerstudy-auth-service/common/certs"
 	"tracerstudy-auth-service/common/config"
 	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/mailer"
+	"tracerstudy-auth-service/common/password"
+	"tracerstudy-auth-service/common/upstream"
 	"tracerstudy-auth-service/modules/auth/builder"
+	"tracerstudy-auth-service/modules/auth/handler"
 	"tracerstudy-auth-service/pb"
+	"tracerstudy-auth-service/server"
 
 	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
-func InitGrpc(server *grpc.Server, cfg config.Config, db *gorm.DB, jwtManager *commonJwt.JWT, grpcConn *grpc.ClientConn) {
-	auth := builder.BuildAuthHandler(cfg, db, jwtManager, grpcConn)
+func InitGrpc(server *grpc.Server, cfg config.Config, db *gorm.DB, jwtManager *commonJwt.JWT, keyCipher *commonJwt.KeyCipher, passwordHasher password.Hasher, mailer mailer.Mailer, upstreams *upstream.Registry, tlsReloader *certs.Reloader, grpcConn *grpc.ClientConn) {
+	auth := builder.BuildAuthHandler(cfg, db, jwtManager, keyCipher, passwordHasher, mailer, upstreams, tlsReloader, grpcConn)
 	pb.RegisterAuthServiceServer(server, auth)
 }
+
+func InitRest(rest *server.Rest, jwtManager *commonJwt.JWT) error {
+	return rest.HandlePath("GET", handler.JwksPath, handler.NewJwksRestHandler(jwtManager))
+}
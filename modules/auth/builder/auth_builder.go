@@ -1,23 +1,77 @@
 package builder
 
 import (
+	"tracerstudy-auth-service/common/authorization"
+	"tracerstudy-auth-service/common/certs"
 	"tracerstudy-auth-service/common/config"
 	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/mailer"
+	"tracerstudy-auth-service/common/password"
+	"tracerstudy-auth-service/common/throttle"
+	"tracerstudy-auth-service/common/upstream"
 	"tracerstudy-auth-service/modules/auth/client"
 	"tracerstudy-auth-service/modules/auth/handler"
+	authRepo "tracerstudy-auth-service/modules/auth/repository"
+	authSvc "tracerstudy-auth-service/modules/auth/service"
+	roleRepo "tracerstudy-auth-service/modules/role/repository"
 	userRepo "tracerstudy-auth-service/modules/user/repository"
 	userSvc "tracerstudy-auth-service/modules/user/service"
+	"tracerstudy-auth-service/server"
 
 	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
-func BuildAuthHandler(cfg config.Config, db *gorm.DB, jwtManager *commonJwt.JWT, grpcConn *grpc.ClientConn) *handler.AuthHandler {
+func BuildAuthHandler(cfg config.Config, db *gorm.DB, jwtManager *commonJwt.JWT, keyCipher *commonJwt.KeyCipher, passwordHasher password.Hasher, mailer mailer.Mailer, upstreams *upstream.Registry, tlsReloader *certs.Reloader, grpcConn *grpc.ClientConn) *handler.AuthHandler {
 	userRepository := userRepo.NewUserRepository(db)
-	userSvc := userSvc.NewUserService(cfg, userRepository)
+	userScopeRepository := userRepo.NewUserScopeRepository(db)
+	roleRepository := roleRepo.NewRoleRepository(db)
+	emailVerificationRepository := userRepo.NewEmailVerificationRepository(db)
+	emailVerificationSvc := userSvc.NewEmailVerificationService(cfg, emailVerificationRepository, userRepository, mailer)
+	alumniRepository := userRepo.NewAlumniRepository(db)
+	alumniSvc := userSvc.NewAlumniService(cfg, alumniRepository, passwordHasher)
+	userSvc := userSvc.NewUserService(cfg, userRepository, userScopeRepository, roleRepository, jwtManager, passwordHasher, emailVerificationSvc)
 
-	pktsSvc := client.BuildPktsServiceClient(cfg.ClientURL.Pkts)
-	mhsSvc := client.BuildMhsBiodataServiceClient(cfg.ClientURL.MhsBiodata)
+	refreshTokenRepository := authRepo.NewRefreshTokenRepository(db)
+	refreshTokenSvc := authSvc.NewRefreshTokenService(cfg, refreshTokenRepository, jwtManager)
 
-	return handler.NewAuthHandler(cfg, userSvc, jwtManager, pktsSvc, mhsSvc)
+	signingKeyRepository := authRepo.NewSigningKeyRepository(db)
+	signingKeySvc := authSvc.NewSigningKeyService(cfg, signingKeyRepository, jwtManager, keyCipher)
+
+	passwordResetRepository := authRepo.NewPasswordResetRepository(db)
+	passwordResetSvc := authSvc.NewPasswordResetService(cfg, passwordResetRepository, userSvc, mailer)
+
+	pktsSvc := client.BuildPktsServiceClient(cfg.ClientURL.Pkts, upstreams, cfg.Upstream.PktsTimeout, upstreamDialOptions(cfg.TLS, cfg.TLS.Pkts, cfg.TLS.PktsCAFile, tlsReloader)...)
+	mhsSvc := client.BuildMhsBiodataServiceClient(cfg.ClientURL.MhsBiodata, upstreams, cfg.Upstream.MhsBiodataTimeout, upstreamDialOptions(cfg.TLS, cfg.TLS.MhsBiodata, cfg.TLS.MhsBiodataCAFile, tlsReloader)...)
+	cachedMhsSvc := client.NewCachedMhsBiodataApiServiceClient(&mhsSvc, cfg.Biodata.CacheTTL, cfg.Biodata.StaleTTL)
+
+	userStudyLoginRepository := authRepo.NewUserStudyLoginRepository(db)
+	userStudyGrantRepository := authRepo.NewUserStudyGrantRepository(db)
+	userStudyLoginSvc := authSvc.NewUserStudyLoginService(cfg, userStudyLoginRepository, userStudyGrantRepository, pktsSvc, mailer)
+
+	mfaRepository := authRepo.NewMfaRepository(db)
+	mfaChallengeRepository := authRepo.NewMfaChallengeRepository(db)
+	mfaSvc := authSvc.NewMfaService(cfg, mfaRepository, mfaChallengeRepository)
+
+	var loginAttemptStore throttle.Store = throttle.NewMemoryStore()
+	if cfg.Throttle.Store == throttle.StoreDatabase {
+		loginAttemptStore = authRepo.NewLoginAttemptRepository(db)
+	}
+	throttler := throttle.NewThrottler(cfg.Throttle, loginAttemptStore)
+
+	return handler.NewAuthHandler(cfg, userSvc, alumniSvc, refreshTokenSvc, signingKeySvc, passwordResetSvc, emailVerificationSvc, userStudyLoginSvc, mfaSvc, throttler, jwtManager, pktsSvc, cachedMhsSvc, authorization.NewServiceClients(cfg.ServiceAuth.Clients))
+}
+
+// upstreamDialOptions dials an upstream over TLS when enabled for it,
+// verifying it against its own CA bundle or else the shared one.
+func upstreamDialOptions(cfg config.TLS, enabled bool, caFile string, tlsReloader *certs.Reloader) []server.DialOption {
+	if !enabled {
+		return nil
+	}
+
+	if caFile == "" {
+		caFile = cfg.CAFile
+	}
+
+	return []server.DialOption{server.WithTLS(tlsReloader, cfg, caFile, "")}
 }
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"sync"
+	"time"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/pb"
+)
+
+const (
+	maxCachedBiodata = 50000
+)
+
+// MhsBiodataApi is the part of the biodata API that alumni logins use.
+type MhsBiodataApi interface {
+	CheckMhsAlumni(ctx context.Context, nim string, tglSidang string) (*pb.CheckMhsAlumniResponse, error)
+	FetchMhsBiodataByNim(ctx context.Context, nim string) (*pb.MhsBiodataApiResponse, error)
+}
+
+// CachedMhsBiodataApiServiceClient keeps successful biodata API responses in
+// memory for ttl. For staleTTL after that they are still returned right away
+// while a fresh copy is fetched in the background, so a slow API only slows
+// down the first login of an alumnus in a while.
+type CachedMhsBiodataApiServiceClient struct {
+	client  MhsBiodataApi
+	checks  *staleCache[*pb.CheckMhsAlumniResponse]
+	biodata *staleCache[*pb.MhsBiodataApiResponse]
+}
+
+func NewCachedMhsBiodataApiServiceClient(client MhsBiodataApi, ttl, staleTTL time.Duration) *CachedMhsBiodataApiServiceClient {
+	return &CachedMhsBiodataApiServiceClient{
+		client:  client,
+		checks:  newStaleCache[*pb.CheckMhsAlumniResponse]("CheckMhsAlumni", ttl, staleTTL),
+		biodata: newStaleCache[*pb.MhsBiodataApiResponse]("FetchMhsBiodataByNim", ttl, staleTTL),
+	}
+}
+
+func (c *CachedMhsBiodataApiServiceClient) CheckMhsAlumni(ctx context.Context, nim string, tglSidang string) (*pb.CheckMhsAlumniResponse, error) {
+	// the graduation date is a login secret, so it is not kept as a key
+	return c.checks.get(ctx, utils.HashToken(nim+"\x00"+tglSidang), func(ctx context.Context) (*pb.CheckMhsAlumniResponse, error) {
+		return c.client.CheckMhsAlumni(ctx, nim, tglSidang)
+	})
+}
+
+func (c *CachedMhsBiodataApiServiceClient) FetchMhsBiodataByNim(ctx context.Context, nim string) (*pb.MhsBiodataApiResponse, error) {
+	return c.biodata.get(ctx, nim, func(ctx context.Context) (*pb.MhsBiodataApiResponse, error) {
+		return c.client.FetchMhsBiodataByNim(ctx, nim)
+	})
+}
+
+type staleEntry[T any] struct {
+	key        string
+	value      T
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+type staleCache[T any] struct {
+	name     string
+	ttl      time.Duration
+	staleTTL time.Duration
+	size     int
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	// recent orders the entries from most to least recently used
+	recent *list.List
+}
+
+func newStaleCache[T any](name string, ttl, staleTTL time.Duration) *staleCache[T] {
+	return &staleCache[T]{
+		name:     name,
+		ttl:      ttl,
+		staleTTL: staleTTL,
+		size:     maxCachedBiodata,
+		entries:  make(map[string]*list.Element),
+		recent:   list.New(),
+	}
+}
+
+// get returns the value cached under key, calling fetch when there is none
+// or it is too old to be served. Errors are never cached. Background
+// refreshes do not use ctx, which ends with the request that triggered them.
+func (c *staleCache[T]) get(ctx context.Context, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	if c.ttl+c.staleTTL <= 0 {
+		return fetch(ctx)
+	}
+
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*staleEntry[T])
+		c.recent.MoveToFront(element)
+		age := time.Since(entry.fetchedAt)
+		if age < c.ttl+c.staleTTL {
+			if age >= c.ttl && !entry.refreshing {
+				entry.refreshing = true
+				go c.refresh(key, fetch)
+			}
+			value := entry.value
+			c.mu.Unlock()
+			return value, nil
+		}
+	}
+	c.mu.Unlock()
+
+	value, err := fetch(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	c.save(key, value)
+
+	return value, nil
+}
+
+func (c *staleCache[T]) refresh(key string, fetch func(ctx context.Context) (T, error)) {
+	value, err := fetch(context.Background())
+	if err != nil {
+		log.Println("WARNING: [MhsBiodataApiCache - refresh] Error while refreshing", c.name, "response:", err)
+		c.mu.Lock()
+		if element, ok := c.entries[key]; ok {
+			element.Value.(*staleEntry[T]).refreshing = false
+		}
+		c.mu.Unlock()
+		return
+	}
+
+	c.save(key, value)
+}
+
+// save stores value under key, evicting the least recently used entries
+// beyond the cache size.
+func (c *staleCache[T]) save(key string, value T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &staleEntry[T]{key: key, value: value, fetchedAt: time.Now()}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.recent.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.recent.PushFront(entry)
+
+	for c.recent.Len() > c.size {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.entries, oldest.Value.(*staleEntry[T]).key)
+	}
+}
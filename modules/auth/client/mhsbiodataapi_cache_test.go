@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/pb"
+)
+
+type fakeMhsBiodataApi struct {
+	MhsBiodataApi
+	calls int
+}
+
+func (c *fakeMhsBiodataApi) CheckMhsAlumni(ctx context.Context, nim string, tglSidang string) (*pb.CheckMhsAlumniResponse, error) {
+	c.calls++
+	return &pb.CheckMhsAlumniResponse{}, nil
+}
+
+func TestStaleCacheEviction(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      []string
+		wantKept  []string
+		wantGone  []string
+		wantCount int
+	}{
+		{
+			name:      "below the size",
+			keys:      []string{"a", "b"},
+			wantKept:  []string{"a", "b"},
+			wantCount: 2,
+		},
+		{
+			name:      "least recently saved entry is evicted",
+			keys:      []string{"a", "b", "c", "d"},
+			wantKept:  []string{"b", "c", "d"},
+			wantGone:  []string{"a"},
+			wantCount: 3,
+		},
+		{
+			name:      "reading an entry keeps it",
+			keys:      []string{"a", "b", "c", "a", "d"},
+			wantKept:  []string{"a", "c", "d"},
+			wantGone:  []string{"b"},
+			wantCount: 3,
+		},
+		{
+			name:      "saving an entry again does not grow the cache",
+			keys:      []string{"a", "a", "a"},
+			wantKept:  []string{"a"},
+			wantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newStaleCache[string]("test", time.Hour, 0)
+			c.size = 3
+
+			fetch := func(key string) func(context.Context) (string, error) {
+				return func(context.Context) (string, error) { return key, nil }
+			}
+			for _, key := range tt.keys {
+				if _, err := c.get(context.Background(), key, fetch(key)); err != nil {
+					t.Fatalf("get() error = %v", err)
+				}
+			}
+
+			if len(c.entries) != tt.wantCount || c.recent.Len() != tt.wantCount {
+				t.Errorf("cached entries = %d in map and %d in list, want %d", len(c.entries), c.recent.Len(), tt.wantCount)
+			}
+			for _, key := range tt.wantKept {
+				if _, ok := c.entries[key]; !ok {
+					t.Errorf("entry %q was evicted", key)
+				}
+			}
+			for _, key := range tt.wantGone {
+				if _, ok := c.entries[key]; ok {
+					t.Errorf("entry %q was kept", key)
+				}
+			}
+		})
+	}
+}
+
+func TestCachedMhsBiodataApiCheckMhsAlumni(t *testing.T) {
+	api := &fakeMhsBiodataApi{}
+	c := NewCachedMhsBiodataApiServiceClient(api, time.Hour, time.Hour)
+	c.checks.size = 2
+
+	for i := 0; i < 5; i++ {
+		if _, err := c.CheckMhsAlumni(context.Background(), fmt.Sprintf("19115210%02d", i), "2023-08-17"); err != nil {
+			t.Fatalf("CheckMhsAlumni() error = %v", err)
+		}
+	}
+	if _, err := c.CheckMhsAlumni(context.Background(), "1911521004", "2023-08-17"); err != nil {
+		t.Fatalf("CheckMhsAlumni() error = %v", err)
+	}
+
+	if api.calls != 5 {
+		t.Errorf("biodata API calls = %d, want 5", api.calls)
+	}
+	if len(c.checks.entries) != 2 {
+		t.Errorf("cached checks = %d, want 2", len(c.checks.entries))
+	}
+	for key := range c.checks.entries {
+		if strings.Contains(key, "2023-08-17") || strings.Contains(key, "19115210") {
+			t.Errorf("cache key %q contains the login data", key)
+		}
+	}
+}
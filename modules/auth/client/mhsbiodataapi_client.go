@@ -2,16 +2,23 @@ package client
 
 import (
 	"context"
+	"time"
+	"tracerstudy-auth-service/common/upstream"
 	"tracerstudy-auth-service/pb"
 	"tracerstudy-auth-service/server"
 )
 
+const (
+	MhsBiodataUpstream = "mhsbiodata"
+)
+
 type MhsBiodataApiServiceClient struct {
 	Client pb.MhsBiodataApiServiceClient
 }
 
-func BuildMhsBiodataServiceClient(url string) MhsBiodataApiServiceClient {
-	cc := server.InitGRPCConn(url, false, "")
+func BuildMhsBiodataServiceClient(url string, upstreams *upstream.Registry, timeout time.Duration, opts ...server.DialOption) MhsBiodataApiServiceClient {
+	opts = append(opts, server.WithUnaryInterceptor(upstreams.UnaryClientInterceptor(MhsBiodataUpstream, timeout)))
+	cc := server.InitGRPCConn(url, opts...)
 
 	c := MhsBiodataApiServiceClient{
 		Client: pb.NewMhsBiodataApiServiceClient(cc),
@@ -20,11 +27,19 @@ func BuildMhsBiodataServiceClient(url string) MhsBiodataApiServiceClient {
 	return c
 }
 
-func (mc *MhsBiodataApiServiceClient) CheckMhsAlumni(nim string, tglSidang string) (*pb.CheckMhsAlumniResponse, error) {
+func (mc *MhsBiodataApiServiceClient) CheckMhsAlumni(ctx context.Context, nim string, tglSidang string) (*pb.CheckMhsAlumniResponse, error) {
 	req := &pb.CheckMhsAlumniRequest{
 		Nim:       nim,
 		TglSidang: tglSidang,
 	}
 
-	return mc.Client.CheckMhsAlumni(context.Background(), req)
+	return mc.Client.CheckMhsAlumni(ctx, req)
+}
+
+func (mc *MhsBiodataApiServiceClient) FetchMhsBiodataByNim(ctx context.Context, nim string) (*pb.MhsBiodataApiResponse, error) {
+	req := &pb.MhsBiodataApiRequest{
+		Nim: nim,
+	}
+
+	return mc.Client.FetchMhsBiodataByNim(ctx, req)
 }
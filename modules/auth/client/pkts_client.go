@@ -2,16 +2,23 @@ package client
 
 import (
 	"context"
+	"time"
+	"tracerstudy-auth-service/common/upstream"
 	"tracerstudy-auth-service/pb"
 	"tracerstudy-auth-service/server"
 )
 
+const (
+	PktsUpstream = "pkts"
+)
+
 type PktsServiceClient struct {
 	Client pb.PKTSServiceClient
 }
 
-func BuildPktsServiceClient(url string) PktsServiceClient {
-	cc := server.InitGRPCConn(url, false, "")
+func BuildPktsServiceClient(url string, upstreams *upstream.Registry, timeout time.Duration, opts ...server.DialOption) PktsServiceClient {
+	opts = append(opts, server.WithUnaryInterceptor(upstreams.UnaryClientInterceptor(PktsUpstream, timeout)))
+	cc := server.InitGRPCConn(url, opts...)
 
 	c := PktsServiceClient{
 		Client: pb.NewPKTSServiceClient(cc),
@@ -20,12 +27,12 @@ func BuildPktsServiceClient(url string) PktsServiceClient {
 	return c
 }
 
-func (c *PktsServiceClient) GetNimByDataAtasan(nama, email, hp string) (*pb.GetNimByDataAtasanResponse, error) {
+func (c *PktsServiceClient) GetNimByDataAtasan(ctx context.Context, nama, email, hp string) (*pb.GetNimByDataAtasanResponse, error) {
 	req := &pb.GetNimByDataAtasanRequest{
 		NamaAtasan:  nama,
 		EmailAtasan: email,
 		HpAtasan:    hp,
 	}
 
-	return c.Client.GetNimByDataAtasan(context.Background(), req)
+	return c.Client.GetNimByDataAtasan(ctx, req)
 }
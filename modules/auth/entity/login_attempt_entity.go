@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	LoginAttemptTableName = "login_attempts"
+)
+
+// LoginAttempt counts the consecutive failed logins of an account or a
+// client address, named by ThrottleKey.
+type LoginAttempt struct {
+	ThrottleKey   string    `gorm:"primaryKey;size:191" json:"throttle_key"`
+	Failures      uint32    `json:"failures"`
+	LastFailureAt time.Time `gorm:"index" json:"last_failure_at"`
+}
+
+func NewLoginAttempt(key string, at time.Time) *LoginAttempt {
+	return &LoginAttempt{
+		ThrottleKey:   key,
+		Failures:      1,
+		LastFailureAt: at,
+	}
+}
+
+func (l *LoginAttempt) TableName() string {
+	return LoginAttemptTableName
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	MfaCredentialTableName   = "mfa_credentials"
+	MfaRecoveryCodeTableName = "mfa_recovery_codes"
+	MfaChallengeTableName    = "mfa_challenges"
+
+	MfaChallengeVerify = "verify"
+	MfaChallengeEnroll = "enroll"
+)
+
+// MfaCredential holds the TOTP secret of a user. It is pending until the
+// user confirms it with a code, which sets EnabledAt. LastUsedStep is the
+// time step of the last accepted code, so codes cannot be replayed.
+type MfaCredential struct {
+	UserId       uint64     `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	Secret       string     `gorm:"size:64" json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `json:"last_used_step"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// MfaRecoveryCode is a single-use code that stands in for a TOTP code when
+// the authenticator is lost. Only its hash is stored.
+type MfaRecoveryCode struct {
+	Id        uint64     `json:"id"`
+	UserId    uint64     `gorm:"index" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;uniqueIndex" json:"code_hash"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MfaChallenge is the second step of a login whose password was accepted.
+// A verify challenge is answered with a code; an enroll challenge lets a
+// user who must use MFA enroll before receiving tokens.
+type MfaChallenge struct {
+	Id        uint64     `json:"id"`
+	UserId    uint64     `gorm:"index" json:"user_id"`
+	TokenHash string     `gorm:"size:64;uniqueIndex" json:"token_hash"`
+	Purpose   string     `gorm:"size:16" json:"purpose"`
+	Attempts  uint32     `json:"attempts"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewMfaCredential(userId uint64, secret string) *MfaCredential {
+	return &MfaCredential{
+		UserId:    userId,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func NewMfaRecoveryCode(userId uint64, codeHash string) *MfaRecoveryCode {
+	return &MfaRecoveryCode{
+		UserId:    userId,
+		CodeHash:  codeHash,
+		CreatedAt: time.Now(),
+	}
+}
+
+func NewMfaChallenge(userId uint64, tokenHash, purpose string, expiresAt time.Time) *MfaChallenge {
+	return &MfaChallenge{
+		UserId:    userId,
+		TokenHash: tokenHash,
+		Purpose:   purpose,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (c *MfaCredential) Enabled() bool {
+	return c.EnabledAt != nil
+}
+
+func (c *MfaCredential) TableName() string {
+	return MfaCredentialTableName
+}
+
+func (c *MfaRecoveryCode) TableName() string {
+	return MfaRecoveryCodeTableName
+}
+
+func (c *MfaChallenge) TableName() string {
+	return MfaChallengeTableName
+}
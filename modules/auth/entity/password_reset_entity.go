@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	PasswordResetTokenTableName = "password_reset_tokens"
+)
+
+// PasswordResetToken is a single-use password reset token. Only the hash of
+// the token is stored; the token itself is only ever mailed to the user.
+type PasswordResetToken struct {
+	Id        uint64     `json:"id"`
+	UserId    uint64     `gorm:"index" json:"user_id"`
+	TokenHash string     `gorm:"size:64;uniqueIndex" json:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewPasswordResetToken(userId uint64, tokenHash string, expiresAt time.Time) *PasswordResetToken {
+	return &PasswordResetToken{
+		UserId:    userId,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (t *PasswordResetToken) TableName() string {
+	return PasswordResetTokenTableName
+}
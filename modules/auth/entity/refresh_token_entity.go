@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	RefreshTokenTableName = "refresh_tokens"
+)
+
+type RefreshToken struct {
+	Id        uint64     `json:"id"`
+	FamilyId  string     `gorm:"size:64;index" json:"family_id"`
+	TokenHash string     `gorm:"size:64;uniqueIndex" json:"token_hash"`
+	Subject   string     `json:"subject"`
+	Role      uint32     `json:"role"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewRefreshToken(familyId, tokenHash, subject string, role uint32, expiresAt time.Time) *RefreshToken {
+	return &RefreshToken{
+		FamilyId:  familyId,
+		TokenHash: tokenHash,
+		Subject:   subject,
+		Role:      role,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (r *RefreshToken) TableName() string {
+	return RefreshTokenTableName
+}
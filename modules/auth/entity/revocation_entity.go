@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	RevokedTokenTableName      = "revoked_tokens"
+	SubjectRevocationTableName = "subject_revocations"
+)
+
+// RevokedToken invalidates a single token. ExpiresAt is when the token stops
+// verifying on its own, after which the row is deleted.
+type RevokedToken struct {
+	Jti       string    `gorm:"primaryKey;size:64" json:"jti"`
+	Subject   string    `gorm:"index" json:"subject"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// SubjectRevocation invalidates every token of a subject issued at or
+// before RevokedAt.
+type SubjectRevocation struct {
+	Subject   string    `gorm:"primaryKey;size:255" json:"subject"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+func NewRevokedToken(jti, subject string, expiresAt time.Time) *RevokedToken {
+	return &RevokedToken{
+		Jti:       jti,
+		Subject:   subject,
+		ExpiresAt: expiresAt,
+		RevokedAt: time.Now(),
+	}
+}
+
+func NewSubjectRevocation(subject string, revokedAt time.Time) *SubjectRevocation {
+	return &SubjectRevocation{
+		Subject:   subject,
+		RevokedAt: revokedAt,
+	}
+}
+
+func (r *RevokedToken) TableName() string {
+	return RevokedTokenTableName
+}
+
+func (s *SubjectRevocation) TableName() string {
+	return SubjectRevocationTableName
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+	"tracerstudy-auth-service/pb"
+)
+
+const (
+	SigningKeyTableName = "signing_keys"
+
+	SigningKeyStatusActive    = "active"
+	SigningKeyStatusScheduled = "scheduled"
+	SigningKeyStatusStandby   = "standby"
+	SigningKeyStatusRetired   = "retired"
+)
+
+// SigningKey is a persisted JWT signing key. PrivateKey is sealed with the
+// key encryption key, and empty for the bootstrap key, whose material comes
+// from configuration on every replica.
+type SigningKey struct {
+	Kid         string     `gorm:"primaryKey;size:64" json:"kid"`
+	Alg         string     `gorm:"size:16" json:"alg"`
+	PrivateKey  string     `gorm:"type:text" json:"-"`
+	ActivatesAt *time.Time `json:"activates_at"`
+	RetiredAt   *time.Time `json:"retired_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func NewSigningKey(kid, alg, privateKey string, activatesAt *time.Time) *SigningKey {
+	return &SigningKey{
+		Kid:         kid,
+		Alg:         alg,
+		PrivateKey:  privateKey,
+		ActivatesAt: activatesAt,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func (k *SigningKey) TableName() string {
+	return SigningKeyTableName
+}
+
+func ConvertSigningKeyEntityToProto(k *SigningKey, status string) *pb.SigningKey {
+	key := &pb.SigningKey{
+		Kid:       k.Kid,
+		Alg:       k.Alg,
+		Status:    status,
+		CreatedAt: k.CreatedAt.Format(time.RFC3339),
+	}
+
+	if k.ActivatesAt != nil {
+		key.ActivatesAt = k.ActivatesAt.Format(time.RFC3339)
+	}
+
+	if k.RetiredAt != nil {
+		key.RetiredAt = k.RetiredAt.Format(time.RFC3339)
+	}
+
+	return key
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	UserStudyGrantTableName = "user_study_grants"
+)
+
+// UserStudyGrant holds the alumni an employer may evaluate when there are
+// too many NIMs to embed in the access token. The token carries the grant
+// id instead, and the grant lives as long as the token does.
+type UserStudyGrant struct {
+	Id        string    `gorm:"size:64;primaryKey" json:"id"`
+	Email     string    `gorm:"size:255" json:"email"`
+	Nims      []string  `gorm:"type:text;serializer:json" json:"nims"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewUserStudyGrant(id, email string, nims []string, expiresAt time.Time) *UserStudyGrant {
+	return &UserStudyGrant{
+		Id:        id,
+		Email:     strings.ToLower(email),
+		Nims:      nims,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (g *UserStudyGrant) TableName() string {
+	return UserStudyGrantTableName
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	UserStudyLoginTokenTableName = "user_study_login_tokens"
+)
+
+// UserStudyLoginToken is a single-use login link for an alumni employer,
+// who has no account of their own and is known only by email. Only the hash
+// of the token is stored; the token itself is only ever mailed.
+type UserStudyLoginToken struct {
+	Id        uint64     `json:"id"`
+	Email     string     `gorm:"size:255;index" json:"email"`
+	TokenHash string     `gorm:"size:64;uniqueIndex" json:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewUserStudyLoginToken(email, tokenHash string, expiresAt time.Time) *UserStudyLoginToken {
+	return &UserStudyLoginToken{
+		Email:     strings.ToLower(email),
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (t *UserStudyLoginToken) TableName() string {
+	return UserStudyLoginTokenTableName
+}
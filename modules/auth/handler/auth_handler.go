@@ -5,12 +5,16 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"tracerstudy-auth-service/common/authorization"
 	"tracerstudy-auth-service/common/config"
 	"tracerstudy-auth-service/common/errors"
 	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/throttle"
 	"tracerstudy-auth-service/common/utils"
 
 	"tracerstudy-auth-service/modules/auth/client"
+	authEntity "tracerstudy-auth-service/modules/auth/entity"
+	authSvc "tracerstudy-auth-service/modules/auth/service"
 	"tracerstudy-auth-service/modules/user/entity"
 	userSvc "tracerstudy-auth-service/modules/user/service"
 	"tracerstudy-auth-service/pb"
@@ -23,33 +27,72 @@ import (
 
 type AuthHandler struct {
 	pb.UnimplementedAuthServiceServer
-	config     config.Config
-	userSvc    userSvc.UserServiceUseCase
-	jwtManager *commonJwt.JWT
-	pktsSvc    client.PktsServiceClient
-	mhsApiSvc  client.MhsBiodataApiServiceClient
+	config            config.Config
+	userSvc           userSvc.UserServiceUseCase
+	alumniSvc         userSvc.AlumniServiceUseCase
+	refreshTokenSvc   authSvc.RefreshTokenServiceUseCase
+	signingKeySvc     authSvc.SigningKeyServiceUseCase
+	passwordResetSvc  authSvc.PasswordResetServiceUseCase
+	emailVerifySvc    userSvc.EmailVerificationServiceUseCase
+	userStudyLoginSvc authSvc.UserStudyLoginServiceUseCase
+	mfaSvc            authSvc.MfaServiceUseCase
+	throttler         *throttle.Throttler
+	jwtManager        *commonJwt.JWT
+	pktsSvc           client.PktsServiceClient
+	mhsApiSvc         client.MhsBiodataApi
+	serviceClients    *authorization.ServiceClients
 }
 
 func NewAuthHandler(
 	config config.Config,
 	userService userSvc.UserServiceUseCase,
+	alumniService userSvc.AlumniServiceUseCase,
+	refreshTokenService authSvc.RefreshTokenServiceUseCase,
+	signingKeyService authSvc.SigningKeyServiceUseCase,
+	passwordResetService authSvc.PasswordResetServiceUseCase,
+	emailVerificationService userSvc.EmailVerificationServiceUseCase,
+	userStudyLoginService authSvc.UserStudyLoginServiceUseCase,
+	mfaService authSvc.MfaServiceUseCase,
+	throttler *throttle.Throttler,
 	jwtManager *commonJwt.JWT,
 	pktsService client.PktsServiceClient,
-	mhsApiService client.MhsBiodataApiServiceClient,
+	mhsApiService client.MhsBiodataApi,
+	serviceClients *authorization.ServiceClients,
 ) *AuthHandler {
 	return &AuthHandler{
-		config:     config,
-		userSvc:    userService,
-		jwtManager: jwtManager,
-		pktsSvc:    pktsService,
-		mhsApiSvc:  mhsApiService,
+		config:            config,
+		userSvc:           userService,
+		alumniSvc:         alumniService,
+		refreshTokenSvc:   refreshTokenService,
+		signingKeySvc:     signingKeyService,
+		passwordResetSvc:  passwordResetService,
+		emailVerifySvc:    emailVerificationService,
+		userStudyLoginSvc: userStudyLoginService,
+		mfaSvc:            mfaService,
+		throttler:         throttler,
+		jwtManager:        jwtManager,
+		pktsSvc:           pktsService,
+		mhsApiSvc:         mhsApiService,
+		serviceClients:    serviceClients,
 	}
 }
 
 func (ah *AuthHandler) LoginAlumni(ctx context.Context, req *pb.LoginAlumniRequest) (*pb.LoginResponse, error) {
-	res, err := ah.mhsApiSvc.CheckMhsAlumni(req.GetNim(), req.GetTanggalSidang())
+	account := throttle.AccountKey("alumni", req.GetNim())
+	client := throttle.ClientKey(utils.GetClientIP(ctx))
+	if throttled, err := ah.checkLoginThrottle(ctx, "LoginAlumni", account, client); err != nil {
+		return throttled, err
+	}
+
+	res, err := ah.mhsApiSvc.CheckMhsAlumni(ctx, req.GetNim(), req.GetTanggalSidang())
 	if err != nil {
 		parseError := errors.ParseError(err)
+		if res, err := ah.degradedAlumniLogin(ctx, "LoginAlumni", req, parseError.Code, account, client); res != nil {
+			return res, err
+		}
+		if parseError.Code == codes.NotFound || parseError.Code == codes.InvalidArgument {
+			ah.recordLoginFailure(ctx, "LoginAlumni", account, client)
+		}
 		log.Println("ERROR: [AuthHandler - LoginAlumni] Error checking mhs biodata:", parseError.Message)
 		// return nil, status.Errorf(parseError.Code, parseError.Message)
 		return &pb.LoginResponse{
@@ -59,6 +102,7 @@ func (ah *AuthHandler) LoginAlumni(ctx context.Context, req *pb.LoginAlumniReque
 	}
 
 	if !res.GetIsAlumni() {
+		ah.recordLoginFailure(ctx, "LoginAlumni", account, client)
 		message := res.GetMessage()
 		log.Println("WARNING: [AuthHandler - LoginAlumni]", message)
 		// return nil, status.Errorf(codes.PermissionDenied, "mahasiswa is not an alumni")
@@ -68,12 +112,75 @@ func (ah *AuthHandler) LoginAlumni(ctx context.Context, req *pb.LoginAlumniReque
 		}, status.Errorf(codes.PermissionDenied, message)
 	}
 
-	// generate token with cred = nim, role = 6 (alumni)
-	token, err := ah.jwtManager.GenerateToken(req.GetNim(), 6)
+	biodata, err := ah.mhsApiSvc.FetchMhsBiodataByNim(ctx, req.GetNim())
+	if err == nil && biodata.GetData().GetNIM() == "" {
+		err = status.Errorf(codes.NotFound, "mhs biodata not found")
+	}
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if res, err := ah.degradedAlumniLogin(ctx, "LoginAlumni", req, parseError.Code, account, client); res != nil {
+			return res, err
+		}
+		log.Println("ERROR: [AuthHandler - LoginAlumni] Error while fetching mhs biodata:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
 
+	alumni, err := ah.alumniSvc.Provision(ctx, biodata.GetData(), req.GetTanggalSidang())
 	if err != nil {
 		parseError := errors.ParseError(err)
-		log.Println("ERROR: [AuthHandler - LoginAlumni] Error while generating token:", parseError.Message)
+		log.Println("ERROR: [AuthHandler - LoginAlumni] Error while provisioning alumni:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return ah.alumniLoginResponse(ctx, "LoginAlumni", alumni, account)
+}
+
+// degradedAlumniLogin logs an alumnus in from their local record when
+// degraded login is enabled and the biodata API failed with code because it
+// is unavailable. A tanggal sidang not matching the record counts as a
+// failed login. It returns a nil response when the login has to fail with
+// the API error instead.
+func (ah *AuthHandler) degradedAlumniLogin(ctx context.Context, method string, req *pb.LoginAlumniRequest, code codes.Code, account, client string) (*pb.LoginResponse, error) {
+	if !ah.config.Biodata.DegradedLogin || (code != codes.Unavailable && code != codes.DeadlineExceeded) {
+		return nil, nil
+	}
+
+	alumni, err := ah.alumniSvc.LoginFromCache(ctx, req.GetNim(), req.GetTanggalSidang())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code != codes.PermissionDenied {
+			log.Printf("ERROR: [AuthHandler - %s] Error while checking local alumni record: %s", method, parseError.Message)
+			return nil, nil
+		}
+
+		ah.recordLoginFailure(ctx, method, account, client)
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusForbidden),
+			Message: parseError.Message,
+		}, status.Errorf(codes.PermissionDenied, parseError.Message)
+	}
+
+	if alumni == nil {
+		return nil, nil
+	}
+
+	log.Printf("WARNING: [AuthHandler - %s] Biodata API unavailable, alumni %d logged in from local record", method, alumni.Id)
+	return ah.alumniLoginResponse(ctx, method, alumni, account)
+}
+
+func (ah *AuthHandler) alumniLoginResponse(ctx context.Context, method string, alumni *entity.Alumni, account string) (*pb.LoginResponse, error) {
+	// generate token with sub = alumni:id, role = 6 (alumni)
+	token, refreshToken, err := ah.generateTokenPair(ctx, commonJwt.AlumniSubject(alumni.Id), 6)
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Printf("ERROR: [AuthHandler - %s] Error while generating token: %s", method, parseError.Message)
 		// return nil, status.Errorf(codes.Internal, "token failed to generate: %v", parseError.Message)
 		return &pb.LoginResponse{
 			Code:    uint32(http.StatusInternalServerError),
@@ -81,17 +188,36 @@ func (ah *AuthHandler) LoginAlumni(ctx context.Context, req *pb.LoginAlumniReque
 		}, status.Errorf(codes.Internal, "token failed to generate: %v", parseError.Message)
 	}
 
+	ah.recordLoginSuccess(ctx, method, account)
+
 	return &pb.LoginResponse{
-		Code:    uint32(http.StatusOK),
-		Message: "login success",
-		Token:   token,
+		Code:         uint32(http.StatusOK),
+		Message:      "login success",
+		Token:        token,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
 func (ah *AuthHandler) LoginUserStudy(ctx context.Context, req *pb.LoginUserStudyRequest) (*pb.LoginResponse, error) {
-	user, err := ah.pktsSvc.GetNimByDataAtasan(req.GetNamaAtasan(), req.GetEmailAtasan(), req.GetHpAtasan())
+	if !ah.config.UserStudy.AllowDataLogin {
+		log.Println("WARNING: [AuthHandler - LoginUserStudy] Login with employer data is disabled")
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusBadRequest),
+			Message: "login with employer data is disabled, request a login link instead",
+		}, status.Errorf(codes.FailedPrecondition, "login with employer data is disabled, request a login link instead")
+	}
+
+	account := throttle.AccountKey("userstudy", req.GetEmailAtasan())
+	client := throttle.ClientKey(utils.GetClientIP(ctx))
+	if throttled, err := ah.checkLoginThrottle(ctx, "LoginUserStudy", account, client); err != nil {
+		return throttled, err
+	}
+
+	user, err := ah.pktsSvc.GetNimByDataAtasan(ctx, req.GetNamaAtasan(), req.GetEmailAtasan(), req.GetHpAtasan())
 	if err != nil {
-		if user.GetNims() == nil || len(user.GetNims()) == 0 {
+		// an unreachable PKTS is not a failed login
+		if errors.ParseError(err).Code == codes.NotFound {
+			ah.recordLoginFailure(ctx, "LoginUserStudy", account, client)
 			log.Println("WARNING: [AuthHandler - LoginUserStudy] User resource not found")
 			// return nil, status.Errorf(codes.NotFound, "user resource not found")
 			return &pb.LoginResponse{
@@ -109,6 +235,7 @@ func (ah *AuthHandler) LoginUserStudy(ctx context.Context, req *pb.LoginUserStud
 	}
 
 	if user.GetNims() == nil || len(user.GetNims()) == 0 {
+		ah.recordLoginFailure(ctx, "LoginUserStudy", account, client)
 		log.Println("WARNING: [AuthHandler - LoginUserStudy] User resource not found")
 		// return nil, status.Errorf(codes.NotFound, "user resource not found")
 		return &pb.LoginResponse{
@@ -117,8 +244,8 @@ func (ah *AuthHandler) LoginUserStudy(ctx context.Context, req *pb.LoginUserStud
 		}, status.Errorf(codes.NotFound, "user resource not found")
 	}
 
-	// generate token with cred = email, role = 7 (pengguna alumni)
-	token, err := ah.jwtManager.GenerateToken(req.GetEmailAtasan(), 7)
+	// generate token with sub = userstudy:email, role = 7 (pengguna alumni)
+	token, refreshToken, err := ah.generateUserStudyTokenPair(ctx, req.GetEmailAtasan(), user.GetNims())
 
 	if err != nil {
 		parseError := errors.ParseError(err)
@@ -130,17 +257,27 @@ func (ah *AuthHandler) LoginUserStudy(ctx context.Context, req *pb.LoginUserStud
 		}, status.Errorf(codes.Internal, "token failed to generate: %v", parseError.Message)
 	}
 
+	ah.recordLoginSuccess(ctx, "LoginUserStudy", account)
+
 	return &pb.LoginResponse{
-		Code:    uint32(http.StatusOK),
-		Message: "login user study success",
-		Token:   token,
+		Code:         uint32(http.StatusOK),
+		Message:      "login user study success",
+		Token:        token,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
 func (ah *AuthHandler) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.LoginResponse, error) {
+	account := throttle.AccountKey("user", req.GetUsername())
+	client := throttle.ClientKey(utils.GetClientIP(ctx))
+	if throttled, err := ah.checkLoginThrottle(ctx, "LoginUser", account, client); err != nil {
+		return throttled, err
+	}
+
 	user, err := ah.userSvc.FindByUsername(ctx, req.GetUsername())
 	if err != nil {
 		if user == nil {
+			ah.recordLoginFailure(ctx, "LoginUser", account, client)
 			log.Println("WARNING: [AuthHandler - LoginUser] User not found")
 			// return nil, status.Errorf(codes.NotFound, "user not found")
 			return &pb.LoginResponse{
@@ -158,6 +295,7 @@ func (ah *AuthHandler) LoginUser(ctx context.Context, req *pb.LoginUserRequest)
 	}
 
 	if user == nil {
+		ah.recordLoginFailure(ctx, "LoginUser", account, client)
 		log.Println("WARNING: [AuthHandler - LoginUser] User resource not found")
 		// return nil, status.Errorf(codes.NotFound, "user resource not found")
 		return &pb.LoginResponse{
@@ -166,8 +304,18 @@ func (ah *AuthHandler) LoginUser(ctx context.Context, req *pb.LoginUserRequest)
 		}, status.Errorf(codes.NotFound, "user not found")
 	}
 
-	match := utils.CheckPasswordHash(req.GetPassword(), user.Password)
+	match, err := ah.userSvc.Authenticate(ctx, user, req.GetPassword())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - LoginUser] Error while verifying password:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
 	if !match {
+		ah.recordLoginFailure(ctx, "LoginUser", account, client)
 		log.Println("WARNING: [AuthHandler - LoginUser] Invalid credentials")
 		// return nil, status.Errorf(codes.Unauthenticated, "invalid credentials")
 		return &pb.LoginResponse{
@@ -176,8 +324,45 @@ func (ah *AuthHandler) LoginUser(ctx context.Context, req *pb.LoginUserRequest)
 		}, status.Errorf(codes.InvalidArgument, "invalid credentials")
 	}
 
-	// generate token with cred = username, role = roleId
-	token, err := ah.jwtManager.GenerateToken(user.Username, user.RoleId)
+	ah.recordLoginSuccess(ctx, "LoginUser", account)
+
+	if ah.config.Email.RequiredForLogin && user.EmailVerifiedAt == nil {
+		log.Println("WARNING: [AuthHandler - LoginUser] Email address has not been verified")
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusBadRequest),
+			Message: "email address has not been verified",
+		}, status.Errorf(codes.FailedPrecondition, "email address has not been verified")
+	}
+
+	// users with mfa enabled, or whose role requires it, get a challenge
+	// instead of tokens
+	mfaToken, purpose, err := ah.mfaSvc.StartChallenge(ctx, user)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - LoginUser] Error while starting mfa challenge:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "failed to start mfa challenge",
+		}, status.Errorf(codes.Internal, "failed to start mfa challenge")
+	}
+
+	if mfaToken != "" {
+		message := "mfa code required"
+		if purpose == authEntity.MfaChallengeEnroll {
+			message = "mfa enrollment required"
+		}
+
+		return &pb.LoginResponse{
+			Code:                  uint32(http.StatusOK),
+			Message:               message,
+			MfaRequired:           purpose == authEntity.MfaChallengeVerify,
+			MfaEnrollmentRequired: purpose == authEntity.MfaChallengeEnroll,
+			MfaToken:              mfaToken,
+		}, nil
+	}
+
+	// generate token with sub = user:id, role = roleId
+	token, refreshToken, err := ah.generateTokenPair(ctx, commonJwt.UserSubject(user.Id), user.RoleId)
 
 	if err != nil {
 		parseError := errors.ParseError(err)
@@ -190,13 +375,14 @@ func (ah *AuthHandler) LoginUser(ctx context.Context, req *pb.LoginUserRequest)
 	}
 
 	return &pb.LoginResponse{
-		Code:    uint32(http.StatusOK),
-		Message: "login user success",
-		Token:   token,
+		Code:         uint32(http.StatusOK),
+		Message:      "login user success",
+		Token:        token,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
-func (ah *AuthHandler) RegisterUser(ctx context.Context, req *pb.User) (*pb.SingleUserResponse, error) {
+func (ah *AuthHandler) RegisterUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.SingleUserResponse, error) {
 	user, err := ah.userSvc.FindByUsername(ctx, req.GetUsername())
 	if err != nil {
 		if user != nil {
@@ -233,9 +419,9 @@ func (ah *AuthHandler) RegisterUser(ctx context.Context, req *pb.User) (*pb.Sing
 		log.Println("ERROR: [AuthHandler - RegisterUser] Error while creating user:", parseError.Message)
 		// return nil, status.Errorf(parseError.Code, parseError.Message)
 		return &pb.SingleUserResponse{
-			Code:    uint32(http.StatusInternalServerError),
+			Code:    parseError.HTTPStatus(),
 			Message: parseError.Message,
-		}, status.Errorf(parseError.Code, parseError.Message)
+		}, errors.Status(err)
 	}
 
 	userProto := entity.ConvertEntityToProto(user)
@@ -247,11 +433,11 @@ func (ah *AuthHandler) RegisterUser(ctx context.Context, req *pb.User) (*pb.Sing
 	}, nil
 }
 
-func (ah *AuthHandler) GetCurrentUser(ctx context.Context, req *emptypb.Empty) (*pb.SingleUserResponse, error) {
+func (ah *AuthHandler) GetCurrentUser(ctx context.Context, req *emptypb.Empty) (*pb.CurrentPrincipalResponse, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		log.Println("ERROR: [AuthHandler - GetCurrentUser] No metadata found")
-		return &pb.SingleUserResponse{
+		return &pb.CurrentPrincipalResponse{
 			Code:    uint32(http.StatusBadRequest),
 			Message: "no metadata found",
 		}, status.Errorf(codes.InvalidArgument, "no metadata found")
@@ -260,7 +446,7 @@ func (ah *AuthHandler) GetCurrentUser(ctx context.Context, req *emptypb.Empty) (
 	values, ok := md["authorization"]
 	if !ok || len(values) == 0 {
 		log.Println("ERROR: [AuthHandler - GetCurrentUser] No authorization header found")
-		return &pb.SingleUserResponse{
+		return &pb.CurrentPrincipalResponse{
 			Code:    uint32(http.StatusBadRequest),
 			Message: "no authorization header found",
 		}, status.Errorf(codes.InvalidArgument, "no authorization header found")
@@ -270,50 +456,416 @@ func (ah *AuthHandler) GetCurrentUser(ctx context.Context, req *emptypb.Empty) (
 	parts := strings.Fields(authHeader)
 	if len(parts) != 2 || parts[0] != "Bearer" {
 		log.Println("ERROR: [AuthHandler - GetCurrentUser] Invalid authorization header")
-		return &pb.SingleUserResponse{
+		return &pb.CurrentPrincipalResponse{
 			Code:    uint32(http.StatusBadRequest),
 			Message: "invalid authorization header",
 		}, status.Errorf(codes.InvalidArgument, "invalid authorization header")
 	}
 
 	accessToken := parts[1]
-	claims, err := ah.jwtManager.Verify(accessToken)
+	claims, err := ah.jwtManager.Verify(ctx, accessToken)
 	if err != nil {
 		log.Println("ERROR: [AuthHandler - GetCurrentUser] Invalid token:", err)
-		return &pb.SingleUserResponse{
+		return &pb.CurrentPrincipalResponse{
 			Code:    uint32(http.StatusUnauthorized),
 			Message: "invalid token",
 		}, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
 
-	user, err := ah.userSvc.FindByUsername(ctx, claims.Cred)
+	subjectType, id, err := commonJwt.ParseSubject(claims.Subject)
+	if err != nil {
+		log.Println("ERROR: [AuthHandler - GetCurrentUser] Invalid subject:", claims.Subject)
+		return &pb.CurrentPrincipalResponse{
+			Code:    uint32(http.StatusUnauthorized),
+			Message: "invalid token",
+		}, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+
+	principal := &pb.CurrentPrincipal{
+		Sub:  claims.Subject,
+		Type: subjectType,
+		Role: claims.Role,
+	}
+
+	switch subjectType {
+	case commonJwt.SubjectTypeUser:
+		principal.User, err = ah.currentUserProfile(ctx, claims.Subject)
+	case commonJwt.SubjectTypeAlumni:
+		principal.Alumni, err = ah.currentAlumniProfile(ctx, claims.Subject)
+	case commonJwt.SubjectTypeUserStudy:
+		principal.Employer, err = ah.currentEmployerProfile(ctx, id, claims.Scopes)
+	default:
+		err = status.Errorf(codes.NotFound, "unknown subject type %s", subjectType)
+	}
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - GetCurrentUser] Error while fetching current principal:", parseError.Message)
+		return &pb.CurrentPrincipalResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.CurrentPrincipalResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "get current user success",
+		Data:    principal,
+	}, nil
+}
+
+func (ah *AuthHandler) currentUserProfile(ctx context.Context, subject string) (*pb.UserView, error) {
+	userId, err := commonJwt.ParseUserSubject(subject)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+
+	user, err := ah.userSvc.FindById(ctx, userId)
 	if err != nil {
 		if user == nil {
-			log.Println("WARNING: [AuthHandler - GetCurrentUser] User not found")
-			return &pb.SingleUserResponse{
-				Code:    uint32(http.StatusNotFound),
-				Message: "user not found",
-			}, status.Errorf(codes.NotFound, "user not found")
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, err
+	}
+
+	return entity.ConvertEntityToProto(user), nil
+}
+
+func (ah *AuthHandler) currentAlumniProfile(ctx context.Context, subject string) (*pb.AlumniProfile, error) {
+	alumniId, err := commonJwt.ParseAlumniSubject(subject)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+
+	alumni, err := ah.alumniSvc.FindById(ctx, alumniId)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ah.mhsApiSvc.FetchMhsBiodataByNim(ctx, alumni.Nim)
+	if err != nil {
+		return nil, err
+	}
+
+	mhs := res.GetData()
+	if mhs == nil {
+		return nil, status.Errorf(codes.NotFound, "alumni not found")
+	}
+
+	return &pb.AlumniProfile{
+		Nim:           mhs.GetNIM(),
+		Nama:          mhs.GetNAMA(),
+		Email:         mhs.GetEMAIL(),
+		Hp:            mhs.GetHP(),
+		Kodeprodi:     mhs.GetKODEPST(),
+		Prodi:         mhs.GetNAMAPST(),
+		Jenjang:       mhs.GetJENJANG(),
+		Kodefak:       mhs.GetKODEFAK(),
+		Fakultas:      mhs.GetNAMAFAK(),
+		TahunMasuk:    mhs.GetTHNMASUK(),
+		TanggalSidang: mhs.GetTGLSIDANG(),
+		Ipk:           mhs.GetIPK(),
+	}, nil
+}
+
+func (ah *AuthHandler) currentEmployerProfile(ctx context.Context, email string, scopes commonJwt.Scopes) (*pb.EmployerProfile, error) {
+	nims, err := ah.userStudyLoginSvc.AuthorizedNims(ctx, email, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.EmployerProfile{
+		Email: email,
+		Nims:  nims,
+	}, nil
+}
+
+func (ah *AuthHandler) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	claims, err := ah.currentClaims(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ChangePassword] Invalid token:", parseError.Message)
+		return &pb.ChangePasswordResponse{
+			Code:    uint32(http.StatusUnauthorized),
+			Message: "invalid token",
+		}, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+
+	userId, err := commonJwt.ParseUserSubject(claims.Subject)
+	if err != nil {
+		log.Println("WARNING: [AuthHandler - ChangePassword] Subject has no password:", claims.Subject)
+		return &pb.ChangePasswordResponse{
+			Code:    uint32(http.StatusForbidden),
+			Message: "password can only be changed by users",
+		}, status.Errorf(codes.PermissionDenied, "password can only be changed by users")
+	}
+
+	user, err := ah.userSvc.FindById(ctx, userId)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ChangePassword] Error while fetching user:", parseError.Message)
+		return &pb.ChangePasswordResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	// guessing the current password counts against the same limits as
+	// logging in, so a stolen token cannot be used to find it
+	account := throttle.AccountKey("user", user.Username)
+	client := throttle.ClientKey(utils.GetClientIP(ctx))
+	if throttled, err := ah.checkLoginThrottle(ctx, "ChangePassword", account, client); err != nil {
+		return &pb.ChangePasswordResponse{
+			Code:    throttled.GetCode(),
+			Message: throttled.GetMessage(),
+		}, err
+	}
+
+	if err := ah.userSvc.ChangePassword(ctx, userId, req.GetCurrentPassword(), req.GetNewPassword()); err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.Unauthenticated {
+			ah.recordLoginFailure(ctx, "ChangePassword", account, client)
 		}
+		log.Println("ERROR: [AuthHandler - ChangePassword] Error while changing password:", parseError.Message)
+		return &pb.ChangePasswordResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, errors.Status(err)
+	}
+
+	ah.recordLoginSuccess(ctx, "ChangePassword", account)
+
+	// the caller's own tokens were revoked along with every other session
+	token, refreshToken, err := ah.generateTokenPair(ctx, claims.Subject, claims.Role)
+	if err != nil {
 		parseError := errors.ParseError(err)
-		log.Println("ERROR: [AuthHandler - GetCurrentUser] Error while fetching user:", parseError.Message)
-		return &pb.SingleUserResponse{
+		log.Println("ERROR: [AuthHandler - ChangePassword] Error while generating token:", parseError.Message)
+		return &pb.ChangePasswordResponse{
 			Code:    uint32(http.StatusInternalServerError),
+			Message: "password changed, but token failed to generate: " + parseError.Message,
+		}, status.Errorf(codes.Internal, "password changed, but token failed to generate: %v", parseError.Message)
+	}
+
+	return &pb.ChangePasswordResponse{
+		Code:         uint32(http.StatusOK),
+		Message:      "change password success",
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (ah *AuthHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.LoginResponse, error) {
+	current, err := ah.refreshTokenSvc.Check(ctx, req.GetRefreshToken())
+	if err != nil {
+		return ah.refreshTokenFailure(err)
+	}
+
+	// the access token is built before the refresh token is consumed, so a
+	// failed lookup of the subject's scopes can be retried with the same token
+	token, err := ah.generateToken(ctx, current.Subject, current.Role)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			log.Println("WARNING: [AuthHandler - RefreshToken] User resource not found:", parseError.Message)
+			return &pb.LoginResponse{
+				Code:    uint32(http.StatusNotFound),
+				Message: parseError.Message,
+			}, status.Errorf(codes.NotFound, parseError.Message)
+		}
+		log.Println("ERROR: [AuthHandler - RefreshToken] Error while generating token:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "token failed to generate: " + parseError.Message,
+		}, status.Errorf(codes.Internal, "token failed to generate: %v", parseError.Message)
+	}
+
+	refreshToken, err := ah.refreshTokenSvc.Rotate(ctx, current)
+	if err != nil {
+		return ah.refreshTokenFailure(err)
+	}
+
+	return &pb.LoginResponse{
+		Code:         uint32(http.StatusOK),
+		Message:      "refresh token success",
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (ah *AuthHandler) refreshTokenFailure(err error) (*pb.LoginResponse, error) {
+	parseError := errors.ParseError(err)
+	if parseError.Code == codes.Unauthenticated {
+		log.Println("WARNING: [AuthHandler - RefreshToken] Refresh token rejected:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusUnauthorized),
 			Message: parseError.Message,
-		}, status.Errorf(parseError.Code, parseError.Message)
+		}, status.Errorf(codes.Unauthenticated, parseError.Message)
+	}
+	log.Println("ERROR: [AuthHandler - RefreshToken] Error while rotating refresh token:", parseError.Message)
+	return &pb.LoginResponse{
+		Code:    uint32(http.StatusInternalServerError),
+		Message: parseError.Message,
+	}, status.Errorf(parseError.Code, parseError.Message)
+}
+
+func (ah *AuthHandler) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	accessToken, err := utils.GetBearerToken(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - Logout] Error while getting access token:", parseError.Message)
+		return &pb.LogoutResponse{
+			Code:    uint32(http.StatusUnauthorized),
+			Message: parseError.Message,
+		}, status.Errorf(codes.Unauthenticated, parseError.Message)
 	}
 
-	userProto := &pb.User{
-		Id:       user.Id,
-		Name:     user.Name,
-		Username: user.Username,
-		Email:    user.Email,
-		RoleId:   user.RoleId,
+	claims, err := ah.jwtManager.Verify(ctx, accessToken)
+	if err != nil {
+		log.Println("ERROR: [AuthHandler - Logout] Invalid token:", err)
+		return &pb.LogoutResponse{
+			Code:    uint32(http.StatusUnauthorized),
+			Message: "invalid token",
+		}, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
 
-	return &pb.SingleUserResponse{
+	if req.GetRefreshToken() != "" {
+		if err := ah.refreshTokenSvc.Revoke(ctx, req.GetRefreshToken(), claims.Subject); err != nil {
+			parseError := errors.ParseError(err)
+			log.Println("ERROR: [AuthHandler - Logout] Error while revoking refresh token:", parseError.Message)
+			return &pb.LogoutResponse{
+				Code:    uint32(http.StatusBadRequest),
+				Message: parseError.Message,
+			}, status.Errorf(parseError.Code, parseError.Message)
+		}
+	}
+
+	if err := ah.jwtManager.RevokeToken(ctx, claims); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - Logout] Error while revoking token:", parseError.Message)
+		return &pb.LogoutResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "failed to revoke token",
+		}, status.Errorf(codes.Internal, "failed to revoke token")
+	}
+
+	return &pb.LogoutResponse{
 		Code:    uint32(http.StatusOK),
-		Message: "get current user success",
-		Data:    userProto,
+		Message: "logout success",
+	}, nil
+}
+
+func (ah *AuthHandler) GetJwks(ctx context.Context, req *emptypb.Empty) (*pb.GetJwksResponse, error) {
+	jwks := ah.jwtManager.Jwks()
+
+	var keys []*pb.Jwk
+	for _, k := range jwks.Keys {
+		keys = append(keys, &pb.Jwk{
+			Kty: k.Kty,
+			Kid: k.Kid,
+			Use: k.Use,
+			Alg: k.Alg,
+			N:   k.N,
+			E:   k.E,
+			Crv: k.Crv,
+			X:   k.X,
+			Y:   k.Y,
+		})
+	}
+
+	return &pb.GetJwksResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "get jwks success",
+		Keys:    keys,
 	}, nil
 }
+
+// generateTokenPair issues a short-lived access token together with a
+// refresh token that starts a new rotation family.
+func (ah *AuthHandler) generateTokenPair(ctx context.Context, subject string, role uint32) (string, string, error) {
+	token, err := ah.generateToken(ctx, subject, role)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, err := ah.refreshTokenSvc.Issue(ctx, subject, role)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, refreshToken, nil
+}
+
+// generateToken issues an access token, reading the current prodi and
+// faculty scopes of user subjects, and the current alumni of employer
+// subjects, so refreshed tokens pick up changes. Alumni tokens carry their
+// own NIM. An employer without alumni gets NotFound, as on login.
+func (ah *AuthHandler) generateToken(ctx context.Context, subject string, role uint32) (string, error) {
+	var scopes commonJwt.Scopes
+	if userId, err := commonJwt.ParseUserSubject(subject); err == nil {
+		scopes, err = ah.userSvc.ClaimScopes(ctx, userId)
+		if err != nil {
+			return "", err
+		}
+	} else if alumniId, err := commonJwt.ParseAlumniSubject(subject); err == nil {
+		alumni, err := ah.alumniSvc.FindById(ctx, alumniId)
+		if err != nil {
+			return "", err
+		}
+
+		scopes.Nims = []string{alumni.Nim}
+	} else if email, err := commonJwt.ParseUserStudySubject(subject); err == nil {
+		nims, err := ah.userStudyLoginSvc.FetchNims(ctx, email)
+		if err != nil {
+			return "", err
+		}
+
+		if len(nims) == 0 {
+			return "", status.Errorf(codes.NotFound, "user resource not found")
+		}
+
+		scopes, err = ah.userStudyLoginSvc.ClaimScopes(ctx, email, nims)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return ah.jwtManager.GenerateToken(subject, role, scopes)
+}
+
+// generateUserStudyTokenPair issues employer tokens for alumni that were
+// just looked up, sparing generateToken another PKTS call.
+func (ah *AuthHandler) generateUserStudyTokenPair(ctx context.Context, email string, nims []string) (string, string, error) {
+	subject := commonJwt.UserStudySubject(email)
+
+	scopes, err := ah.userStudyLoginSvc.ClaimScopes(ctx, email, nims)
+	if err != nil {
+		return "", "", err
+	}
+
+	token, err := ah.jwtManager.GenerateToken(subject, 7, scopes)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, err := ah.refreshTokenSvc.Issue(ctx, subject, 7)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, refreshToken, nil
+}
+
+// currentClaims verifies the bearer token the request was made with.
+func (ah *AuthHandler) currentClaims(ctx context.Context) (*commonJwt.CustomClaims, error) {
+	accessToken, err := utils.GetBearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := ah.jwtManager.Verify(ctx, accessToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+
+	return claims, nil
+}
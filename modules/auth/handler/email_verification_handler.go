@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (ah *AuthHandler) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.EmailVerificationResponse, error) {
+	if err := ah.emailVerifySvc.Verify(ctx, req.GetToken()); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - VerifyEmail] Error while verifying email:", parseError.Message)
+		return &pb.EmailVerificationResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, errors.Status(err)
+	}
+
+	return &pb.EmailVerificationResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "verify email success",
+	}, nil
+}
+
+func (ah *AuthHandler) ResendVerification(ctx context.Context, req *pb.ResendVerificationRequest) (*pb.EmailVerificationResponse, error) {
+	email := strings.TrimSpace(req.GetEmail())
+	if email == "" {
+		return &pb.EmailVerificationResponse{
+			Code:    uint32(http.StatusBadRequest),
+			Message: "email is required",
+		}, status.Errorf(codes.InvalidArgument, "email is required")
+	}
+
+	if err := ah.emailVerifySvc.Resend(ctx, email); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ResendVerification] Error while resending verification:", parseError.Message)
+		return &pb.EmailVerificationResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "failed to resend verification",
+		}, status.Errorf(codes.Internal, "failed to resend verification")
+	}
+
+	return &pb.EmailVerificationResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "if the email is registered and unverified, a verification link has been sent to it",
+	}, nil
+}
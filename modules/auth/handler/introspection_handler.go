@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"tracerstudy-auth-service/common/errors"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	tokenTypeAccessToken = "access_token"
+)
+
+// IntrospectToken reports the state of an access token to another
+// tracer-study service, following RFC 7662. Tokens that fail signature or
+// claim validation, or have been revoked, are reported as inactive without
+// further detail.
+func (ah *AuthHandler) IntrospectToken(ctx context.Context, req *pb.IntrospectTokenRequest) (*pb.IntrospectTokenResponse, error) {
+	clientId, err := ah.serviceClients.Authenticate(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - IntrospectToken] Service client authentication failed:", parseError.Message)
+		return &pb.IntrospectTokenResponse{
+			Code:    uint32(http.StatusUnauthorized),
+			Message: parseError.Message,
+		}, status.Errorf(codes.Unauthenticated, parseError.Message)
+	}
+
+	claims, err := ah.jwtManager.Parse(req.GetToken())
+	if err != nil {
+		log.Println("INFO: [AuthHandler - IntrospectToken] Inactive token introspected by", clientId)
+		return &pb.IntrospectTokenResponse{
+			Code:    uint32(http.StatusOK),
+			Message: "token is inactive",
+			Active:  false,
+		}, nil
+	}
+
+	revoked, err := ah.jwtManager.IsRevoked(ctx, claims)
+	if err != nil {
+		log.Println("ERROR: [AuthHandler - IntrospectToken] Error while checking token revocation:", err)
+		return &pb.IntrospectTokenResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "failed to check token revocation",
+		}, status.Errorf(codes.Internal, "failed to check token revocation")
+	}
+
+	if revoked {
+		log.Println("INFO: [AuthHandler - IntrospectToken] Revoked token introspected by", clientId)
+		return &pb.IntrospectTokenResponse{
+			Code:    uint32(http.StatusOK),
+			Message: "token has been revoked",
+			Active:  false,
+		}, nil
+	}
+
+	nims := claims.Nims
+	if email, err := commonJwt.ParseUserStudySubject(claims.Subject); err == nil {
+		nims, err = ah.userStudyLoginSvc.AuthorizedNims(ctx, email, claims.Scopes)
+		if err != nil {
+			parseError := errors.ParseError(err)
+			log.Println("ERROR: [AuthHandler - IntrospectToken] Error while fetching authorized alumni:", parseError.Message)
+			return &pb.IntrospectTokenResponse{
+				Code:    parseError.HTTPStatus(),
+				Message: "failed to fetch authorized alumni",
+			}, status.Errorf(parseError.Code, "failed to fetch authorized alumni")
+		}
+	}
+
+	return &pb.IntrospectTokenResponse{
+		Code:      uint32(http.StatusOK),
+		Message:   "token is active",
+		Active:    true,
+		Sub:       claims.Subject,
+		Role:      claims.Role,
+		Exp:       claims.ExpiresAt,
+		Iat:       claims.IssuedAt,
+		Nbf:       claims.NotBefore,
+		Iss:       claims.Issuer,
+		Aud:       claims.Audience,
+		Jti:       claims.Id,
+		TokenType: tokenTypeAccessToken,
+		Kodeprodi: claims.Kodeprodi,
+		Kodefak:   claims.Kodefak,
+		Nims:      nims,
+	}, nil
+}
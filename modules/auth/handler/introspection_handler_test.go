@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/authorization"
+	"tracerstudy-auth-service/common/config"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRevocationStore struct {
+	commonJwt.RevocationStore
+	tokens map[string]bool
+}
+
+func (s *fakeRevocationStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.tokens[jti], nil
+}
+
+func (s *fakeRevocationStore) SubjectRevokedAt(ctx context.Context, subject string) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func basicAuthContext(id, secret string) context.Context {
+	credentials := base64.StdEncoding.EncodeToString([]byte(id + ":" + secret))
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic "+credentials))
+}
+
+func TestAuthHandlerIntrospectToken(t *testing.T) {
+	store := &fakeRevocationStore{tokens: map[string]bool{}}
+	jwtManager := commonJwt.NewJWT(config.JWTConfig{
+		Issuer:        "issuer",
+		Audience:      "audience",
+		TokenDuration: time.Minute,
+	}, commonJwt.NewHMACSigningKey("", []byte("secret")), store)
+
+	token, err := jwtManager.GenerateToken("user:1", 1, commonJwt.Scopes{Kodeprodi: []string{"0401"}})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	revokedToken, err := jwtManager.GenerateToken("user:2", 1, commonJwt.Scopes{})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	claims, err := jwtManager.Parse(revokedToken)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	store.tokens[claims.Id] = true
+
+	otherKey := commonJwt.NewJWT(config.JWTConfig{
+		Issuer:        "issuer",
+		Audience:      "audience",
+		TokenDuration: time.Minute,
+	}, commonJwt.NewHMACSigningKey("", []byte("other-secret")), nil)
+	forgedToken, err := otherKey.GenerateToken("user:1", 1, commonJwt.Scopes{})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		token      string
+		wantCode   codes.Code
+		wantActive bool
+		wantSub    string
+	}{
+		{
+			name:       "active token",
+			ctx:        basicAuthContext("tracer", "s3cret"),
+			token:      token,
+			wantActive: true,
+			wantSub:    "user:1",
+		},
+		{
+			name:  "revoked token",
+			ctx:   basicAuthContext("tracer", "s3cret"),
+			token: revokedToken,
+		},
+		{
+			name:  "token signed with another key",
+			ctx:   basicAuthContext("tracer", "s3cret"),
+			token: forgedToken,
+		},
+		{
+			name:  "malformed token",
+			ctx:   basicAuthContext("tracer", "s3cret"),
+			token: "not-a-token",
+		},
+		{
+			name:     "wrong client secret",
+			ctx:      basicAuthContext("tracer", "guess"),
+			token:    token,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "no client credentials",
+			ctx:      context.Background(),
+			token:    token,
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ah := &AuthHandler{
+				jwtManager:     jwtManager,
+				serviceClients: authorization.NewServiceClients("tracer:s3cret"),
+			}
+
+			res, err := ah.IntrospectToken(tt.ctx, &pb.IntrospectTokenRequest{Token: tt.token})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("IntrospectToken() error = %v, want %v", err, tt.wantCode)
+			}
+			if res.GetActive() != tt.wantActive {
+				t.Errorf("IntrospectToken() active = %v, want %v", res.GetActive(), tt.wantActive)
+			}
+			if res.GetSub() != tt.wantSub {
+				t.Errorf("IntrospectToken() sub = %q, want %q", res.GetSub(), tt.wantSub)
+			}
+			if tt.wantActive && len(res.GetKodeprodi()) != 1 {
+				t.Errorf("IntrospectToken() kodeprodi = %v, want the token's scopes", res.GetKodeprodi())
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+)
+
+const (
+	JwksPath = "/.well-known/jwks.json"
+)
+
+// NewJwksRestHandler serves the JWKS document in its standard JSON shape,
+// so it is written directly instead of through the gateway marshaler.
+func NewJwksRestHandler(jwtManager *commonJwt.JWT) func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+
+		if err := json.NewEncoder(w).Encode(jwtManager.Jwks()); err != nil {
+			log.Println("ERROR: [JwksRestHandler] Error while encoding jwks:", err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/common/throttle"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (ah *AuthHandler) ListLoginLockouts(ctx context.Context, req *emptypb.Empty) (*pb.ListLoginLockoutsResponse, error) {
+	lockouts, err := ah.throttler.Lockouts(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ListLoginLockouts] Error while get login lockouts:", parseError.Message)
+		return &pb.ListLoginLockoutsResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "failed to get login lockouts",
+		}, status.Errorf(codes.Internal, "failed to get login lockouts")
+	}
+
+	var lockoutArr []*pb.LoginLockout
+	for _, l := range lockouts {
+		lockoutArr = append(lockoutArr, &pb.LoginLockout{
+			Key:          l.Key,
+			Failures:     l.Failures,
+			BlockedUntil: l.BlockedUntil.Format(time.RFC3339),
+			LockedOut:    l.LockedOut,
+		})
+	}
+
+	return &pb.ListLoginLockoutsResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "get login lockouts success",
+		Data:    lockoutArr,
+	}, nil
+}
+
+func (ah *AuthHandler) ClearLoginLockout(ctx context.Context, req *pb.ClearLoginLockoutRequest) (*pb.ClearLoginLockoutResponse, error) {
+	key := strings.TrimSpace(req.GetKey())
+	if key == "" {
+		return &pb.ClearLoginLockoutResponse{
+			Code:    uint32(http.StatusBadRequest),
+			Message: "key is required",
+		}, status.Errorf(codes.InvalidArgument, "key is required")
+	}
+
+	if err := ah.throttler.Clear(ctx, key); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ClearLoginLockout] Error while clearing login lockout:", parseError.Message)
+		return &pb.ClearLoginLockoutResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "failed to clear login lockout",
+		}, status.Errorf(codes.Internal, "failed to clear login lockout")
+	}
+
+	log.Println("INFO: [AuthHandler - ClearLoginLockout] Login lockout cleared:", key)
+
+	return &pb.ClearLoginLockoutResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "clear login lockout success",
+	}, nil
+}
+
+// checkLoginThrottle returns the response to send when any of keys may not
+// attempt to log in yet.
+func (ah *AuthHandler) checkLoginThrottle(ctx context.Context, method string, keys ...string) (*pb.LoginResponse, error) {
+	err := ah.throttler.Check(ctx, keys...)
+	if err == nil {
+		return nil, nil
+	}
+
+	parseError := errors.ParseError(err)
+	if parseError.Code != codes.ResourceExhausted {
+		log.Printf("ERROR: [AuthHandler - %s] Error while checking login throttle: %s", method, parseError.Message)
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "failed to check login attempts",
+		}, status.Errorf(codes.Internal, "failed to check login attempts")
+	}
+
+	log.Printf("WARNING: [AuthHandler - %s] Login throttled: %s", method, parseError.Message)
+	throttle.SetRetryAfterHeader(ctx, err)
+
+	return &pb.LoginResponse{
+		Code:    parseError.HTTPStatus(),
+		Message: parseError.Message,
+	}, errors.Status(err)
+}
+
+// recordLoginFailure counts a failed login against keys. The failure is
+// reported to the caller regardless, so errors are only logged.
+func (ah *AuthHandler) recordLoginFailure(ctx context.Context, method string, keys ...string) {
+	if err := ah.throttler.Fail(ctx, keys...); err != nil {
+		log.Printf("ERROR: [AuthHandler - %s] Error while recording failed login: %v", method, err)
+	}
+}
+
+func (ah *AuthHandler) recordLoginSuccess(ctx context.Context, method, key string) {
+	if err := ah.throttler.Succeed(ctx, key); err != nil {
+		log.Printf("ERROR: [AuthHandler - %s] Error while clearing failed logins: %v", method, err)
+	}
+}
@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"tracerstudy-auth-service/common/errors"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/throttle"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/auth/entity"
+	userEntity "tracerstudy-auth-service/modules/user/entity"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// VerifyMfa completes a login whose password was accepted, given a code
+// from the user's authenticator or one of their recovery codes.
+func (ah *AuthHandler) VerifyMfa(ctx context.Context, req *pb.VerifyMfaRequest) (*pb.LoginResponse, error) {
+	challenge, err := ah.mfaSvc.FindChallenge(ctx, req.GetMfaToken(), entity.MfaChallengeVerify)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("WARNING: [AuthHandler - VerifyMfa] Invalid mfa challenge:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	account := mfaThrottleKey(challenge.UserId)
+	client := throttle.ClientKey(utils.GetClientIP(ctx))
+	if throttled, err := ah.checkLoginThrottle(ctx, "VerifyMfa", account, client); err != nil {
+		return throttled, err
+	}
+
+	if err := ah.mfaSvc.Verify(ctx, challenge.UserId, req.GetTotpCode(), req.GetRecoveryCode()); err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.Unauthenticated {
+			ah.mfaSvc.FailChallenge(ctx, challenge)
+			ah.recordLoginFailure(ctx, "VerifyMfa", account, client)
+		}
+		log.Println("WARNING: [AuthHandler - VerifyMfa] Error while verifying mfa code:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	if err := ah.mfaSvc.CloseChallenge(ctx, challenge); err != nil {
+		parseError := errors.ParseError(err)
+		return &pb.LoginResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	ah.recordLoginSuccess(ctx, "VerifyMfa", account)
+
+	user, err := ah.userSvc.FindById(ctx, challenge.UserId)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - VerifyMfa] Error while fetching user:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	token, refreshToken, err := ah.generateTokenPair(ctx, commonJwt.UserSubject(user.Id), user.RoleId)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - VerifyMfa] Error while generating token:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "token failed to generate: " + parseError.Message,
+		}, status.Errorf(codes.Internal, "token failed to generate: %v", parseError.Message)
+	}
+
+	return &pb.LoginResponse{
+		Code:         uint32(http.StatusOK),
+		Message:      "login user success",
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// BeginMfaEnrollment starts enrolling the caller, identified by their access
+// token or, when their role requires MFA, by the token LoginUser returned.
+func (ah *AuthHandler) BeginMfaEnrollment(ctx context.Context, req *pb.BeginMfaEnrollmentRequest) (*pb.BeginMfaEnrollmentResponse, error) {
+	user, _, err := ah.mfaEnrollee(ctx, req.GetMfaToken())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("WARNING: [AuthHandler - BeginMfaEnrollment] Error while identifying user:", parseError.Message)
+		return &pb.BeginMfaEnrollmentResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	secret, uri, err := ah.mfaSvc.BeginEnrollment(ctx, user)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - BeginMfaEnrollment] Error while beginning mfa enrollment:", parseError.Message)
+		return &pb.BeginMfaEnrollmentResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.BeginMfaEnrollmentResponse{
+		Code:       uint32(http.StatusOK),
+		Message:    "begin mfa enrollment success",
+		Secret:     secret,
+		OtpauthUri: uri,
+	}, nil
+}
+
+// ConfirmMfaEnrollment enables MFA and returns the recovery codes. Callers
+// enrolling during login also receive their tokens.
+func (ah *AuthHandler) ConfirmMfaEnrollment(ctx context.Context, req *pb.ConfirmMfaEnrollmentRequest) (*pb.ConfirmMfaEnrollmentResponse, error) {
+	user, challenge, err := ah.mfaEnrollee(ctx, req.GetMfaToken())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("WARNING: [AuthHandler - ConfirmMfaEnrollment] Error while identifying user:", parseError.Message)
+		return &pb.ConfirmMfaEnrollmentResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	account := mfaThrottleKey(user.Id)
+	client := throttle.ClientKey(utils.GetClientIP(ctx))
+	if throttled, err := ah.checkLoginThrottle(ctx, "ConfirmMfaEnrollment", account, client); err != nil {
+		return &pb.ConfirmMfaEnrollmentResponse{
+			Code:    throttled.GetCode(),
+			Message: throttled.GetMessage(),
+		}, err
+	}
+
+	recoveryCodes, err := ah.mfaSvc.ConfirmEnrollment(ctx, user.Id, req.GetTotpCode())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.Unauthenticated {
+			if challenge != nil {
+				ah.mfaSvc.FailChallenge(ctx, challenge)
+			}
+			ah.recordLoginFailure(ctx, "ConfirmMfaEnrollment", account, client)
+		}
+		log.Println("WARNING: [AuthHandler - ConfirmMfaEnrollment] Error while confirming mfa enrollment:", parseError.Message)
+		return &pb.ConfirmMfaEnrollmentResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	res := &pb.ConfirmMfaEnrollmentResponse{
+		Code:          uint32(http.StatusOK),
+		Message:       "confirm mfa enrollment success",
+		RecoveryCodes: recoveryCodes,
+	}
+
+	if challenge == nil {
+		return res, nil
+	}
+
+	if err := ah.mfaSvc.CloseChallenge(ctx, challenge); err != nil {
+		// mfa is enabled regardless; the user logs in again to get tokens
+		return res, nil
+	}
+
+	token, refreshToken, err := ah.generateTokenPair(ctx, commonJwt.UserSubject(user.Id), user.RoleId)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ConfirmMfaEnrollment] Error while generating token:", parseError.Message)
+		return res, nil
+	}
+
+	res.Token = token
+	res.RefreshToken = refreshToken
+
+	return res, nil
+}
+
+func (ah *AuthHandler) DisableMfa(ctx context.Context, req *pb.DisableMfaRequest) (*pb.DisableMfaResponse, error) {
+	user, err := ah.currentUser(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("WARNING: [AuthHandler - DisableMfa] Error while identifying user:", parseError.Message)
+		return &pb.DisableMfaResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	account := mfaThrottleKey(user.Id)
+	client := throttle.ClientKey(utils.GetClientIP(ctx))
+	if throttled, err := ah.checkLoginThrottle(ctx, "DisableMfa", account, client); err != nil {
+		return &pb.DisableMfaResponse{
+			Code:    throttled.GetCode(),
+			Message: throttled.GetMessage(),
+		}, err
+	}
+
+	match, err := ah.userSvc.Authenticate(ctx, user, req.GetPassword())
+	if err == nil && !match {
+		err = status.Errorf(codes.Unauthenticated, "password is incorrect")
+	}
+
+	if err == nil {
+		err = ah.mfaSvc.Disable(ctx, user, req.GetTotpCode())
+	}
+
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.Unauthenticated {
+			ah.recordLoginFailure(ctx, "DisableMfa", account, client)
+		}
+		log.Println("WARNING: [AuthHandler - DisableMfa] Error while disabling mfa:", parseError.Message)
+		return &pb.DisableMfaResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.DisableMfaResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "disable mfa success",
+	}, nil
+}
+
+func (ah *AuthHandler) RegenerateRecoveryCodes(ctx context.Context, req *pb.RegenerateRecoveryCodesRequest) (*pb.RecoveryCodesResponse, error) {
+	user, err := ah.currentUser(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("WARNING: [AuthHandler - RegenerateRecoveryCodes] Error while identifying user:", parseError.Message)
+		return &pb.RecoveryCodesResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	account := mfaThrottleKey(user.Id)
+	client := throttle.ClientKey(utils.GetClientIP(ctx))
+	if throttled, err := ah.checkLoginThrottle(ctx, "RegenerateRecoveryCodes", account, client); err != nil {
+		return &pb.RecoveryCodesResponse{
+			Code:    throttled.GetCode(),
+			Message: throttled.GetMessage(),
+		}, err
+	}
+
+	recoveryCodes, err := ah.mfaSvc.RegenerateRecoveryCodes(ctx, user.Id, req.GetTotpCode())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.Unauthenticated {
+			ah.recordLoginFailure(ctx, "RegenerateRecoveryCodes", account, client)
+		}
+		log.Println("WARNING: [AuthHandler - RegenerateRecoveryCodes] Error while regenerating recovery codes:", parseError.Message)
+		return &pb.RecoveryCodesResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.RecoveryCodesResponse{
+		Code:          uint32(http.StatusOK),
+		Message:       "regenerate recovery codes success",
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// mfaEnrollee identifies the user enrolling: from an enroll challenge when
+// mfaToken is given, otherwise from the access token.
+func (ah *AuthHandler) mfaEnrollee(ctx context.Context, mfaToken string) (*userEntity.User, *entity.MfaChallenge, error) {
+	if mfaToken == "" {
+		user, err := ah.currentUser(ctx)
+		return user, nil, err
+	}
+
+	challenge, err := ah.mfaSvc.FindChallenge(ctx, mfaToken, entity.MfaChallengeEnroll)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := ah.userSvc.FindById(ctx, challenge.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, challenge, nil
+}
+
+// currentUser returns the user the access token was issued to.
+func (ah *AuthHandler) currentUser(ctx context.Context) (*userEntity.User, error) {
+	claims, err := ah.currentClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userId, err := commonJwt.ParseUserSubject(claims.Subject)
+	if err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "mfa is only available to users")
+	}
+
+	return ah.userSvc.FindById(ctx, userId)
+}
+
+func mfaThrottleKey(userId uint64) string {
+	return throttle.AccountKey("mfa", strconv.FormatUint(userId, 10))
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (ah *AuthHandler) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.PasswordResetResponse, error) {
+	email := strings.TrimSpace(req.GetEmail())
+	if email == "" {
+		return &pb.PasswordResetResponse{
+			Code:    uint32(http.StatusBadRequest),
+			Message: "email is required",
+		}, status.Errorf(codes.InvalidArgument, "email is required")
+	}
+
+	if err := ah.passwordResetSvc.Request(ctx, email); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - RequestPasswordReset] Error while requesting password reset:", parseError.Message)
+		return &pb.PasswordResetResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "failed to request password reset",
+		}, status.Errorf(codes.Internal, "failed to request password reset")
+	}
+
+	return &pb.PasswordResetResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "if the email is registered, a password reset link has been sent to it",
+	}, nil
+}
+
+func (ah *AuthHandler) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.PasswordResetResponse, error) {
+	if err := ah.passwordResetSvc.Reset(ctx, req.GetToken(), req.GetNewPassword()); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ResetPassword] Error while resetting password:", parseError.Message)
+		return &pb.PasswordResetResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, errors.Status(err)
+	}
+
+	return &pb.PasswordResetResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "reset password success",
+	}, nil
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/modules/auth/entity"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (ah *AuthHandler) ListSigningKeys(ctx context.Context, req *emptypb.Empty) (*pb.ListSigningKeysResponse, error) {
+	keys, err := ah.signingKeySvc.FindAll(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ListSigningKeys] Error while get all signing keys:", parseError.Message)
+		return &pb.ListSigningKeysResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	var keyArr []*pb.SigningKey
+	for _, k := range keys {
+		keyArr = append(keyArr, entity.ConvertSigningKeyEntityToProto(k, ah.signingKeySvc.Status(k)))
+	}
+
+	return &pb.ListSigningKeysResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "get all signing keys success",
+		Data:    keyArr,
+	}, nil
+}
+
+func (ah *AuthHandler) CreateSigningKey(ctx context.Context, req *pb.CreateSigningKeyRequest) (*pb.SigningKeyResponse, error) {
+	var activatesAt *time.Time
+	if req.GetActivatesAt() != "" {
+		t, err := time.Parse(time.RFC3339, req.GetActivatesAt())
+		if err != nil {
+			log.Println("WARNING: [AuthHandler - CreateSigningKey] Invalid activates_at:", err)
+			return &pb.SigningKeyResponse{
+				Code:    uint32(http.StatusBadRequest),
+				Message: "activates_at must be an RFC 3339 timestamp",
+			}, status.Errorf(codes.InvalidArgument, "activates_at must be an RFC 3339 timestamp")
+		}
+		activatesAt = &t
+	}
+
+	key, err := ah.signingKeySvc.Create(ctx, req.GetAlg(), activatesAt)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - CreateSigningKey] Error while create signing key:", parseError.Message)
+		return &pb.SigningKeyResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.SigningKeyResponse{
+		Code:    uint32(http.StatusCreated),
+		Message: "create signing key success",
+		Data:    entity.ConvertSigningKeyEntityToProto(key, ah.signingKeySvc.Status(key)),
+	}, nil
+}
+
+func (ah *AuthHandler) PromoteSigningKey(ctx context.Context, req *pb.SigningKeyRequest) (*pb.SigningKeyResponse, error) {
+	key, err := ah.signingKeySvc.Promote(ctx, req.GetKid())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - PromoteSigningKey] Error while promote signing key:", parseError.Message)
+		return &pb.SigningKeyResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.SigningKeyResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "promote signing key success",
+		Data:    entity.ConvertSigningKeyEntityToProto(key, ah.signingKeySvc.Status(key)),
+	}, nil
+}
+
+func (ah *AuthHandler) RetireSigningKey(ctx context.Context, req *pb.SigningKeyRequest) (*pb.SigningKeyResponse, error) {
+	key, err := ah.signingKeySvc.Retire(ctx, req.GetKid())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - RetireSigningKey] Error while retire signing key:", parseError.Message)
+		return &pb.SigningKeyResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.SigningKeyResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "retire signing key success",
+		Data:    entity.ConvertSigningKeyEntityToProto(key, ah.signingKeySvc.Status(key)),
+	}, nil
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (ah *AuthHandler) RequestUserStudyLoginLink(ctx context.Context, req *pb.RequestUserStudyLoginLinkRequest) (*pb.UserStudyLoginLinkResponse, error) {
+	email := strings.TrimSpace(req.GetEmailAtasan())
+	if email == "" {
+		return &pb.UserStudyLoginLinkResponse{
+			Code:    uint32(http.StatusBadRequest),
+			Message: "email_atasan is required",
+		}, status.Errorf(codes.InvalidArgument, "email_atasan is required")
+	}
+
+	if err := ah.userStudyLoginSvc.Request(ctx, email); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - RequestUserStudyLoginLink] Error while requesting login link:", parseError.Message)
+		return &pb.UserStudyLoginLinkResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "failed to request login link",
+		}, status.Errorf(codes.Internal, "failed to request login link")
+	}
+
+	return &pb.UserStudyLoginLinkResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "if the email belongs to an alumni employer, a login link has been sent to it",
+	}, nil
+}
+
+func (ah *AuthHandler) ConsumeUserStudyLoginLink(ctx context.Context, req *pb.ConsumeUserStudyLoginLinkRequest) (*pb.LoginResponse, error) {
+	email, err := ah.userStudyLoginSvc.Consume(ctx, req.GetToken())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ConsumeUserStudyLoginLink] Error while consuming login link:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	// the employer may have lost their alumni since the link was sent
+	nims, err := ah.userStudyLoginSvc.FetchNims(ctx, email)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ConsumeUserStudyLoginLink] Error while fetching alumni:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	if len(nims) == 0 {
+		log.Println("WARNING: [AuthHandler - ConsumeUserStudyLoginLink] User resource not found")
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusNotFound),
+			Message: "user resource not found",
+		}, status.Errorf(codes.NotFound, "user resource not found")
+	}
+
+	// generate token with sub = userstudy:email, role = 7 (pengguna alumni)
+	token, refreshToken, err := ah.generateUserStudyTokenPair(ctx, email, nims)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthHandler - ConsumeUserStudyLoginLink] Error while generating token:", parseError.Message)
+		return &pb.LoginResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "token failed to generate: " + parseError.Message,
+		}, status.Errorf(codes.Internal, "token failed to generate: %v", parseError.Message)
+	}
+
+	return &pb.LoginResponse{
+		Code:         uint32(http.StatusOK),
+		Message:      "login user study success",
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
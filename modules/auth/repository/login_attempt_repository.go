@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"tracerstudy-auth-service/common/throttle"
+	"tracerstudy-auth-service/modules/auth/entity"
+
+	"go.opencensus.io/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptRepository is the database backing of throttle.Store, shared
+// by every replica.
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: db,
+	}
+}
+
+func (r *LoginAttemptRepository) Get(ctx context.Context, key string) (*throttle.Attempts, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "LoginAttemptRepository - Get")
+	defer span.End()
+
+	var attempt entity.LoginAttempt
+	if err := r.db.Debug().WithContext(ctxSpan).Where("throttle_key = ?", key).First(&attempt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Println("ERROR: [LoginAttemptRepository - Get] Internal server error:", err)
+		return nil, err
+	}
+
+	return convertLoginAttempt(&attempt), nil
+}
+
+func (r *LoginAttemptRepository) Fail(ctx context.Context, key string, at time.Time, resetAfter time.Duration) (*throttle.Attempts, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "LoginAttemptRepository - Fail")
+	defer span.End()
+
+	// failures is assigned first, so it still sees the previous failure time
+	attempt := entity.NewLoginAttempt(key, at)
+	if err := r.db.Debug().WithContext(ctxSpan).Clauses(clause.OnConflict{
+		DoUpdates: []clause.Assignment{
+			{Column: clause.Column{Name: "failures"}, Value: gorm.Expr("IF(last_failure_at < ?, 1, failures + 1)", at.Add(-resetAfter))},
+			{Column: clause.Column{Name: "last_failure_at"}, Value: gorm.Expr("VALUES(last_failure_at)")},
+		},
+	}).Create(attempt).Error; err != nil {
+		log.Println("ERROR: [LoginAttemptRepository - Fail] Internal server error:", err)
+		return nil, err
+	}
+
+	return r.Get(ctxSpan, key)
+}
+
+func (r *LoginAttemptRepository) Clear(ctx context.Context, key string) error {
+	ctxSpan, span := trace.StartSpan(ctx, "LoginAttemptRepository - Clear")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Where("throttle_key = ?", key).Delete(&entity.LoginAttempt{}).Error; err != nil {
+		log.Println("ERROR: [LoginAttemptRepository - Clear] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) FindSince(ctx context.Context, since time.Time) ([]*throttle.Attempts, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "LoginAttemptRepository - FindSince")
+	defer span.End()
+
+	var attempts []entity.LoginAttempt
+	if err := r.db.Debug().WithContext(ctxSpan).Where("last_failure_at >= ?", since).Find(&attempts).Error; err != nil {
+		log.Println("ERROR: [LoginAttemptRepository - FindSince] Internal server error:", err)
+		return nil, err
+	}
+
+	res := make([]*throttle.Attempts, 0, len(attempts))
+	for i := range attempts {
+		res = append(res, convertLoginAttempt(&attempts[i]))
+	}
+
+	return res, nil
+}
+
+func convertLoginAttempt(attempt *entity.LoginAttempt) *throttle.Attempts {
+	return &throttle.Attempts{
+		Key:           attempt.ThrottleKey,
+		Failures:      attempt.Failures,
+		LastFailureAt: attempt.LastFailureAt,
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"tracerstudy-auth-service/modules/auth/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type MfaChallengeRepository struct {
+	db *gorm.DB
+}
+
+func NewMfaChallengeRepository(db *gorm.DB) *MfaChallengeRepository {
+	return &MfaChallengeRepository{
+		db: db,
+	}
+}
+
+type MfaChallengeRepositoryUseCase interface {
+	FindByHash(ctx context.Context, tokenHash string) (*entity.MfaChallenge, error)
+	Create(ctx context.Context, req *entity.MfaChallenge) (*entity.MfaChallenge, error)
+	IncrementAttempts(ctx context.Context, id uint64) error
+	MarkUsed(ctx context.Context, id uint64) (bool, error)
+}
+
+func (r *MfaChallengeRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.MfaChallenge, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaChallengeRepository - FindByHash")
+	defer span.End()
+
+	var challenge entity.MfaChallenge
+	if err := r.db.Debug().WithContext(ctxSpan).Where("token_hash = ?", tokenHash).First(&challenge).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [MfaChallengeRepository - FindByHash] Record not found for mfa challenge")
+			return nil, status.Errorf(codes.NotFound, "record not found for mfa challenge")
+		}
+		log.Println("ERROR: [MfaChallengeRepository - FindByHash] Internal server error:", err)
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+func (r *MfaChallengeRepository) Create(ctx context.Context, req *entity.MfaChallenge) (*entity.MfaChallenge, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaChallengeRepository - Create")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Create(req).Error; err != nil {
+		log.Println("ERROR: [MfaChallengeRepository - Create] Internal server error:", err)
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (r *MfaChallengeRepository) IncrementAttempts(ctx context.Context, id uint64) error {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaChallengeRepository - IncrementAttempts")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Model(&entity.MfaChallenge{}).Where("id = ?", id).Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+		log.Println("ERROR: [MfaChallengeRepository - IncrementAttempts] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkUsed consumes the challenge. It reports false when the challenge had
+// already been used, so one challenge cannot complete two logins.
+func (r *MfaChallengeRepository) MarkUsed(ctx context.Context, id uint64) (bool, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaChallengeRepository - MarkUsed")
+	defer span.End()
+
+	res := r.db.Debug().WithContext(ctxSpan).Model(&entity.MfaChallenge{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+	if res.Error != nil {
+		log.Println("ERROR: [MfaChallengeRepository - MarkUsed] Internal server error:", res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"tracerstudy-auth-service/modules/auth/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type MfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMfaRepository(db *gorm.DB) *MfaRepository {
+	return &MfaRepository{
+		db: db,
+	}
+}
+
+type MfaRepositoryUseCase interface {
+	FindCredential(ctx context.Context, userId uint64) (*entity.MfaCredential, error)
+	SaveCredential(ctx context.Context, credential *entity.MfaCredential) (*entity.MfaCredential, error)
+	Enable(ctx context.Context, userId uint64, step int64, codeHashes []string) (bool, error)
+	UseStep(ctx context.Context, userId uint64, step int64) (bool, error)
+	Delete(ctx context.Context, userId uint64) error
+	ReplaceRecoveryCodes(ctx context.Context, userId uint64, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userId uint64, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userId uint64) (int64, error)
+}
+
+func (r *MfaRepository) FindCredential(ctx context.Context, userId uint64) (*entity.MfaCredential, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaRepository - FindCredential")
+	defer span.End()
+
+	var credential entity.MfaCredential
+	if err := r.db.Debug().WithContext(ctxSpan).Where("user_id = ?", userId).First(&credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "record not found for mfa credential of user %d", userId)
+		}
+		log.Println("ERROR: [MfaRepository - FindCredential] Internal server error:", err)
+		return nil, err
+	}
+
+	return &credential, nil
+}
+
+func (r *MfaRepository) SaveCredential(ctx context.Context, credential *entity.MfaCredential) (*entity.MfaCredential, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaRepository - SaveCredential")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Save(credential).Error; err != nil {
+		log.Println("ERROR: [MfaRepository - SaveCredential] Internal server error:", err)
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+// Enable turns on a pending credential together with its recovery codes. It
+// reports false when the credential was already enabled.
+func (r *MfaRepository) Enable(ctx context.Context, userId uint64, step int64, codeHashes []string) (bool, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaRepository - Enable")
+	defer span.End()
+
+	enabled := false
+	err := r.db.Debug().WithContext(ctxSpan).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entity.MfaCredential{}).
+			Where("user_id = ? AND enabled_at IS NULL", userId).
+			Updates(map[string]interface{}{"enabled_at": time.Now(), "last_used_step": step})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		enabled = true
+		return replaceRecoveryCodes(tx, userId, codeHashes)
+	})
+	if err != nil {
+		log.Println("ERROR: [MfaRepository - Enable] Internal server error:", err)
+		return false, err
+	}
+
+	return enabled, nil
+}
+
+// UseStep records that the code of step was used. It reports false when a
+// code of the same or a later step was used already.
+func (r *MfaRepository) UseStep(ctx context.Context, userId uint64, step int64) (bool, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaRepository - UseStep")
+	defer span.End()
+
+	res := r.db.Debug().WithContext(ctxSpan).Model(&entity.MfaCredential{}).Where("user_id = ? AND last_used_step < ?", userId, step).Update("last_used_step", step)
+	if res.Error != nil {
+		log.Println("ERROR: [MfaRepository - UseStep] Internal server error:", res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// Delete removes the credential and the recovery codes of the user.
+func (r *MfaRepository) Delete(ctx context.Context, userId uint64) error {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaRepository - Delete")
+	defer span.End()
+
+	err := r.db.Debug().WithContext(ctxSpan).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&entity.MfaRecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userId).Delete(&entity.MfaCredential{}).Error
+	})
+	if err != nil {
+		log.Println("ERROR: [MfaRepository - Delete] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *MfaRepository) ReplaceRecoveryCodes(ctx context.Context, userId uint64, codeHashes []string) error {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaRepository - ReplaceRecoveryCodes")
+	defer span.End()
+
+	err := r.db.Debug().WithContext(ctxSpan).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userId, codeHashes)
+	})
+	if err != nil {
+		log.Println("ERROR: [MfaRepository - ReplaceRecoveryCodes] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
+
+// UseRecoveryCode consumes a recovery code. It reports false when the user
+// has no unused code with that hash.
+func (r *MfaRepository) UseRecoveryCode(ctx context.Context, userId uint64, codeHash string) (bool, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaRepository - UseRecoveryCode")
+	defer span.End()
+
+	res := r.db.Debug().WithContext(ctxSpan).Model(&entity.MfaRecoveryCode{}).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).Update("used_at", time.Now())
+	if res.Error != nil {
+		log.Println("ERROR: [MfaRepository - UseRecoveryCode] Internal server error:", res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *MfaRepository) CountRecoveryCodes(ctx context.Context, userId uint64) (int64, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "MfaRepository - CountRecoveryCodes")
+	defer span.End()
+
+	var count int64
+	if err := r.db.Debug().WithContext(ctxSpan).Model(&entity.MfaRecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userId).Count(&count).Error; err != nil {
+		log.Println("ERROR: [MfaRepository - CountRecoveryCodes] Internal server error:", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userId uint64, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userId).Delete(&entity.MfaRecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	recoveryCodes := make([]*entity.MfaRecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		recoveryCodes = append(recoveryCodes, entity.NewMfaRecoveryCode(userId, hash))
+	}
+
+	if len(recoveryCodes) == 0 {
+		return nil
+	}
+
+	return tx.Create(&recoveryCodes).Error
+}
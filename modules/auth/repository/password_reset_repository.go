@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"tracerstudy-auth-service/modules/auth/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type PasswordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: db,
+	}
+}
+
+type PasswordResetRepositoryUseCase interface {
+	FindByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+	FindLatestByUser(ctx context.Context, userId uint64) (*entity.PasswordResetToken, error)
+	Create(ctx context.Context, req *entity.PasswordResetToken) (*entity.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id uint64) (bool, error)
+	Release(ctx context.Context, id uint64) error
+	InvalidateUser(ctx context.Context, userId uint64) error
+}
+
+func (r *PasswordResetRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PasswordResetRepository - FindByHash")
+	defer span.End()
+
+	var token entity.PasswordResetToken
+	if err := r.db.Debug().WithContext(ctxSpan).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [PasswordResetRepository - FindByHash] Record not found for reset token")
+			return nil, status.Errorf(codes.NotFound, "record not found for reset token")
+		}
+		log.Println("ERROR: [PasswordResetRepository - FindByHash] Internal server error:", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *PasswordResetRepository) FindLatestByUser(ctx context.Context, userId uint64) (*entity.PasswordResetToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PasswordResetRepository - FindLatestByUser")
+	defer span.End()
+
+	var token entity.PasswordResetToken
+	if err := r.db.Debug().WithContext(ctxSpan).Where("user_id = ?", userId).Order("created_at DESC").First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "record not found for reset token of user %d", userId)
+		}
+		log.Println("ERROR: [PasswordResetRepository - FindLatestByUser] Internal server error:", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *PasswordResetRepository) Create(ctx context.Context, req *entity.PasswordResetToken) (*entity.PasswordResetToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PasswordResetRepository - Create")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Create(req).Error; err != nil {
+		log.Println("ERROR: [PasswordResetRepository - Create] Internal server error:", err)
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// MarkUsed consumes the token. It reports false when the token had already
+// been used, so a token cannot reset the password twice.
+func (r *PasswordResetRepository) MarkUsed(ctx context.Context, id uint64) (bool, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PasswordResetRepository - MarkUsed")
+	defer span.End()
+
+	res := r.db.Debug().WithContext(ctxSpan).Model(&entity.PasswordResetToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+	if res.Error != nil {
+		log.Println("ERROR: [PasswordResetRepository - MarkUsed] Internal server error:", res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// Release makes a token consumed by MarkUsed usable again, for when the
+// password could not be reset with it.
+func (r *PasswordResetRepository) Release(ctx context.Context, id uint64) error {
+	ctxSpan, span := trace.StartSpan(ctx, "PasswordResetRepository - Release")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Model(&entity.PasswordResetToken{}).Where("id = ?", id).Update("used_at", nil).Error; err != nil {
+		log.Println("ERROR: [PasswordResetRepository - Release] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
+
+// InvalidateUser consumes every outstanding token of the user.
+func (r *PasswordResetRepository) InvalidateUser(ctx context.Context, userId uint64) error {
+	ctxSpan, span := trace.StartSpan(ctx, "PasswordResetRepository - InvalidateUser")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Model(&entity.PasswordResetToken{}).Where("user_id = ? AND used_at IS NULL", userId).Update("used_at", time.Now()).Error; err != nil {
+		log.Println("ERROR: [PasswordResetRepository - InvalidateUser] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
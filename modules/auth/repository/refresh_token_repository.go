@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"tracerstudy-auth-service/modules/auth/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db: db,
+	}
+}
+
+type RefreshTokenRepositoryUseCase interface {
+	FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	Create(ctx context.Context, req *entity.RefreshToken) (*entity.RefreshToken, error)
+	MarkUsed(ctx context.Context, id uint64) (bool, error)
+	RevokeFamily(ctx context.Context, familyId string) error
+}
+
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "RefreshTokenRepository - FindByHash")
+	defer span.End()
+
+	var token entity.RefreshToken
+	if err := r.db.Debug().WithContext(ctxSpan).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [RefreshTokenRepository - FindByHash] Record not found for refresh token")
+			return nil, status.Errorf(codes.NotFound, "record not found for refresh token")
+		}
+		log.Println("ERROR: [RefreshTokenRepository - FindByHash] Internal server error:", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, req *entity.RefreshToken) (*entity.RefreshToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "RefreshTokenRepository - Create")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Create(req).Error; err != nil {
+		log.Println("ERROR: [RefreshTokenRepository - Create] Internal server error:", err)
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// MarkUsed flags the token as consumed. It reports false when the token had
+// already been used, so concurrent refreshes of the same token are detected.
+func (r *RefreshTokenRepository) MarkUsed(ctx context.Context, id uint64) (bool, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "RefreshTokenRepository - MarkUsed")
+	defer span.End()
+
+	res := r.db.Debug().WithContext(ctxSpan).Model(&entity.RefreshToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+	if res.Error != nil {
+		log.Println("ERROR: [RefreshTokenRepository - MarkUsed] Internal server error:", res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyId string) error {
+	ctxSpan, span := trace.StartSpan(ctx, "RefreshTokenRepository - RevokeFamily")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Model(&entity.RefreshToken{}).Where("family_id = ? AND revoked_at IS NULL", familyId).Update("revoked_at", time.Now()).Error; err != nil {
+		log.Println("ERROR: [RefreshTokenRepository - RevokeFamily] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
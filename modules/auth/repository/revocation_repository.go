@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"tracerstudy-auth-service/modules/auth/entity"
+
+	"go.opencensus.io/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevocationRepository is the database backing of commonJwt.RevocationStore.
+type RevocationRepository struct {
+	db *gorm.DB
+}
+
+func NewRevocationRepository(db *gorm.DB) *RevocationRepository {
+	return &RevocationRepository{
+		db: db,
+	}
+}
+
+func (r *RevocationRepository) RevokeToken(ctx context.Context, jti, subject string, expiresAt time.Time) error {
+	ctxSpan, span := trace.StartSpan(ctx, "RevocationRepository - RevokeToken")
+	defer span.End()
+
+	revoked := entity.NewRevokedToken(jti, subject, expiresAt)
+	if err := r.db.Debug().WithContext(ctxSpan).Clauses(clause.OnConflict{DoNothing: true}).Create(revoked).Error; err != nil {
+		log.Println("ERROR: [RevocationRepository - RevokeToken] Internal server error:", err)
+		return err
+	}
+
+	if err := r.DeleteExpired(ctxSpan, time.Now()); err != nil {
+		log.Println("WARNING: [RevocationRepository - RevokeToken] Error while deleting expired revocations:", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the revocations of tokens that expired before
+// before, which no longer verify anyway.
+func (r *RevocationRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	ctxSpan, span := trace.StartSpan(ctx, "RevocationRepository - DeleteExpired")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Where("expires_at < ?", before).Delete(&entity.RevokedToken{}).Error; err != nil {
+		log.Println("ERROR: [RevocationRepository - DeleteExpired] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *RevocationRepository) RevokeSubject(ctx context.Context, subject string, revokedAt time.Time) error {
+	ctxSpan, span := trace.StartSpan(ctx, "RevocationRepository - RevokeSubject")
+	defer span.End()
+
+	// a revocation never moves back in time
+	revocation := entity.NewSubjectRevocation(subject, revokedAt)
+	if err := r.db.Debug().WithContext(ctxSpan).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"revoked_at": gorm.Expr("GREATEST(revoked_at, VALUES(revoked_at))"),
+		}),
+	}).Create(revocation).Error; err != nil {
+		log.Println("ERROR: [RevocationRepository - RevokeSubject] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *RevocationRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "RevocationRepository - IsTokenRevoked")
+	defer span.End()
+
+	var count int64
+	if err := r.db.Debug().WithContext(ctxSpan).Model(&entity.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		log.Println("ERROR: [RevocationRepository - IsTokenRevoked] Internal server error:", err)
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *RevocationRepository) SubjectRevokedAt(ctx context.Context, subject string) (time.Time, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "RevocationRepository - SubjectRevokedAt")
+	defer span.End()
+
+	var revocation entity.SubjectRevocation
+	if err := r.db.Debug().WithContext(ctxSpan).Where("subject = ?", subject).First(&revocation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		log.Println("ERROR: [RevocationRepository - SubjectRevokedAt] Internal server error:", err)
+		return time.Time{}, err
+	}
+
+	return revocation.RevokedAt, nil
+}
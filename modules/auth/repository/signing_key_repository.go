@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"tracerstudy-auth-service/modules/auth/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type SigningKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) *SigningKeyRepository {
+	return &SigningKeyRepository{
+		db: db,
+	}
+}
+
+type SigningKeyRepositoryUseCase interface {
+	FindAll(ctx context.Context) ([]*entity.SigningKey, error)
+	FindByKid(ctx context.Context, kid string) (*entity.SigningKey, error)
+	Create(ctx context.Context, req *entity.SigningKey) (*entity.SigningKey, error)
+	Update(ctx context.Context, key *entity.SigningKey, updatedFields map[string]interface{}) (*entity.SigningKey, error)
+}
+
+func (r *SigningKeyRepository) FindAll(ctx context.Context) ([]*entity.SigningKey, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "SigningKeyRepository - FindAll")
+	defer span.End()
+
+	var keys []*entity.SigningKey
+	if err := r.db.Debug().WithContext(ctxSpan).Order("created_at").Find(&keys).Error; err != nil {
+		log.Println("ERROR: [SigningKeyRepository - FindAll] Internal server error:", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *SigningKeyRepository) FindByKid(ctx context.Context, kid string) (*entity.SigningKey, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "SigningKeyRepository - FindByKid")
+	defer span.End()
+
+	var key entity.SigningKey
+	if err := r.db.Debug().WithContext(ctxSpan).Where("kid = ?", kid).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [SigningKeyRepository - FindByKid] Record not found for kid", kid)
+			return nil, status.Errorf(codes.NotFound, "record not found for kid %s", kid)
+		}
+		log.Println("ERROR: [SigningKeyRepository - FindByKid] Internal server error:", err)
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *SigningKeyRepository) Create(ctx context.Context, req *entity.SigningKey) (*entity.SigningKey, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "SigningKeyRepository - Create")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Create(req).Error; err != nil {
+		log.Println("ERROR: [SigningKeyRepository - Create] Internal server error:", err)
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (r *SigningKeyRepository) Update(ctx context.Context, key *entity.SigningKey, updatedFields map[string]interface{}) (*entity.SigningKey, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "SigningKeyRepository - Update")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Model(key).Updates(updatedFields).Error; err != nil {
+		log.Println("ERROR: [SigningKeyRepository - Update] Internal server error:", err)
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"tracerstudy-auth-service/modules/auth/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type UserStudyGrantRepository struct {
+	db *gorm.DB
+}
+
+func NewUserStudyGrantRepository(db *gorm.DB) *UserStudyGrantRepository {
+	return &UserStudyGrantRepository{
+		db: db,
+	}
+}
+
+type UserStudyGrantRepositoryUseCase interface {
+	FindById(ctx context.Context, id string) (*entity.UserStudyGrant, error)
+	Create(ctx context.Context, req *entity.UserStudyGrant) (*entity.UserStudyGrant, error)
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+func (r *UserStudyGrantRepository) FindById(ctx context.Context, id string) (*entity.UserStudyGrant, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "UserStudyGrantRepository - FindById")
+	defer span.End()
+
+	var grant entity.UserStudyGrant
+	if err := r.db.Debug().WithContext(ctxSpan).Where("id = ?", id).First(&grant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [UserStudyGrantRepository - FindById] Record not found for grant", id)
+			return nil, status.Errorf(codes.NotFound, "record not found for grant %s", id)
+		}
+		log.Println("ERROR: [UserStudyGrantRepository - FindById] Internal server error:", err)
+		return nil, err
+	}
+
+	return &grant, nil
+}
+
+func (r *UserStudyGrantRepository) Create(ctx context.Context, req *entity.UserStudyGrant) (*entity.UserStudyGrant, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "UserStudyGrantRepository - Create")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Create(req).Error; err != nil {
+		log.Println("ERROR: [UserStudyGrantRepository - Create] Internal server error:", err)
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (r *UserStudyGrantRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	ctxSpan, span := trace.StartSpan(ctx, "UserStudyGrantRepository - DeleteExpired")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Where("expires_at < ?", before).Delete(&entity.UserStudyGrant{}).Error; err != nil {
+		log.Println("ERROR: [UserStudyGrantRepository - DeleteExpired] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"tracerstudy-auth-service/modules/auth/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type UserStudyLoginRepository struct {
+	db *gorm.DB
+}
+
+func NewUserStudyLoginRepository(db *gorm.DB) *UserStudyLoginRepository {
+	return &UserStudyLoginRepository{
+		db: db,
+	}
+}
+
+type UserStudyLoginRepositoryUseCase interface {
+	FindByHash(ctx context.Context, tokenHash string) (*entity.UserStudyLoginToken, error)
+	FindLatestByEmail(ctx context.Context, email string) (*entity.UserStudyLoginToken, error)
+	Create(ctx context.Context, req *entity.UserStudyLoginToken) (*entity.UserStudyLoginToken, error)
+	MarkUsed(ctx context.Context, id uint64) (bool, error)
+	InvalidateEmail(ctx context.Context, email string) error
+}
+
+func (r *UserStudyLoginRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.UserStudyLoginToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "UserStudyLoginRepository - FindByHash")
+	defer span.End()
+
+	var token entity.UserStudyLoginToken
+	if err := r.db.Debug().WithContext(ctxSpan).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [UserStudyLoginRepository - FindByHash] Record not found for login token")
+			return nil, status.Errorf(codes.NotFound, "record not found for login token")
+		}
+		log.Println("ERROR: [UserStudyLoginRepository - FindByHash] Internal server error:", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *UserStudyLoginRepository) FindLatestByEmail(ctx context.Context, email string) (*entity.UserStudyLoginToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "UserStudyLoginRepository - FindLatestByEmail")
+	defer span.End()
+
+	var token entity.UserStudyLoginToken
+	if err := r.db.Debug().WithContext(ctxSpan).Where("email = ?", strings.ToLower(email)).Order("created_at DESC").First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "record not found for login token of email %s", email)
+		}
+		log.Println("ERROR: [UserStudyLoginRepository - FindLatestByEmail] Internal server error:", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *UserStudyLoginRepository) Create(ctx context.Context, req *entity.UserStudyLoginToken) (*entity.UserStudyLoginToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "UserStudyLoginRepository - Create")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Create(req).Error; err != nil {
+		log.Println("ERROR: [UserStudyLoginRepository - Create] Internal server error:", err)
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// MarkUsed consumes the token. It reports false when the token had already
+// been used.
+func (r *UserStudyLoginRepository) MarkUsed(ctx context.Context, id uint64) (bool, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "UserStudyLoginRepository - MarkUsed")
+	defer span.End()
+
+	res := r.db.Debug().WithContext(ctxSpan).Model(&entity.UserStudyLoginToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+	if res.Error != nil {
+		log.Println("ERROR: [UserStudyLoginRepository - MarkUsed] Internal server error:", res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// InvalidateEmail consumes every outstanding token sent to email.
+func (r *UserStudyLoginRepository) InvalidateEmail(ctx context.Context, email string) error {
+	ctxSpan, span := trace.StartSpan(ctx, "UserStudyLoginRepository - InvalidateEmail")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Model(&entity.UserStudyLoginToken{}).Where("email = ? AND used_at IS NULL", strings.ToLower(email)).Update("used_at", time.Now()).Error; err != nil {
+		log.Println("ERROR: [UserStudyLoginRepository - InvalidateEmail] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
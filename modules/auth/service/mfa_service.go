@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/common/totp"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/auth/entity"
+	"tracerstudy-auth-service/modules/auth/repository"
+	userEntity "tracerstudy-auth-service/modules/user/entity"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	mfaChallengeTokenSize = 32
+	recoveryCodeCount     = 10
+	recoveryCodeBytes     = 5
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type MfaService struct {
+	cfg                    config.Config
+	mfaRepository          repository.MfaRepositoryUseCase
+	mfaChallengeRepository repository.MfaChallengeRepositoryUseCase
+	requiredRoles          map[uint32]bool
+}
+
+type MfaServiceUseCase interface {
+	Required(roleId uint32) bool
+	StartChallenge(ctx context.Context, user *userEntity.User) (string, string, error)
+	FindChallenge(ctx context.Context, token, purpose string) (*entity.MfaChallenge, error)
+	FailChallenge(ctx context.Context, challenge *entity.MfaChallenge)
+	CloseChallenge(ctx context.Context, challenge *entity.MfaChallenge) error
+	BeginEnrollment(ctx context.Context, user *userEntity.User) (string, string, error)
+	ConfirmEnrollment(ctx context.Context, userId uint64, code string) ([]string, error)
+	Verify(ctx context.Context, userId uint64, code, recoveryCode string) error
+	Disable(ctx context.Context, user *userEntity.User, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userId uint64, code string) ([]string, error)
+}
+
+func NewMfaService(cfg config.Config, mfaRepository repository.MfaRepositoryUseCase, mfaChallengeRepository repository.MfaChallengeRepositoryUseCase) *MfaService {
+	requiredRoles := make(map[uint32]bool)
+	for _, role := range strings.Split(cfg.MFA.RequiredRoles, ",") {
+		role = strings.TrimSpace(role)
+		if role == "" {
+			continue
+		}
+
+		roleId, err := strconv.ParseUint(role, 10, 32)
+		if err != nil {
+			log.Println("WARNING: [MfaService - NewMfaService] Ignoring invalid role in MFA_REQUIRED_ROLES:", role)
+			continue
+		}
+		requiredRoles[uint32(roleId)] = true
+	}
+
+	return &MfaService{
+		cfg:                    cfg,
+		mfaRepository:          mfaRepository,
+		mfaChallengeRepository: mfaChallengeRepository,
+		requiredRoles:          requiredRoles,
+	}
+}
+
+// Required reports whether users of the role must use MFA.
+func (svc *MfaService) Required(roleId uint32) bool {
+	return svc.requiredRoles[roleId]
+}
+
+// StartChallenge opens the second login step for a user whose password was
+// accepted. It returns the challenge token and its purpose: verify when the
+// user has MFA enabled, enroll when the user's role requires MFA but the
+// user has not enrolled yet. Users who need neither get an empty token.
+func (svc *MfaService) StartChallenge(ctx context.Context, user *userEntity.User) (string, string, error) {
+	enabled, err := svc.enabled(ctx, user.Id)
+	if err != nil {
+		return "", "", err
+	}
+
+	purpose := entity.MfaChallengeVerify
+	if !enabled {
+		if !svc.Required(user.RoleId) {
+			return "", "", nil
+		}
+		purpose = entity.MfaChallengeEnroll
+	}
+
+	token, err := utils.GenerateRandomToken(mfaChallengeTokenSize)
+	if err != nil {
+		log.Println("ERROR: [MfaService - StartChallenge] Error while generating challenge token:", err)
+		return "", "", status.Errorf(codes.Internal, "failed to generate mfa token")
+	}
+
+	challenge := entity.NewMfaChallenge(user.Id, utils.HashToken(token), purpose, time.Now().Add(svc.cfg.MFA.ChallengeDuration))
+	if _, err := svc.mfaChallengeRepository.Create(ctx, challenge); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [MfaService - StartChallenge] Error while create mfa challenge:", parseError.Message)
+		return "", "", err
+	}
+
+	return token, purpose, nil
+}
+
+// FindChallenge returns the open challenge of token, provided it was issued
+// for purpose.
+func (svc *MfaService) FindChallenge(ctx context.Context, token, purpose string) (*entity.MfaChallenge, error) {
+	challenge, err := svc.mfaChallengeRepository.FindByHash(ctx, utils.HashToken(token))
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			return nil, status.Errorf(codes.Unauthenticated, "mfa token is invalid")
+		}
+		log.Println("ERROR: [MfaService - FindChallenge] Error while find mfa challenge:", parseError.Message)
+		return nil, err
+	}
+
+	switch {
+	case challenge.Purpose != purpose:
+		log.Println("WARNING: [MfaService - FindChallenge] Challenge presented for the wrong purpose:", challenge.Purpose)
+		return nil, status.Errorf(codes.Unauthenticated, "mfa token is invalid")
+	case challenge.UsedAt != nil:
+		return nil, status.Errorf(codes.Unauthenticated, "mfa token has already been used")
+	case time.Now().After(challenge.ExpiresAt):
+		return nil, status.Errorf(codes.Unauthenticated, "mfa token has expired")
+	case challenge.Attempts >= svc.cfg.MFA.MaxChallengeAttempts:
+		return nil, status.Errorf(codes.Unauthenticated, "too many invalid codes, log in again")
+	}
+
+	return challenge, nil
+}
+
+// FailChallenge counts a wrong code against the challenge.
+func (svc *MfaService) FailChallenge(ctx context.Context, challenge *entity.MfaChallenge) {
+	if err := svc.mfaChallengeRepository.IncrementAttempts(ctx, challenge.Id); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [MfaService - FailChallenge] Error while counting challenge attempt:", parseError.Message)
+	}
+}
+
+// CloseChallenge consumes the challenge once it was answered.
+func (svc *MfaService) CloseChallenge(ctx context.Context, challenge *entity.MfaChallenge) error {
+	closed, err := svc.mfaChallengeRepository.MarkUsed(ctx, challenge.Id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [MfaService - CloseChallenge] Error while marking mfa challenge as used:", parseError.Message)
+		return err
+	}
+
+	if !closed {
+		log.Println("WARNING: [MfaService - CloseChallenge] Challenge was used concurrently for user", challenge.UserId)
+		return status.Errorf(codes.Unauthenticated, "mfa token has already been used")
+	}
+
+	return nil
+}
+
+// BeginEnrollment creates a new pending secret for the user, replacing any
+// earlier unconfirmed one, and returns it with its otpauth URI.
+func (svc *MfaService) BeginEnrollment(ctx context.Context, user *userEntity.User) (string, string, error) {
+	enabled, err := svc.enabled(ctx, user.Id)
+	if err != nil {
+		return "", "", err
+	}
+
+	if enabled {
+		return "", "", status.Errorf(codes.AlreadyExists, "mfa is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		log.Println("ERROR: [MfaService - BeginEnrollment] Error while generating totp secret:", err)
+		return "", "", status.Errorf(codes.Internal, "failed to generate mfa secret")
+	}
+
+	if _, err := svc.mfaRepository.SaveCredential(ctx, entity.NewMfaCredential(user.Id, secret)); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [MfaService - BeginEnrollment] Error while save mfa credential:", parseError.Message)
+		return "", "", err
+	}
+
+	return secret, totp.URI(svc.cfg.MFA.Issuer, user.Username, secret), nil
+}
+
+// ConfirmEnrollment enables the pending secret once the user proves their
+// authenticator produces its codes, and returns fresh recovery codes.
+func (svc *MfaService) ConfirmEnrollment(ctx context.Context, userId uint64, code string) ([]string, error) {
+	credential, err := svc.mfaRepository.FindCredential(ctx, userId)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			return nil, status.Errorf(codes.FailedPrecondition, "mfa enrollment has not been started")
+		}
+		log.Println("ERROR: [MfaService - ConfirmEnrollment] Error while find mfa credential:", parseError.Message)
+		return nil, err
+	}
+
+	if credential.Enabled() {
+		return nil, status.Errorf(codes.AlreadyExists, "mfa is already enabled")
+	}
+
+	step, valid, err := totp.Validate(credential.Secret, code, time.Now(), credential.LastUsedStep)
+	if err != nil {
+		log.Println("ERROR: [MfaService - ConfirmEnrollment] Error while validating totp code:", err)
+		return nil, status.Errorf(codes.Internal, "failed to validate mfa code")
+	}
+
+	if !valid {
+		return nil, status.Errorf(codes.Unauthenticated, "mfa code is invalid")
+	}
+
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		log.Println("ERROR: [MfaService - ConfirmEnrollment] Error while generating recovery codes:", err)
+		return nil, status.Errorf(codes.Internal, "failed to generate recovery codes")
+	}
+
+	enabled, err := svc.mfaRepository.Enable(ctx, userId, step, hashes)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [MfaService - ConfirmEnrollment] Error while enabling mfa:", parseError.Message)
+		return nil, err
+	}
+
+	if !enabled {
+		return nil, status.Errorf(codes.AlreadyExists, "mfa is already enabled")
+	}
+
+	return recoveryCodes, nil
+}
+
+// Verify accepts either a TOTP code or an unused recovery code of the user.
+// Each code is accepted only once.
+func (svc *MfaService) Verify(ctx context.Context, userId uint64, code, recoveryCode string) error {
+	credential, err := svc.mfaRepository.FindCredential(ctx, userId)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			return status.Errorf(codes.FailedPrecondition, "mfa is not enabled")
+		}
+		log.Println("ERROR: [MfaService - Verify] Error while find mfa credential:", parseError.Message)
+		return err
+	}
+
+	if !credential.Enabled() {
+		return status.Errorf(codes.FailedPrecondition, "mfa is not enabled")
+	}
+
+	if recoveryCode != "" {
+		return svc.useRecoveryCode(ctx, userId, recoveryCode)
+	}
+
+	step, valid, err := totp.Validate(credential.Secret, code, time.Now(), credential.LastUsedStep)
+	if err != nil {
+		log.Println("ERROR: [MfaService - Verify] Error while validating totp code:", err)
+		return status.Errorf(codes.Internal, "failed to validate mfa code")
+	}
+
+	if !valid {
+		return status.Errorf(codes.Unauthenticated, "mfa code is invalid")
+	}
+
+	used, err := svc.mfaRepository.UseStep(ctx, userId, step)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [MfaService - Verify] Error while recording used totp step:", parseError.Message)
+		return err
+	}
+
+	if !used {
+		return status.Errorf(codes.Unauthenticated, "mfa code has already been used")
+	}
+
+	return nil
+}
+
+// Disable turns MFA off for a user who proved a current code. Users whose
+// role requires MFA cannot turn it off.
+func (svc *MfaService) Disable(ctx context.Context, user *userEntity.User, code string) error {
+	if svc.Required(user.RoleId) {
+		return status.Errorf(codes.FailedPrecondition, "mfa is required for the user's role")
+	}
+
+	if err := svc.Verify(ctx, user.Id, code, ""); err != nil {
+		return err
+	}
+
+	if err := svc.mfaRepository.Delete(ctx, user.Id); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [MfaService - Disable] Error while delete mfa credential:", parseError.Message)
+		return err
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of a user who proved
+// a current code.
+func (svc *MfaService) RegenerateRecoveryCodes(ctx context.Context, userId uint64, code string) ([]string, error) {
+	if err := svc.Verify(ctx, userId, code, ""); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		log.Println("ERROR: [MfaService - RegenerateRecoveryCodes] Error while generating recovery codes:", err)
+		return nil, status.Errorf(codes.Internal, "failed to generate recovery codes")
+	}
+
+	if err := svc.mfaRepository.ReplaceRecoveryCodes(ctx, userId, hashes); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [MfaService - RegenerateRecoveryCodes] Error while replacing recovery codes:", parseError.Message)
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (svc *MfaService) enabled(ctx context.Context, userId uint64) (bool, error) {
+	credential, err := svc.mfaRepository.FindCredential(ctx, userId)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			return false, nil
+		}
+		log.Println("ERROR: [MfaService - enabled] Error while find mfa credential:", parseError.Message)
+		return false, err
+	}
+
+	return credential.Enabled(), nil
+}
+
+func (svc *MfaService) useRecoveryCode(ctx context.Context, userId uint64, recoveryCode string) error {
+	used, err := svc.mfaRepository.UseRecoveryCode(ctx, userId, utils.HashToken(normalizeRecoveryCode(recoveryCode)))
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [MfaService - Verify] Error while using recovery code:", parseError.Message)
+		return err
+	}
+
+	if !used {
+		return status.Errorf(codes.Unauthenticated, "recovery code is invalid")
+	}
+
+	remaining, err := svc.mfaRepository.CountRecoveryCodes(ctx, userId)
+	if err == nil && remaining <= 2 {
+		log.Println("WARNING: [MfaService - Verify] User", userId, "has", remaining, "recovery codes left")
+	}
+
+	return nil
+}
+
+// generateRecoveryCodes returns recovery codes formatted as xxxx-xxxx
+// alongside the hashes to store.
+func generateRecoveryCodes() ([]string, []string, error) {
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))
+		recoveryCodes = append(recoveryCodes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, utils.HashToken(code))
+	}
+
+	return recoveryCodes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
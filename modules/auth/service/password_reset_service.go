@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/common/mailer"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/auth/entity"
+	"tracerstudy-auth-service/modules/auth/repository"
+	userSvc "tracerstudy-auth-service/modules/user/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	passwordResetTokenSize   = 32
+	passwordResetMailTimeout = 30 * time.Second
+)
+
+type PasswordResetService struct {
+	cfg                     config.Config
+	passwordResetRepository repository.PasswordResetRepositoryUseCase
+	userSvc                 userSvc.UserServiceUseCase
+	mailer                  mailer.Mailer
+}
+
+type PasswordResetServiceUseCase interface {
+	Request(ctx context.Context, email string) error
+	Reset(ctx context.Context, token, newPassword string) error
+}
+
+func NewPasswordResetService(cfg config.Config, passwordResetRepository repository.PasswordResetRepositoryUseCase, userService userSvc.UserServiceUseCase, mailer mailer.Mailer) *PasswordResetService {
+	return &PasswordResetService{
+		cfg:                     cfg,
+		passwordResetRepository: passwordResetRepository,
+		userSvc:                 userService,
+		mailer:                  mailer,
+	}
+}
+
+// Request mails a reset link to the user registered with email. Unknown
+// emails and requests within the resend interval succeed silently, and the
+// mail is sent in the background, so the caller cannot tell whether the
+// email is registered.
+func (svc *PasswordResetService) Request(ctx context.Context, email string) error {
+	user, err := svc.userSvc.FindByEmail(ctx, email)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			log.Println("INFO: [PasswordResetService - Request] Password reset requested for unknown email")
+			return nil
+		}
+		log.Println("ERROR: [PasswordResetService - Request] Error while find user by email:", parseError.Message)
+		return err
+	}
+
+	latest, err := svc.passwordResetRepository.FindLatestByUser(ctx, user.Id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code != codes.NotFound {
+			log.Println("ERROR: [PasswordResetService - Request] Error while find latest reset token:", parseError.Message)
+			return err
+		}
+	} else if time.Since(latest.CreatedAt) < svc.cfg.Password.ResetResendInterval {
+		log.Println("INFO: [PasswordResetService - Request] Password reset requested too soon for user", user.Id)
+		return nil
+	}
+
+	// only the latest link works
+	if err := svc.passwordResetRepository.InvalidateUser(ctx, user.Id); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [PasswordResetService - Request] Error while invalidating reset tokens:", parseError.Message)
+		return err
+	}
+
+	token, err := utils.GenerateRandomToken(passwordResetTokenSize)
+	if err != nil {
+		log.Println("ERROR: [PasswordResetService - Request] Error while generating reset token:", err)
+		return status.Errorf(codes.Internal, "failed to generate reset token")
+	}
+
+	resetToken := entity.NewPasswordResetToken(user.Id, utils.HashToken(token), time.Now().Add(svc.cfg.Password.ResetTokenDuration))
+	if _, err := svc.passwordResetRepository.Create(ctx, resetToken); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [PasswordResetService - Request] Error while create reset token:", parseError.Message)
+		return err
+	}
+
+	msg := &mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Tracer Study password",
+		Body:    svc.resetMailBody(user.Name, token),
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), passwordResetMailTimeout)
+		defer cancel()
+
+		if err := svc.mailer.Send(ctx, msg); err != nil {
+			log.Println("ERROR: [PasswordResetService - Request] Error while sending reset mail:", err)
+		}
+	}()
+
+	return nil
+}
+
+// Reset sets a new password with a token from Request. The token is
+// consumed only once the new password has been accepted.
+func (svc *PasswordResetService) Reset(ctx context.Context, token, newPassword string) error {
+	invalid := status.Errorf(codes.InvalidArgument, "reset token is invalid or has expired")
+
+	resetToken, err := svc.passwordResetRepository.FindByHash(ctx, utils.HashToken(token))
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			return invalid
+		}
+		log.Println("ERROR: [PasswordResetService - Reset] Error while find reset token:", parseError.Message)
+		return err
+	}
+
+	if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+		log.Println("WARNING: [PasswordResetService - Reset] Used or expired reset token for user", resetToken.UserId)
+		return invalid
+	}
+
+	if err := svc.userSvc.ValidatePassword(ctx, resetToken.UserId, newPassword); err != nil {
+		return err
+	}
+
+	marked, err := svc.passwordResetRepository.MarkUsed(ctx, resetToken.Id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [PasswordResetService - Reset] Error while marking reset token as used:", parseError.Message)
+		return err
+	}
+
+	if !marked {
+		log.Println("WARNING: [PasswordResetService - Reset] Reset token used concurrently for user", resetToken.UserId)
+		return invalid
+	}
+
+	if err := svc.userSvc.ResetPassword(ctx, resetToken.UserId, newPassword); err != nil {
+		// the password did not change, so the link has to keep working
+		if releaseErr := svc.passwordResetRepository.Release(ctx, resetToken.Id); releaseErr != nil {
+			log.Println("ERROR: [PasswordResetService - Reset] Error while releasing reset token:", errors.ParseError(releaseErr).Message)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (svc *PasswordResetService) resetMailBody(name, token string) string {
+	return fmt.Sprintf(
+		"Hello %s,\n\nWe received a request to reset your Tracer Study password. Use the link below within %s to choose a new one:\n\n%s\n\nIf you did not ask for this, you can ignore this email; your password stays the same.\n",
+		name, svc.cfg.Password.ResetTokenDuration, mailer.Link(svc.cfg.Password.ResetURL, token),
+	)
+}
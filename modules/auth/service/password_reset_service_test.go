@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/mailer"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/auth/entity"
+	userEntity "tracerstudy-auth-service/modules/user/entity"
+	userSvc "tracerstudy-auth-service/modules/user/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakePasswordResetRepository struct {
+	tokens      []*entity.PasswordResetToken
+	invalidated int
+}
+
+func (r *fakePasswordResetRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "reset token not found")
+}
+
+func (r *fakePasswordResetRepository) FindLatestByUser(ctx context.Context, userId uint64) (*entity.PasswordResetToken, error) {
+	var latest *entity.PasswordResetToken
+	for _, t := range r.tokens {
+		if t.UserId == userId && (latest == nil || t.CreatedAt.After(latest.CreatedAt)) {
+			latest = t
+		}
+	}
+	if latest == nil {
+		return nil, status.Errorf(codes.NotFound, "reset token not found")
+	}
+	return latest, nil
+}
+
+func (r *fakePasswordResetRepository) Create(ctx context.Context, req *entity.PasswordResetToken) (*entity.PasswordResetToken, error) {
+	req.Id = uint64(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, req)
+	return req, nil
+}
+
+func (r *fakePasswordResetRepository) MarkUsed(ctx context.Context, id uint64) (bool, error) {
+	for _, t := range r.tokens {
+		if t.Id == id && t.UsedAt == nil {
+			now := time.Now()
+			t.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakePasswordResetRepository) Release(ctx context.Context, id uint64) error {
+	for _, t := range r.tokens {
+		if t.Id == id {
+			t.UsedAt = nil
+		}
+	}
+	return nil
+}
+
+func (r *fakePasswordResetRepository) InvalidateUser(ctx context.Context, userId uint64) error {
+	r.invalidated++
+	return nil
+}
+
+type fakeUserService struct {
+	userSvc.UserServiceUseCase
+	users    map[string]*userEntity.User
+	reset    bool
+	resetErr error
+}
+
+func (s *fakeUserService) FindByEmail(ctx context.Context, email string) (*userEntity.User, error) {
+	user, ok := s.users[email]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "user not found")
+	}
+	return user, nil
+}
+
+func (s *fakeUserService) ValidatePassword(ctx context.Context, id uint64, password string) error {
+	if len(password) < 8 {
+		return status.Errorf(codes.InvalidArgument, "new_password must be at least 8 characters long")
+	}
+	return nil
+}
+
+func (s *fakeUserService) ResetPassword(ctx context.Context, id uint64, newPassword string) error {
+	if s.resetErr != nil {
+		return s.resetErr
+	}
+	s.reset = true
+	return nil
+}
+
+type fakeMailer struct {
+	sent chan *mailer.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	m.sent <- msg
+	return nil
+}
+
+func TestPasswordResetServiceRequest(t *testing.T) {
+	const email = "budi@example.com"
+
+	tests := []struct {
+		name           string
+		email          string
+		previousAge    time.Duration
+		wantToken      bool
+		wantInvalidate bool
+	}{
+		{
+			name:           "first request",
+			email:          email,
+			wantToken:      true,
+			wantInvalidate: true,
+		},
+		{
+			name:        "request within the resend interval",
+			email:       email,
+			previousAge: 30 * time.Second,
+		},
+		{
+			name:           "request after the resend interval",
+			email:          email,
+			previousAge:    2 * time.Minute,
+			wantToken:      true,
+			wantInvalidate: true,
+		},
+		{
+			name:  "unknown email",
+			email: "siti@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePasswordResetRepository{}
+			if tt.previousAge != 0 {
+				repo.tokens = append(repo.tokens, &entity.PasswordResetToken{Id: 1, UserId: 1, CreatedAt: time.Now().Add(-tt.previousAge)})
+			}
+			users := &fakeUserService{users: map[string]*userEntity.User{email: {Id: 1, Name: "Budi", Email: email}}}
+			mail := &fakeMailer{sent: make(chan *mailer.Message, 1)}
+
+			cfg := config.Config{Password: config.Password{
+				ResetURL:            "https://tracer.example.com/reset",
+				ResetTokenDuration:  30 * time.Minute,
+				ResetResendInterval: time.Minute,
+			}}
+			svc := NewPasswordResetService(cfg, repo, users, mail)
+
+			before := len(repo.tokens)
+			if err := svc.Request(context.Background(), tt.email); err != nil {
+				t.Fatalf("Request() error = %v", err)
+			}
+
+			if created := len(repo.tokens) > before; created != tt.wantToken {
+				t.Fatalf("token created = %v, want %v", created, tt.wantToken)
+			}
+			if invalidated := repo.invalidated > 0; invalidated != tt.wantInvalidate {
+				t.Errorf("previous tokens invalidated = %v, want %v", invalidated, tt.wantInvalidate)
+			}
+
+			if !tt.wantToken {
+				select {
+				case msg := <-mail.sent:
+					t.Errorf("mail sent to %s, want none", msg.To)
+				case <-time.After(50 * time.Millisecond):
+				}
+				return
+			}
+
+			select {
+			case msg := <-mail.sent:
+				if msg.To != email || !strings.Contains(msg.Body, cfg.Password.ResetURL+"?token=") {
+					t.Errorf("mail = %+v, want a reset link to %s", msg, email)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("no mail sent")
+			}
+		})
+	}
+}
+
+func TestPasswordResetServiceReset(t *testing.T) {
+	const token = "reset-token"
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		modify      func(*entity.PasswordResetToken)
+		token       string
+		newPassword string
+		resetErr    error
+		wantCode    codes.Code
+		wantReset   bool
+	}{
+		{
+			name:        "valid token",
+			token:       token,
+			newPassword: "Kopi-Tubruk-42",
+			wantReset:   true,
+		},
+		{
+			name:        "unknown token",
+			token:       "other-token",
+			newPassword: "Kopi-Tubruk-42",
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "used token",
+			modify:      func(r *entity.PasswordResetToken) { r.UsedAt = &past },
+			token:       token,
+			newPassword: "Kopi-Tubruk-42",
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "expired token",
+			modify:      func(r *entity.PasswordResetToken) { r.ExpiresAt = past },
+			token:       token,
+			newPassword: "Kopi-Tubruk-42",
+			wantCode:    codes.InvalidArgument,
+		},
+		{
+			name:        "failed password update keeps the token",
+			token:       token,
+			newPassword: "Kopi-Tubruk-42",
+			resetErr:    status.Errorf(codes.Internal, "database is down"),
+			wantCode:    codes.Internal,
+		},
+		{
+			name:        "rejected password keeps the token",
+			token:       token,
+			newPassword: "short",
+			wantCode:    codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetToken := entity.NewPasswordResetToken(1, utils.HashToken(token), time.Now().Add(time.Hour))
+			resetToken.Id = 1
+			if tt.modify != nil {
+				tt.modify(resetToken)
+			}
+			repo := &fakePasswordResetRepository{tokens: []*entity.PasswordResetToken{resetToken}}
+			users := &fakeUserService{resetErr: tt.resetErr}
+			svc := NewPasswordResetService(config.Config{}, repo, users, &fakeMailer{})
+
+			err := svc.Reset(context.Background(), tt.token, tt.newPassword)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("Reset() error = %v, want %v", err, tt.wantCode)
+			}
+			if users.reset != tt.wantReset {
+				t.Errorf("password reset = %v, want %v", users.reset, tt.wantReset)
+			}
+			if tt.modify == nil && tt.token == token && (resetToken.UsedAt != nil) != tt.wantReset {
+				t.Errorf("token used = %v, want %v", resetToken.UsedAt != nil, tt.wantReset)
+			}
+		})
+	}
+}
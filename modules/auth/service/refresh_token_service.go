@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/auth/entity"
+	"tracerstudy-auth-service/modules/auth/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	refreshTokenSize = 32
+)
+
+type RefreshTokenService struct {
+	cfg                    config.Config
+	refreshTokenRepository repository.RefreshTokenRepositoryUseCase
+	jwtManager             *commonJwt.JWT
+}
+
+type RefreshTokenServiceUseCase interface {
+	Issue(ctx context.Context, subject string, role uint32) (string, error)
+	Check(ctx context.Context, refreshToken string) (*entity.RefreshToken, error)
+	Rotate(ctx context.Context, current *entity.RefreshToken) (string, error)
+	Revoke(ctx context.Context, refreshToken, subject string) error
+}
+
+func NewRefreshTokenService(cfg config.Config, refreshTokenRepository repository.RefreshTokenRepositoryUseCase, jwtManager *commonJwt.JWT) *RefreshTokenService {
+	return &RefreshTokenService{
+		cfg:                    cfg,
+		refreshTokenRepository: refreshTokenRepository,
+		jwtManager:             jwtManager,
+	}
+}
+
+// Issue starts a new token family for a fresh login.
+func (svc *RefreshTokenService) Issue(ctx context.Context, subject string, role uint32) (string, error) {
+	familyId, err := utils.GenerateRandomToken(refreshTokenSize)
+	if err != nil {
+		log.Println("ERROR: [RefreshTokenService - Issue] Error while generating token family:", err)
+		return "", status.Errorf(codes.Internal, "failed to generate refresh token")
+	}
+
+	return svc.issue(ctx, familyId, subject, role)
+}
+
+// Check returns the stored refresh token if it may still be rotated, without
+// consuming it, so the caller can build the new access token first and a
+// failure there leaves the token usable for a retry. Presenting a token that
+// was already consumed revokes the family.
+func (svc *RefreshTokenService) Check(ctx context.Context, refreshToken string) (*entity.RefreshToken, error) {
+	current, err := svc.refreshTokenRepository.FindByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			log.Println("WARNING: [RefreshTokenService - Check] Unknown refresh token")
+			return nil, status.Errorf(codes.Unauthenticated, "refresh token is invalid")
+		}
+		log.Println("ERROR: [RefreshTokenService - Check] Error while find refresh token:", parseError.Message)
+		return nil, err
+	}
+
+	if current.RevokedAt != nil {
+		log.Println("WARNING: [RefreshTokenService - Check] Refresh token has been revoked")
+		return nil, status.Errorf(codes.Unauthenticated, "refresh token has been revoked")
+	}
+
+	if current.UsedAt != nil {
+		return nil, svc.revokeReusedFamily(ctx, current)
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		log.Println("WARNING: [RefreshTokenService - Check] Refresh token has expired")
+		return nil, status.Errorf(codes.Unauthenticated, "refresh token has expired")
+	}
+
+	subjectRevoked, err := svc.jwtManager.IsSubjectRevoked(ctx, current.Subject, current.CreatedAt)
+	if err != nil {
+		log.Println("ERROR: [RefreshTokenService - Check] Error while checking subject revocation:", err)
+		return nil, err
+	}
+
+	if subjectRevoked {
+		log.Println("WARNING: [RefreshTokenService - Check] Refresh token was issued before the subject was revoked")
+		return nil, status.Errorf(codes.Unauthenticated, "refresh token has been revoked")
+	}
+
+	return current, nil
+}
+
+// Rotate consumes a refresh token returned by Check and returns its
+// replacement from the same family. Losing the race to consume it revokes
+// the family.
+func (svc *RefreshTokenService) Rotate(ctx context.Context, current *entity.RefreshToken) (string, error) {
+	marked, err := svc.refreshTokenRepository.MarkUsed(ctx, current.Id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RefreshTokenService - Rotate] Error while marking refresh token as used:", parseError.Message)
+		return "", err
+	}
+
+	if !marked {
+		return "", svc.revokeReusedFamily(ctx, current)
+	}
+
+	return svc.issue(ctx, current.FamilyId, current.Subject, current.Role)
+}
+
+// Revoke ends the token family of refreshToken, provided it belongs to subject.
+func (svc *RefreshTokenService) Revoke(ctx context.Context, refreshToken, subject string) error {
+	current, err := svc.refreshTokenRepository.FindByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			log.Println("WARNING: [RefreshTokenService - Revoke] Unknown refresh token")
+			return status.Errorf(codes.InvalidArgument, "refresh token is invalid")
+		}
+		log.Println("ERROR: [RefreshTokenService - Revoke] Error while find refresh token:", parseError.Message)
+		return err
+	}
+
+	if current.Subject != subject {
+		log.Println("WARNING: [RefreshTokenService - Revoke] Refresh token does not belong to the caller")
+		return status.Errorf(codes.InvalidArgument, "refresh token is invalid")
+	}
+
+	if err := svc.refreshTokenRepository.RevokeFamily(ctx, current.FamilyId); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RefreshTokenService - Revoke] Error while revoking refresh token family:", parseError.Message)
+		return err
+	}
+
+	return nil
+}
+
+func (svc *RefreshTokenService) issue(ctx context.Context, familyId, subject string, role uint32) (string, error) {
+	token, err := utils.GenerateRandomToken(refreshTokenSize)
+	if err != nil {
+		log.Println("ERROR: [RefreshTokenService - issue] Error while generating refresh token:", err)
+		return "", status.Errorf(codes.Internal, "failed to generate refresh token")
+	}
+
+	refreshToken := entity.NewRefreshToken(familyId, utils.HashToken(token), subject, role, time.Now().Add(svc.cfg.JWT.RefreshTokenDuration))
+
+	if _, err := svc.refreshTokenRepository.Create(ctx, refreshToken); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RefreshTokenService - issue] Error while create refresh token:", parseError.Message)
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (svc *RefreshTokenService) revokeReusedFamily(ctx context.Context, token *entity.RefreshToken) error {
+	log.Println("WARNING: [RefreshTokenService - revokeReusedFamily] Refresh token reuse detected, revoking family for subject:", token.Subject)
+
+	if err := svc.refreshTokenRepository.RevokeFamily(ctx, token.FamilyId); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RefreshTokenService - revokeReusedFamily] Error while revoking refresh token family:", parseError.Message)
+		return err
+	}
+
+	return status.Errorf(codes.Unauthenticated, "refresh token has already been used")
+}
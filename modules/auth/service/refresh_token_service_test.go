@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/auth/entity"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRefreshTokenRepository struct {
+	tokens          []*entity.RefreshToken
+	loseMarkUsed    bool
+	revokedFamilies []string
+}
+
+func (r *fakeRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			token := *t
+			return &token, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "refresh token not found")
+}
+
+func (r *fakeRefreshTokenRepository) Create(ctx context.Context, req *entity.RefreshToken) (*entity.RefreshToken, error) {
+	req.Id = uint64(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, req)
+	return req, nil
+}
+
+func (r *fakeRefreshTokenRepository) MarkUsed(ctx context.Context, id uint64) (bool, error) {
+	if r.loseMarkUsed {
+		return false, nil
+	}
+	for _, t := range r.tokens {
+		if t.Id == id && t.UsedAt == nil {
+			now := time.Now()
+			t.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeFamily(ctx context.Context, familyId string) error {
+	r.revokedFamilies = append(r.revokedFamilies, familyId)
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.FamilyId == familyId {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+type fakeRevocationStore struct {
+	subjects map[string]time.Time
+}
+
+func (s *fakeRevocationStore) RevokeToken(ctx context.Context, jti, subject string, expiresAt time.Time) error {
+	return nil
+}
+
+func (s *fakeRevocationStore) RevokeSubject(ctx context.Context, subject string, revokedAt time.Time) error {
+	s.subjects[subject] = revokedAt
+	return nil
+}
+
+func (s *fakeRevocationStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return false, nil
+}
+
+func (s *fakeRevocationStore) SubjectRevokedAt(ctx context.Context, subject string) (time.Time, error) {
+	return s.subjects[subject], nil
+}
+
+func TestRefreshTokenServiceCheckAndRotate(t *testing.T) {
+	const (
+		presented = "presented-refresh-token"
+		familyId  = "family"
+		subject   = "user:1"
+	)
+
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name          string
+		token         func(*entity.RefreshToken)
+		unknown       bool
+		loseMarkUsed  bool
+		revokeSubject bool
+		wantMessage   string
+		wantRevoked   bool
+	}{
+		{
+			name: "valid token is replaced within its family",
+		},
+		{
+			name:        "unknown token",
+			unknown:     true,
+			wantMessage: "refresh token is invalid",
+		},
+		{
+			name:        "revoked token",
+			token:       func(r *entity.RefreshToken) { r.RevokedAt = &past },
+			wantMessage: "refresh token has been revoked",
+		},
+		{
+			name:        "reused token revokes its family",
+			token:       func(r *entity.RefreshToken) { r.UsedAt = &past },
+			wantMessage: "refresh token has already been used",
+			wantRevoked: true,
+		},
+		{
+			name:        "expired token",
+			token:       func(r *entity.RefreshToken) { r.ExpiresAt = past },
+			wantMessage: "refresh token has expired",
+		},
+		{
+			name:          "token issued before the subject was revoked",
+			revokeSubject: true,
+			wantMessage:   "refresh token has been revoked",
+		},
+		{
+			name:         "concurrent rotation revokes its family",
+			loseMarkUsed: true,
+			wantMessage:  "refresh token has already been used",
+			wantRevoked:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			current := &entity.RefreshToken{
+				Id:        1,
+				FamilyId:  familyId,
+				TokenHash: utils.HashToken(presented),
+				Subject:   subject,
+				Role:      1,
+				ExpiresAt: time.Now().Add(time.Hour),
+				CreatedAt: time.Now().Add(-time.Hour),
+			}
+			if tt.token != nil {
+				tt.token(current)
+			}
+
+			repo := &fakeRefreshTokenRepository{loseMarkUsed: tt.loseMarkUsed}
+			if !tt.unknown {
+				repo.tokens = append(repo.tokens, current)
+			}
+
+			store := &fakeRevocationStore{subjects: make(map[string]time.Time)}
+			if tt.revokeSubject {
+				store.subjects[subject] = time.Now()
+			}
+
+			cfg := config.Config{JWT: config.JWTConfig{RefreshTokenDuration: time.Hour}}
+			jwtManager := commonJwt.NewJWT(cfg.JWT, commonJwt.NewHMACSigningKey("", []byte("secret")), store)
+			svc := NewRefreshTokenService(cfg, repo, jwtManager)
+
+			checked, err := svc.Check(ctx, presented)
+			var next string
+			if err == nil {
+				// checking again, as a retry after a failed scope lookup
+				// would, must not consume the token
+				checked, err = svc.Check(ctx, presented)
+			}
+			if err == nil {
+				next, err = svc.Rotate(ctx, checked)
+			}
+
+			if tt.wantMessage != "" {
+				if status.Code(err) != codes.Unauthenticated || status.Convert(err).Message() != tt.wantMessage {
+					t.Fatalf("Check() and Rotate() error = %v, want Unauthenticated %q", err, tt.wantMessage)
+				}
+				if revoked := len(repo.revokedFamilies) > 0; revoked != tt.wantRevoked {
+					t.Errorf("family revoked = %v, want %v", revoked, tt.wantRevoked)
+				}
+				if len(repo.tokens) > 1 {
+					t.Errorf("Rotate() issued a token after failing")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Check() and Rotate() error = %v", err)
+			}
+			if checked.Id != current.Id || checked.Subject != subject {
+				t.Errorf("Check() returned token %d of %q, want %d of %q", checked.Id, checked.Subject, current.Id, subject)
+			}
+			if current.UsedAt == nil {
+				t.Errorf("presented token was not marked as used")
+			}
+			if len(repo.tokens) != 2 {
+				t.Fatalf("stored tokens = %d, want 2", len(repo.tokens))
+			}
+			issued := repo.tokens[1]
+			if issued.FamilyId != familyId || issued.TokenHash != utils.HashToken(next) || issued.Subject != subject || issued.Role != current.Role {
+				t.Errorf("issued token = %+v, want family %q for %q", issued, familyId, subject)
+			}
+
+			if _, err := svc.Check(ctx, presented); status.Code(err) != codes.Unauthenticated {
+				t.Errorf("second Check() error = %v, want Unauthenticated", err)
+			}
+			if len(repo.revokedFamilies) != 1 || repo.revokedFamilies[0] != familyId {
+				t.Errorf("revoked families after reuse = %v, want [%s]", repo.revokedFamilies, familyId)
+			}
+			if _, err := svc.Check(ctx, next); status.Code(err) != codes.Unauthenticated {
+				t.Errorf("Check() of the replacement after reuse error = %v, want Unauthenticated", err)
+			}
+		})
+	}
+}
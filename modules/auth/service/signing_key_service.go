@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/modules/auth/entity"
+	"tracerstudy-auth-service/modules/auth/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type SigningKeyService struct {
+	cfg                  config.Config
+	signingKeyRepository repository.SigningKeyRepositoryUseCase
+	jwtManager           *commonJwt.JWT
+	keyCipher            *commonJwt.KeyCipher
+}
+
+type SigningKeyServiceUseCase interface {
+	FindAll(ctx context.Context) ([]*entity.SigningKey, error)
+	Status(key *entity.SigningKey) string
+	Create(ctx context.Context, alg string, activatesAt *time.Time) (*entity.SigningKey, error)
+	Promote(ctx context.Context, kid string) (*entity.SigningKey, error)
+	Retire(ctx context.Context, kid string) (*entity.SigningKey, error)
+}
+
+// NewSigningKeyService stores generated private keys sealed with
+// keyCipher. Without one, only the bootstrap key can be used.
+func NewSigningKeyService(cfg config.Config, signingKeyRepository repository.SigningKeyRepositoryUseCase, jwtManager *commonJwt.JWT, keyCipher *commonJwt.KeyCipher) *SigningKeyService {
+	return &SigningKeyService{
+		cfg:                  cfg,
+		signingKeyRepository: signingKeyRepository,
+		jwtManager:           jwtManager,
+		keyCipher:            keyCipher,
+	}
+}
+
+// Bootstrap records the configured key in the shared key set, active right
+// away when the key set is empty, seals private keys stored before they
+// were encrypted, then loads the key set.
+func (svc *SigningKeyService) Bootstrap(ctx context.Context) error {
+	keys, err := svc.signingKeyRepository.FindAll(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [SigningKeyService - Bootstrap] Error while find all signing keys:", parseError.Message)
+		return err
+	}
+
+	bootstrapKey := svc.jwtManager.BootstrapKey()
+
+	known := false
+	for _, k := range keys {
+		if k.Kid == bootstrapKey.Kid {
+			known = true
+			break
+		}
+	}
+
+	if !known {
+		var activatesAt *time.Time
+		if len(keys) == 0 {
+			now := time.Now()
+			activatesAt = &now
+		}
+
+		key := entity.NewSigningKey(bootstrapKey.Kid, bootstrapKey.Method.Alg(), "", activatesAt)
+		if _, err := svc.signingKeyRepository.Create(ctx, key); err != nil {
+			// another replica may have recorded it concurrently
+			log.Println("WARNING: [SigningKeyService - Bootstrap] Error while recording bootstrap key:", err)
+		}
+	}
+
+	if err := svc.sealStoredKeys(ctx, keys); err != nil {
+		return err
+	}
+
+	return svc.Reload(ctx)
+}
+
+func (svc *SigningKeyService) sealStoredKeys(ctx context.Context, keys []*entity.SigningKey) error {
+	for _, k := range keys {
+		if k.PrivateKey == "" || commonJwt.IsSealedKey(k.PrivateKey) {
+			continue
+		}
+
+		if svc.keyCipher == nil {
+			log.Println("WARNING: [SigningKeyService - Bootstrap] Signing key is stored unencrypted, configure a key encryption key:", k.Kid)
+			continue
+		}
+
+		sealed, err := svc.keyCipher.Seal(k.Kid, []byte(k.PrivateKey))
+		if err != nil {
+			log.Println("ERROR: [SigningKeyService - Bootstrap] Error while sealing signing key", k.Kid, ":", err)
+			return err
+		}
+
+		if _, err := svc.signingKeyRepository.Update(ctx, k, map[string]interface{}{
+			"private_key": sealed,
+		}); err != nil {
+			parseError := errors.ParseError(err)
+			log.Println("ERROR: [SigningKeyService - Bootstrap] Error while update signing key:", parseError.Message)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reload installs the persisted key set into the JWT manager.
+func (svc *SigningKeyService) Reload(ctx context.Context) error {
+	keys, err := svc.signingKeyRepository.FindAll(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [SigningKeyService - Reload] Error while find all signing keys:", parseError.Message)
+		return err
+	}
+
+	bootstrapKey := svc.jwtManager.BootstrapKey()
+
+	var managedKeys []*commonJwt.ManagedKey
+	for _, k := range keys {
+		signingKey := bootstrapKey
+		if k.PrivateKey != "" {
+			signingKey, err = svc.parseStoredKey(k)
+			if err != nil {
+				log.Println("ERROR: [SigningKeyService - Reload] Error while parsing signing key", k.Kid, ":", err)
+				continue
+			}
+		} else if k.Kid != bootstrapKey.Kid {
+			log.Println("WARNING: [SigningKeyService - Reload] No key material for signing key", k.Kid)
+			continue
+		}
+
+		managedKeys = append(managedKeys, &commonJwt.ManagedKey{
+			SigningKey:  signingKey,
+			ActivatesAt: k.ActivatesAt,
+			RetiredAt:   k.RetiredAt,
+		})
+	}
+
+	svc.jwtManager.SetKeys(managedKeys)
+	return nil
+}
+
+func (svc *SigningKeyService) parseStoredKey(k *entity.SigningKey) (*commonJwt.SigningKey, error) {
+	privateKeyPEM := []byte(k.PrivateKey)
+	if commonJwt.IsSealedKey(k.PrivateKey) {
+		if svc.keyCipher == nil {
+			return nil, fmt.Errorf("no key encryption key to open it")
+		}
+
+		var err error
+		if privateKeyPEM, err = svc.keyCipher.Open(k.Kid, k.PrivateKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return commonJwt.ParseSigningKey(k.Alg, k.Kid, privateKeyPEM)
+}
+
+// Watch reloads the key set every interval so that replicas pick up
+// changes made through another replica.
+func (svc *SigningKeyService) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := svc.Reload(ctx); err != nil {
+				log.Println("ERROR: [SigningKeyService - Watch] Error while reloading signing keys:", err)
+			}
+		}
+	}
+}
+
+func (svc *SigningKeyService) FindAll(ctx context.Context) ([]*entity.SigningKey, error) {
+	res, err := svc.signingKeyRepository.FindAll(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [SigningKeyService - FindAll] Error while find all signing keys:", parseError.Message)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (svc *SigningKeyService) Status(key *entity.SigningKey) string {
+	now := time.Now()
+
+	switch {
+	case key.RetiredAt != nil:
+		return entity.SigningKeyStatusRetired
+	case key.ActivatesAt == nil:
+		return entity.SigningKeyStatusStandby
+	case key.ActivatesAt.After(now):
+		return entity.SigningKeyStatusScheduled
+	case svc.jwtManager.ActiveKey(now).Kid == key.Kid:
+		return entity.SigningKeyStatusActive
+	default:
+		return entity.SigningKeyStatusStandby
+	}
+}
+
+// Create generates a new key. It starts signing at activatesAt, or stays on
+// standby until promoted when activatesAt is nil.
+func (svc *SigningKeyService) Create(ctx context.Context, alg string, activatesAt *time.Time) (*entity.SigningKey, error) {
+	if svc.keyCipher == nil {
+		log.Println("WARNING: [SigningKeyService - Create] No key encryption key configured")
+		return nil, status.Errorf(codes.FailedPrecondition, "signing keys can only be stored with a key encryption key configured")
+	}
+
+	if alg == "" {
+		alg = svc.cfg.JWT.SigningAlg
+	}
+
+	signingKey, privateKeyPEM, err := commonJwt.GenerateSigningKey(alg)
+	if err != nil {
+		log.Println("ERROR: [SigningKeyService - Create] Error while generating signing key:", err)
+		return nil, status.Errorf(codes.InvalidArgument, "failed to generate signing key: %v", err)
+	}
+
+	sealed, err := svc.keyCipher.Seal(signingKey.Kid, privateKeyPEM)
+	if err != nil {
+		log.Println("ERROR: [SigningKeyService - Create] Error while sealing signing key:", err)
+		return nil, status.Errorf(codes.Internal, "failed to seal signing key")
+	}
+
+	key := entity.NewSigningKey(signingKey.Kid, alg, sealed, activatesAt)
+
+	res, err := svc.signingKeyRepository.Create(ctx, key)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [SigningKeyService - Create] Error while create signing key:", parseError.Message)
+		return nil, err
+	}
+
+	return res, svc.Reload(ctx)
+}
+
+// Promote makes the key the active signing key immediately. The previously
+// active key keeps verifying until it is retired.
+func (svc *SigningKeyService) Promote(ctx context.Context, kid string) (*entity.SigningKey, error) {
+	key, err := svc.signingKeyRepository.FindByKid(ctx, kid)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [SigningKeyService - Promote] Error while find signing key by kid:", parseError.Message)
+		return nil, err
+	}
+
+	if key.RetiredAt != nil {
+		log.Println("WARNING: [SigningKeyService - Promote] Signing key has been retired:", kid)
+		return nil, status.Errorf(codes.FailedPrecondition, "signing key %s has been retired", kid)
+	}
+
+	res, err := svc.signingKeyRepository.Update(ctx, key, map[string]interface{}{
+		"activates_at": time.Now(),
+	})
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [SigningKeyService - Promote] Error while update signing key:", parseError.Message)
+		return nil, err
+	}
+
+	return res, svc.Reload(ctx)
+}
+
+// Retire stops the key from signing. Tokens it signed keep verifying until
+// they expire.
+func (svc *SigningKeyService) Retire(ctx context.Context, kid string) (*entity.SigningKey, error) {
+	keys, err := svc.signingKeyRepository.FindAll(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [SigningKeyService - Retire] Error while find all signing keys:", parseError.Message)
+		return nil, err
+	}
+
+	now := time.Now()
+
+	var key *entity.SigningKey
+	remaining := 0
+	for _, k := range keys {
+		if k.Kid == kid {
+			key = k
+			continue
+		}
+		if k.RetiredAt == nil && k.ActivatesAt != nil && !k.ActivatesAt.After(now) {
+			remaining++
+		}
+	}
+
+	if key == nil {
+		log.Println("WARNING: [SigningKeyService - Retire] Signing key not found:", kid)
+		return nil, status.Errorf(codes.NotFound, "signing key %s not found", kid)
+	}
+
+	if key.RetiredAt != nil {
+		return key, nil
+	}
+
+	if remaining == 0 {
+		log.Println("WARNING: [SigningKeyService - Retire] Refusing to retire the last active signing key:", kid)
+		return nil, status.Errorf(codes.FailedPrecondition, "promote another signing key before retiring %s", kid)
+	}
+
+	res, err := svc.signingKeyRepository.Update(ctx, key, map[string]interface{}{
+		"retired_at": now,
+	})
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [SigningKeyService - Retire] Error while update signing key:", parseError.Message)
+		return nil, err
+	}
+
+	return res, svc.Reload(ctx)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/mailer"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/auth/client"
+	"tracerstudy-auth-service/modules/auth/entity"
+	"tracerstudy-auth-service/modules/auth/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	userStudyLoginTokenSize   = 32
+	userStudyGrantIdSize      = 24
+	userStudyLoginMailTimeout = 30 * time.Second
+)
+
+type UserStudyLoginService struct {
+	cfg                      config.Config
+	userStudyLoginRepository repository.UserStudyLoginRepositoryUseCase
+	userStudyGrantRepository repository.UserStudyGrantRepositoryUseCase
+	pktsSvc                  client.PktsServiceClient
+	mailer                   mailer.Mailer
+}
+
+type UserStudyLoginServiceUseCase interface {
+	Request(ctx context.Context, email string) error
+	Consume(ctx context.Context, token string) (string, error)
+	FetchNims(ctx context.Context, email string) ([]string, error)
+	ClaimScopes(ctx context.Context, email string, nims []string) (commonJwt.Scopes, error)
+	AuthorizedNims(ctx context.Context, email string, scopes commonJwt.Scopes) ([]string, error)
+}
+
+func NewUserStudyLoginService(cfg config.Config, userStudyLoginRepository repository.UserStudyLoginRepositoryUseCase, userStudyGrantRepository repository.UserStudyGrantRepositoryUseCase, pktsService client.PktsServiceClient, mailer mailer.Mailer) *UserStudyLoginService {
+	return &UserStudyLoginService{
+		cfg:                      cfg,
+		userStudyLoginRepository: userStudyLoginRepository,
+		userStudyGrantRepository: userStudyGrantRepository,
+		pktsSvc:                  pktsService,
+		mailer:                   mailer,
+	}
+}
+
+// Request mails a login link to email if PKTS knows it as the email of an
+// alumni's employer and it was not sent one within the resend interval.
+func (svc *UserStudyLoginService) Request(ctx context.Context, email string) error {
+	nims, err := svc.FetchNims(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if len(nims) == 0 {
+		log.Println("INFO: [UserStudyLoginService - Request] Login link requested for unknown email")
+		return nil
+	}
+
+	latest, err := svc.userStudyLoginRepository.FindLatestByEmail(ctx, email)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code != codes.NotFound {
+			log.Println("ERROR: [UserStudyLoginService - Request] Error while find latest login token:", parseError.Message)
+			return err
+		}
+	} else if time.Since(latest.CreatedAt) < svc.cfg.UserStudy.ResendInterval {
+		log.Println("INFO: [UserStudyLoginService - Request] Login link requested too soon")
+		return nil
+	}
+
+	if err := svc.userStudyLoginRepository.InvalidateEmail(ctx, email); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserStudyLoginService - Request] Error while invalidating login tokens:", parseError.Message)
+		return err
+	}
+
+	token, err := utils.GenerateRandomToken(userStudyLoginTokenSize)
+	if err != nil {
+		log.Println("ERROR: [UserStudyLoginService - Request] Error while generating login token:", err)
+		return status.Errorf(codes.Internal, "failed to generate login token")
+	}
+
+	loginToken := entity.NewUserStudyLoginToken(email, utils.HashToken(token), time.Now().Add(svc.cfg.UserStudy.LinkDuration))
+	if _, err := svc.userStudyLoginRepository.Create(ctx, loginToken); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserStudyLoginService - Request] Error while create login token:", parseError.Message)
+		return err
+	}
+
+	msg := &mailer.Message{
+		To:      email,
+		Subject: "Your Tracer Study login link",
+		Body:    svc.loginMailBody(token),
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), userStudyLoginMailTimeout)
+		defer cancel()
+
+		if err := svc.mailer.Send(ctx, msg); err != nil {
+			log.Println("ERROR: [UserStudyLoginService - Request] Error while sending login mail:", err)
+		}
+	}()
+
+	return nil
+}
+
+// Consume uses up a token from Request and returns the email it was sent
+// to.
+func (svc *UserStudyLoginService) Consume(ctx context.Context, token string) (string, error) {
+	invalid := status.Errorf(codes.InvalidArgument, "login link is invalid or has expired")
+
+	loginToken, err := svc.userStudyLoginRepository.FindByHash(ctx, utils.HashToken(token))
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			return "", invalid
+		}
+		log.Println("ERROR: [UserStudyLoginService - Consume] Error while find login token:", parseError.Message)
+		return "", err
+	}
+
+	if loginToken.UsedAt != nil || time.Now().After(loginToken.ExpiresAt) {
+		log.Println("WARNING: [UserStudyLoginService - Consume] Used or expired login token", loginToken.Id)
+		return "", invalid
+	}
+
+	marked, err := svc.userStudyLoginRepository.MarkUsed(ctx, loginToken.Id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserStudyLoginService - Consume] Error while marking login token as used:", parseError.Message)
+		return "", err
+	}
+
+	if !marked {
+		log.Println("WARNING: [UserStudyLoginService - Consume] Login token used concurrently", loginToken.Id)
+		return "", invalid
+	}
+
+	return loginToken.Email, nil
+}
+
+// FetchNims asks PKTS which alumni list email as their employer's email.
+func (svc *UserStudyLoginService) FetchNims(ctx context.Context, email string) ([]string, error) {
+	atasan, err := svc.pktsSvc.GetNimByDataAtasan(ctx, "", email, "")
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			return nil, nil
+		}
+		log.Println("ERROR: [UserStudyLoginService - FetchNims] Error while fetching employer:", parseError.Message)
+		return nil, err
+	}
+
+	return atasan.GetNims(), nil
+}
+
+// ClaimScopes returns the scopes of an employer's access token. Up to the
+// configured number of NIMs are embedded as they are; larger sets are stored
+// as a grant for the lifetime of the token and referenced by id.
+func (svc *UserStudyLoginService) ClaimScopes(ctx context.Context, email string, nims []string) (commonJwt.Scopes, error) {
+	if len(nims) <= svc.cfg.UserStudy.MaxTokenNims {
+		return commonJwt.Scopes{Nims: nims}, nil
+	}
+
+	grantId, err := utils.GenerateRandomToken(userStudyGrantIdSize)
+	if err != nil {
+		log.Println("ERROR: [UserStudyLoginService - ClaimScopes] Error while generating grant id:", err)
+		return commonJwt.Scopes{}, status.Errorf(codes.Internal, "failed to generate grant id")
+	}
+
+	now := time.Now()
+	grant := entity.NewUserStudyGrant(grantId, email, nims, now.Add(svc.cfg.JWT.TokenDuration+svc.cfg.JWT.Leeway))
+	if _, err := svc.userStudyGrantRepository.Create(ctx, grant); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserStudyLoginService - ClaimScopes] Error while create grant:", parseError.Message)
+		return commonJwt.Scopes{}, err
+	}
+
+	if err := svc.userStudyGrantRepository.DeleteExpired(ctx, now); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("WARNING: [UserStudyLoginService - ClaimScopes] Error while deleting expired grants:", parseError.Message)
+	}
+
+	return commonJwt.Scopes{NimGrant: grantId}, nil
+}
+
+// AuthorizedNims returns the alumni an employer's token allows access to,
+// looking up the grant the token references if there is one.
+func (svc *UserStudyLoginService) AuthorizedNims(ctx context.Context, email string, scopes commonJwt.Scopes) ([]string, error) {
+	if scopes.NimGrant == "" {
+		return scopes.Nims, nil
+	}
+
+	grant, err := svc.userStudyGrantRepository.FindById(ctx, scopes.NimGrant)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			return nil, status.Errorf(codes.NotFound, "alumni grant not found")
+		}
+		log.Println("ERROR: [UserStudyLoginService - AuthorizedNims] Error while find grant:", parseError.Message)
+		return nil, err
+	}
+
+	if !strings.EqualFold(grant.Email, email) {
+		log.Println("WARNING: [UserStudyLoginService - AuthorizedNims] Grant", grant.Id, "does not belong to the token subject")
+		return nil, status.Errorf(codes.PermissionDenied, "alumni grant does not belong to the token subject")
+	}
+
+	return grant.Nims, nil
+}
+
+func (svc *UserStudyLoginService) loginMailBody(token string) string {
+	return fmt.Sprintf(
+		"Hello,\n\nUse the link below within %s to log in to Tracer Study and fill in the alumni user survey. The link works only once:\n\n%s\n\nIf you did not ask for this, you can ignore this email.\n",
+		svc.cfg.UserStudy.LinkDuration, mailer.Link(svc.cfg.UserStudy.LoginURL, token),
+	)
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/mailer"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/auth/client"
+	"tracerstudy-auth-service/modules/auth/entity"
+	"tracerstudy-auth-service/modules/auth/repository"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeUserStudyLoginRepository struct {
+	repository.UserStudyLoginRepositoryUseCase
+	tokens      []*entity.UserStudyLoginToken
+	invalidated int
+}
+
+func (r *fakeUserStudyLoginRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.UserStudyLoginToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "login token not found")
+}
+
+func (r *fakeUserStudyLoginRepository) FindLatestByEmail(ctx context.Context, email string) (*entity.UserStudyLoginToken, error) {
+	var latest *entity.UserStudyLoginToken
+	for _, t := range r.tokens {
+		if t.Email == email && (latest == nil || t.CreatedAt.After(latest.CreatedAt)) {
+			latest = t
+		}
+	}
+	if latest == nil {
+		return nil, status.Errorf(codes.NotFound, "login token not found")
+	}
+	return latest, nil
+}
+
+func (r *fakeUserStudyLoginRepository) Create(ctx context.Context, req *entity.UserStudyLoginToken) (*entity.UserStudyLoginToken, error) {
+	req.Id = uint64(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, req)
+	return req, nil
+}
+
+func (r *fakeUserStudyLoginRepository) MarkUsed(ctx context.Context, id uint64) (bool, error) {
+	for _, t := range r.tokens {
+		if t.Id == id && t.UsedAt == nil {
+			now := time.Now()
+			t.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeUserStudyLoginRepository) InvalidateEmail(ctx context.Context, email string) error {
+	r.invalidated++
+	return nil
+}
+
+type fakeUserStudyGrantRepository struct {
+	repository.UserStudyGrantRepositoryUseCase
+	grants map[string]*entity.UserStudyGrant
+}
+
+func (r *fakeUserStudyGrantRepository) FindById(ctx context.Context, id string) (*entity.UserStudyGrant, error) {
+	grant, ok := r.grants[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "grant not found")
+	}
+	return grant, nil
+}
+
+func (r *fakeUserStudyGrantRepository) Create(ctx context.Context, req *entity.UserStudyGrant) (*entity.UserStudyGrant, error) {
+	r.grants[req.Id] = req
+	return req, nil
+}
+
+func (r *fakeUserStudyGrantRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+// fakePktsClient knows the alumni of each employer email.
+type fakePktsClient struct {
+	pb.PKTSServiceClient
+	employers map[string][]string
+	err       error
+}
+
+func (c *fakePktsClient) GetNimByDataAtasan(ctx context.Context, in *pb.GetNimByDataAtasanRequest, opts ...grpc.CallOption) (*pb.GetNimByDataAtasanResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	nims, ok := c.employers[in.GetEmailAtasan()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "atasan not found")
+	}
+	return &pb.GetNimByDataAtasanResponse{Nims: nims}, nil
+}
+
+func TestUserStudyLoginServiceRequest(t *testing.T) {
+	const email = "hrd@example.com"
+
+	tests := []struct {
+		name        string
+		email       string
+		previousAge time.Duration
+		pktsErr     error
+		wantCode    codes.Code
+		wantToken   bool
+	}{
+		{
+			name:      "first request",
+			email:     email,
+			wantToken: true,
+		},
+		{
+			name:        "request within the resend interval",
+			email:       email,
+			previousAge: 30 * time.Second,
+		},
+		{
+			name:        "request after the resend interval",
+			email:       email,
+			previousAge: 2 * time.Minute,
+			wantToken:   true,
+		},
+		{
+			name:  "email unknown to PKTS",
+			email: "siti@example.com",
+		},
+		{
+			name:     "PKTS unavailable",
+			email:    email,
+			pktsErr:  status.Errorf(codes.Unavailable, "pkts is down"),
+			wantCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserStudyLoginRepository{}
+			if tt.previousAge != 0 {
+				repo.tokens = append(repo.tokens, &entity.UserStudyLoginToken{Id: 1, Email: email, CreatedAt: time.Now().Add(-tt.previousAge)})
+			}
+			pkts := client.PktsServiceClient{Client: &fakePktsClient{
+				employers: map[string][]string{email: {"1911521001"}},
+				err:       tt.pktsErr,
+			}}
+			mail := &fakeMailer{sent: make(chan *mailer.Message, 1)}
+
+			cfg := config.Config{UserStudy: config.UserStudyLogin{
+				LoginURL:       "https://tracer.example.com/user-study",
+				LinkDuration:   15 * time.Minute,
+				ResendInterval: time.Minute,
+			}}
+			svc := NewUserStudyLoginService(cfg, repo, nil, pkts, mail)
+
+			before := len(repo.tokens)
+			err := svc.Request(context.Background(), tt.email)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("Request() error = %v, want %v", err, tt.wantCode)
+			}
+
+			if created := len(repo.tokens) > before; created != tt.wantToken {
+				t.Fatalf("token created = %v, want %v", created, tt.wantToken)
+			}
+			if invalidated := repo.invalidated > 0; invalidated != tt.wantToken {
+				t.Errorf("previous tokens invalidated = %v, want %v", invalidated, tt.wantToken)
+			}
+
+			if !tt.wantToken {
+				select {
+				case msg := <-mail.sent:
+					t.Errorf("mail sent to %s, want none", msg.To)
+				case <-time.After(50 * time.Millisecond):
+				}
+				return
+			}
+
+			select {
+			case msg := <-mail.sent:
+				if msg.To != email || !strings.Contains(msg.Body, cfg.UserStudy.LoginURL+"?token=") {
+					t.Errorf("mail = %+v, want a login link to %s", msg, email)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("no mail sent")
+			}
+		})
+	}
+}
+
+func TestUserStudyLoginServiceConsume(t *testing.T) {
+	const (
+		token = "login-token"
+		email = "hrd@example.com"
+	)
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		modify   func(*entity.UserStudyLoginToken)
+		token    string
+		wantCode codes.Code
+	}{
+		{
+			name:  "valid token",
+			token: token,
+		},
+		{
+			name:     "unknown token",
+			token:    "other-token",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "used token",
+			modify:   func(l *entity.UserStudyLoginToken) { l.UsedAt = &past },
+			token:    token,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "expired token",
+			modify:   func(l *entity.UserStudyLoginToken) { l.ExpiresAt = past },
+			token:    token,
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loginToken := entity.NewUserStudyLoginToken(email, utils.HashToken(token), time.Now().Add(time.Hour))
+			loginToken.Id = 1
+			if tt.modify != nil {
+				tt.modify(loginToken)
+			}
+			repo := &fakeUserStudyLoginRepository{tokens: []*entity.UserStudyLoginToken{loginToken}}
+			svc := NewUserStudyLoginService(config.Config{}, repo, nil, client.PktsServiceClient{}, &fakeMailer{})
+
+			got, err := svc.Consume(context.Background(), tt.token)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("Consume() error = %v, want %v", err, tt.wantCode)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+			if got != email {
+				t.Errorf("Consume() = %q, want %q", got, email)
+			}
+
+			// the link works only once
+			if _, err := svc.Consume(context.Background(), tt.token); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("second Consume() error = %v, want %v", err, codes.InvalidArgument)
+			}
+		})
+	}
+}
+
+func TestUserStudyLoginServiceClaimScopes(t *testing.T) {
+	const email = "HRD@example.com"
+	few := []string{"1911521001", "1911521002"}
+	many := []string{"1911521001", "1911521002", "1911521003"}
+
+	tests := []struct {
+		name       string
+		nims       []string
+		tokenEmail string
+		wantGrant  bool
+		wantCode   codes.Code
+		wantNims   []string
+	}{
+		{
+			name:       "embedded nims",
+			nims:       few,
+			tokenEmail: email,
+			wantNims:   few,
+		},
+		{
+			name:       "stored grant",
+			nims:       many,
+			tokenEmail: "hrd@example.com",
+			wantGrant:  true,
+			wantNims:   many,
+		},
+		{
+			name:       "grant of another employer",
+			nims:       many,
+			tokenEmail: "siti@example.com",
+			wantGrant:  true,
+			wantCode:   codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants := &fakeUserStudyGrantRepository{grants: map[string]*entity.UserStudyGrant{}}
+			cfg := config.Config{UserStudy: config.UserStudyLogin{MaxTokenNims: 2}}
+			svc := NewUserStudyLoginService(cfg, nil, grants, client.PktsServiceClient{}, &fakeMailer{})
+
+			scopes, err := svc.ClaimScopes(context.Background(), email, tt.nims)
+			if err != nil {
+				t.Fatalf("ClaimScopes() error = %v", err)
+			}
+			if granted := scopes.NimGrant != ""; granted != tt.wantGrant || granted == (len(scopes.Nims) > 0) {
+				t.Fatalf("ClaimScopes() = %+v, want a grant %v", scopes, tt.wantGrant)
+			}
+
+			got, err := svc.AuthorizedNims(context.Background(), tt.tokenEmail, scopes)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("AuthorizedNims() error = %v, want %v", err, tt.wantCode)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantNims, ",") {
+				t.Errorf("AuthorizedNims() = %v, want %v", got, tt.wantNims)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package authorization
+
+import (
+	"tracerstudy-auth-service/common/authorization"
+	"tracerstudy-auth-service/common/config"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/modules/authorization/builder"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc"
+)
+
+func InitGrpc(server *grpc.Server, cfg config.Config, jwtManager *commonJwt.JWT, policy authorization.Policy) {
+	authorizationHandler := builder.BuildAuthorizationHandler(cfg, jwtManager, policy)
+	pb.RegisterAuthorizationServiceServer(server, authorizationHandler)
+}
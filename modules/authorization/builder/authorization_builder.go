@@ -0,0 +1,14 @@
+package builder
+
+import (
+	"tracerstudy-auth-service/common/authorization"
+	"tracerstudy-auth-service/common/config"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/modules/authorization/handler"
+)
+
+func BuildAuthorizationHandler(cfg config.Config, jwtManager *commonJwt.JWT, policy authorization.Policy) *handler.AuthorizationHandler {
+	serviceClients := authorization.NewServiceClients(cfg.ServiceAuth.Clients)
+
+	return handler.NewAuthorizationHandler(cfg, jwtManager, policy, serviceClients)
+}
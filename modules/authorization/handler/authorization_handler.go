@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"tracerstudy-auth-service/common/authorization"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxBatchChecks = 100
+
+	reasonAllowed    = "allowed"
+	reasonNoPolicy   = "no policy defined for resource and action"
+	reasonRoleDenied = "role is not permitted"
+	reasonOutOfScope = "resource is outside of the caller's prodi or faculty"
+)
+
+type AuthorizationHandler struct {
+	pb.UnimplementedAuthorizationServiceServer
+	config         config.Config
+	jwtManager     *commonJwt.JWT
+	policy         authorization.Policy
+	serviceClients *authorization.ServiceClients
+}
+
+func NewAuthorizationHandler(
+	config config.Config,
+	jwtManager *commonJwt.JWT,
+	policy authorization.Policy,
+	serviceClients *authorization.ServiceClients,
+) *AuthorizationHandler {
+	return &AuthorizationHandler{
+		config:         config,
+		jwtManager:     jwtManager,
+		policy:         policy,
+		serviceClients: serviceClients,
+	}
+}
+
+func (ah *AuthorizationHandler) CheckPermission(ctx context.Context, req *pb.CheckPermissionRequest) (*pb.CheckPermissionResponse, error) {
+	claims, err := ah.authenticate(ctx, req.GetToken())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthorizationHandler - CheckPermission] Authentication failed:", parseError.Message)
+		return &pb.CheckPermissionResponse{
+			Code:    uint32(http.StatusUnauthorized),
+			Message: parseError.Message,
+		}, status.Errorf(codes.Unauthenticated, parseError.Message)
+	}
+
+	check := &pb.PermissionCheck{
+		Resource:  req.GetResource(),
+		Action:    req.GetAction(),
+		Kodeprodi: req.GetKodeprodi(),
+		Kodefak:   req.GetKodefak(),
+	}
+
+	decision, err := ah.decide(ctx, claims, check)
+	if err != nil {
+		log.Println("ERROR: [AuthorizationHandler - CheckPermission] Error while evaluating policy:", err)
+		return &pb.CheckPermissionResponse{
+			Code:    uint32(http.StatusInternalServerError),
+			Message: "error while evaluating policy",
+		}, status.Errorf(codes.Internal, "error while evaluating policy")
+	}
+
+	return &pb.CheckPermissionResponse{
+		Code:      uint32(http.StatusOK),
+		Message:   "check permission success",
+		Subject:   claims.Subject,
+		Role:      claims.Role,
+		Data:      decision,
+		Kodeprodi: claims.Kodeprodi,
+		Kodefak:   claims.Kodefak,
+	}, nil
+}
+
+func (ah *AuthorizationHandler) BatchCheckPermission(ctx context.Context, req *pb.BatchCheckPermissionRequest) (*pb.BatchCheckPermissionResponse, error) {
+	if len(req.GetChecks()) > maxBatchChecks {
+		log.Println("WARNING: [AuthorizationHandler - BatchCheckPermission] Too many checks:", len(req.GetChecks()))
+		return &pb.BatchCheckPermissionResponse{
+			Code:    uint32(http.StatusBadRequest),
+			Message: "too many permission checks",
+		}, status.Errorf(codes.InvalidArgument, "at most %d permission checks are allowed", maxBatchChecks)
+	}
+
+	claims, err := ah.authenticate(ctx, req.GetToken())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AuthorizationHandler - BatchCheckPermission] Authentication failed:", parseError.Message)
+		return &pb.BatchCheckPermissionResponse{
+			Code:    uint32(http.StatusUnauthorized),
+			Message: parseError.Message,
+		}, status.Errorf(codes.Unauthenticated, parseError.Message)
+	}
+
+	var decisions []*pb.PermissionDecision
+	for _, check := range req.GetChecks() {
+		decision, err := ah.decide(ctx, claims, check)
+		if err != nil {
+			log.Println("ERROR: [AuthorizationHandler - BatchCheckPermission] Error while evaluating policy:", err)
+			return &pb.BatchCheckPermissionResponse{
+				Code:    uint32(http.StatusInternalServerError),
+				Message: "error while evaluating policy",
+			}, status.Errorf(codes.Internal, "error while evaluating policy")
+		}
+		decisions = append(decisions, decision)
+	}
+
+	return &pb.BatchCheckPermissionResponse{
+		Code:      uint32(http.StatusOK),
+		Message:   "batch check permission success",
+		Subject:   claims.Subject,
+		Role:      claims.Role,
+		Data:      decisions,
+		Kodeprodi: claims.Kodeprodi,
+		Kodefak:   claims.Kodefak,
+	}, nil
+}
+
+// authenticate checks the calling service's credentials, then the subject
+// token the decision is requested for.
+func (ah *AuthorizationHandler) authenticate(ctx context.Context, token string) (*commonJwt.CustomClaims, error) {
+	if _, err := ah.serviceClients.Authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	claims, err := ah.jwtManager.Verify(ctx, token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "subject token is invalid: %v", err)
+	}
+
+	return claims, nil
+}
+
+// decide evaluates a single check. Resources without a rule are denied,
+// since callers asking us have no public fallback of their own. Checks that
+// name a prodi or faculty are also limited to the caller's scopes.
+func (ah *AuthorizationHandler) decide(ctx context.Context, claims *commonJwt.CustomClaims, check *pb.PermissionCheck) (*pb.PermissionDecision, error) {
+	resource, action := check.GetResource(), check.GetAction()
+	decision := &pb.PermissionDecision{
+		Resource: resource,
+		Action:   action,
+	}
+
+	governed, err := ah.policy.Governs(ctx, resource, action)
+	if err != nil {
+		return nil, err
+	}
+
+	if !governed {
+		decision.Reason = reasonNoPolicy
+		return decision, nil
+	}
+
+	allowed, err := ah.policy.Allows(ctx, resource, action, claims.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowed {
+		decision.Reason = reasonRoleDenied
+		return decision, nil
+	}
+
+	if !authorization.InScope(claims.Role, claims.Scopes, check.GetKodeprodi(), check.GetKodefak()) {
+		decision.Reason = reasonOutOfScope
+		return decision, nil
+	}
+
+	decision.Allowed = true
+	decision.Reason = reasonAllowed
+
+	return decision, nil
+}
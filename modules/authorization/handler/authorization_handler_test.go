@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/authorization"
+	"tracerstudy-auth-service/common/config"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testResource = "tracer_study_grpc.ReportService"
+
+// fakePolicy lets Admins and Admin Prodi users view reports.
+type fakePolicy struct{}
+
+func (fakePolicy) Governs(ctx context.Context, resource, action string) (bool, error) {
+	return resource == testResource && action == "ViewReport", nil
+}
+
+func (fakePolicy) Allows(ctx context.Context, resource, action string, role uint32) (bool, error) {
+	return role == authorization.RoleAdmin || role == authorization.RoleAdminProdi, nil
+}
+
+func newTestAuthorizationHandler(t *testing.T) (*AuthorizationHandler, *commonJwt.JWT) {
+	t.Helper()
+
+	jwtManager := commonJwt.NewJWT(config.JWTConfig{
+		Issuer:        "issuer",
+		Audience:      "audience",
+		TokenDuration: time.Minute,
+	}, commonJwt.NewHMACSigningKey("", []byte("secret")), nil)
+
+	return NewAuthorizationHandler(
+		config.Config{},
+		jwtManager,
+		fakePolicy{},
+		authorization.NewServiceClients("tracer:s3cret"),
+	), jwtManager
+}
+
+func serviceContext(id, secret string) context.Context {
+	credentials := base64.StdEncoding.EncodeToString([]byte(id + ":" + secret))
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic "+credentials))
+}
+
+func TestAuthorizationHandlerCheckPermission(t *testing.T) {
+	ah, jwtManager := newTestAuthorizationHandler(t)
+
+	token := func(role uint32, scopes commonJwt.Scopes) string {
+		token, err := jwtManager.GenerateToken("user:1", role, scopes)
+		if err != nil {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		token       string
+		req         *pb.CheckPermissionRequest
+		wantCode    codes.Code
+		wantAllowed bool
+		wantReason  string
+	}{
+		{
+			name:        "allowed role",
+			ctx:         serviceContext("tracer", "s3cret"),
+			token:       token(authorization.RoleAdmin, commonJwt.Scopes{}),
+			req:         &pb.CheckPermissionRequest{Resource: testResource, Action: "ViewReport"},
+			wantAllowed: true,
+			wantReason:  reasonAllowed,
+		},
+		{
+			name:       "denied role",
+			ctx:        serviceContext("tracer", "s3cret"),
+			token:      token(authorization.RoleAlumni, commonJwt.Scopes{}),
+			req:        &pb.CheckPermissionRequest{Resource: testResource, Action: "ViewReport"},
+			wantReason: reasonRoleDenied,
+		},
+		{
+			name:       "no policy",
+			ctx:        serviceContext("tracer", "s3cret"),
+			token:      token(authorization.RoleAdmin, commonJwt.Scopes{}),
+			req:        &pb.CheckPermissionRequest{Resource: testResource, Action: "DeleteReport"},
+			wantReason: reasonNoPolicy,
+		},
+		{
+			name:        "prodi in scope",
+			ctx:         serviceContext("tracer", "s3cret"),
+			token:       token(authorization.RoleAdminProdi, commonJwt.Scopes{Kodeprodi: []string{"0401"}}),
+			req:         &pb.CheckPermissionRequest{Resource: testResource, Action: "ViewReport", Kodeprodi: "0401"},
+			wantAllowed: true,
+			wantReason:  reasonAllowed,
+		},
+		{
+			name:       "prodi out of scope",
+			ctx:        serviceContext("tracer", "s3cret"),
+			token:      token(authorization.RoleAdminProdi, commonJwt.Scopes{Kodeprodi: []string{"0401"}}),
+			req:        &pb.CheckPermissionRequest{Resource: testResource, Action: "ViewReport", Kodeprodi: "0402"},
+			wantReason: reasonOutOfScope,
+		},
+		{
+			name:     "invalid subject token",
+			ctx:      serviceContext("tracer", "s3cret"),
+			token:    "not-a-token",
+			req:      &pb.CheckPermissionRequest{Resource: testResource, Action: "ViewReport"},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "unknown service client",
+			ctx:      serviceContext("intruder", "s3cret"),
+			token:    token(authorization.RoleAdmin, commonJwt.Scopes{}),
+			req:      &pb.CheckPermissionRequest{Resource: testResource, Action: "ViewReport"},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Token = tt.token
+
+			res, err := ah.CheckPermission(tt.ctx, tt.req)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("CheckPermission() error = %v, want %v", err, tt.wantCode)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+			if res.GetData().GetAllowed() != tt.wantAllowed || res.GetData().GetReason() != tt.wantReason {
+				t.Errorf("CheckPermission() = %+v, want allowed %v because %q", res.GetData(), tt.wantAllowed, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAuthorizationHandlerBatchCheckPermission(t *testing.T) {
+	ah, jwtManager := newTestAuthorizationHandler(t)
+
+	token, err := jwtManager.GenerateToken("user:1", authorization.RoleAdminProdi, commonJwt.Scopes{Kodefak: []string{"04"}})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	checks := []*pb.PermissionCheck{
+		{Resource: testResource, Action: "ViewReport", Kodefak: "04"},
+		{Resource: testResource, Action: "ViewReport", Kodefak: "05"},
+		{Resource: testResource, Action: "DeleteReport"},
+	}
+	res, err := ah.BatchCheckPermission(serviceContext("tracer", "s3cret"), &pb.BatchCheckPermissionRequest{Token: token, Checks: checks})
+	if err != nil {
+		t.Fatalf("BatchCheckPermission() error = %v", err)
+	}
+
+	wantReasons := []string{reasonAllowed, reasonOutOfScope, reasonNoPolicy}
+	if len(res.GetData()) != len(wantReasons) {
+		t.Fatalf("BatchCheckPermission() returned %d decisions, want %d", len(res.GetData()), len(wantReasons))
+	}
+	for i, decision := range res.GetData() {
+		if decision.GetReason() != wantReasons[i] || decision.GetAllowed() != (i == 0) {
+			t.Errorf("decision %d = %+v, want %q", i, decision, wantReasons[i])
+		}
+	}
+
+	tooMany := make([]*pb.PermissionCheck, maxBatchChecks+1)
+	for i := range tooMany {
+		tooMany[i] = checks[0]
+	}
+	_, err = ah.BatchCheckPermission(serviceContext("tracer", "s3cret"), &pb.BatchCheckPermissionRequest{Token: token, Checks: tooMany})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("BatchCheckPermission() with %d checks error = %v, want %v", len(tooMany), err, codes.InvalidArgument)
+	}
+}
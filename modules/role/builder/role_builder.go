@@ -0,0 +1,26 @@
+package builder
+
+import (
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/modules/role/handler"
+	"tracerstudy-auth-service/modules/role/repository"
+	"tracerstudy-auth-service/modules/role/service"
+
+	"gorm.io/gorm"
+)
+
+func BuildPolicy(cfg config.Config, db *gorm.DB) *service.PolicyService {
+	permissionRepo := repository.NewPermissionRepository(db)
+	return service.NewPolicyService(cfg, permissionRepo)
+}
+
+func BuildRoleService(cfg config.Config, db *gorm.DB, policy *service.PolicyService) *service.RoleService {
+	roleRepo := repository.NewRoleRepository(db)
+	permissionRepo := repository.NewPermissionRepository(db)
+	return service.NewRoleService(cfg, roleRepo, permissionRepo, policy)
+}
+
+func BuildRoleHandler(cfg config.Config, db *gorm.DB, policy *service.PolicyService) *handler.RoleHandler {
+	roleSvc := BuildRoleService(cfg, db, policy)
+	return handler.NewRoleHandler(cfg, roleSvc)
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"time"
+	"tracerstudy-auth-service/pb"
+)
+
+const (
+	PermissionTableName     = "permissions"
+	RolePermissionTableName = "role_permissions"
+)
+
+// Permission allows performing Action (an RPC name) on Resource (a fully
+// qualified gRPC service name).
+type Permission struct {
+	Id          uint64    `gorm:"primaryKey" json:"id"`
+	Resource    string    `gorm:"size:128;uniqueIndex:idx_permissions_resource_action" json:"resource"`
+	Action      string    `gorm:"size:128;uniqueIndex:idx_permissions_resource_action" json:"action"`
+	Description string    `gorm:"size:255" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type RolePermission struct {
+	RoleId       uint32    `gorm:"primaryKey" json:"role_id"`
+	PermissionId uint64    `gorm:"primaryKey;index" json:"permission_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func NewPermission(resource, action, description string) *Permission {
+	return &Permission{
+		Resource:    resource,
+		Action:      action,
+		Description: description,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (p *Permission) TableName() string {
+	return PermissionTableName
+}
+
+func (rp *RolePermission) TableName() string {
+	return RolePermissionTableName
+}
+
+func ConvertPermissionEntityToProto(p *Permission) *pb.Permission {
+	return &pb.Permission{
+		Id:          p.Id,
+		Resource:    p.Resource,
+		Action:      p.Action,
+		Description: p.Description,
+		CreatedAt:   p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   p.UpdatedAt.Format(time.RFC3339),
+	}
+}
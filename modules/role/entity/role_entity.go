@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+	"tracerstudy-auth-service/pb"
+)
+
+const (
+	RoleTableName = "roles"
+)
+
+type Role struct {
+	Id          uint32    `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"size:64;uniqueIndex" json:"name"`
+	Description string    `gorm:"size:255" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewRole(id uint32, name, description string) *Role {
+	return &Role{
+		Id:          id,
+		Name:        name,
+		Description: description,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (r *Role) TableName() string {
+	return RoleTableName
+}
+
+func ConvertRoleEntityToProto(r *Role) *pb.Role {
+	return &pb.Role{
+		Id:          r.Id,
+		Name:        r.Name,
+		Description: r.Description,
+		CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   r.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/modules/role/entity"
+	"tracerstudy-auth-service/modules/role/service"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type RoleHandler struct {
+	pb.UnimplementedRoleServiceServer
+	config  config.Config
+	roleSvc service.RoleServiceUseCase
+}
+
+func NewRoleHandler(config config.Config, roleService service.RoleServiceUseCase) *RoleHandler {
+	return &RoleHandler{
+		config:  config,
+		roleSvc: roleService,
+	}
+}
+
+func (rh *RoleHandler) GetAllRoles(ctx context.Context, req *emptypb.Empty) (*pb.GetAllRolesResponse, error) {
+	roles, err := rh.roleSvc.FindAll(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - GetAllRoles] Error while get all roles:", parseError.Message)
+		return &pb.GetAllRolesResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	var roleArr []*pb.Role
+	for _, r := range roles {
+		roleArr = append(roleArr, entity.ConvertRoleEntityToProto(r))
+	}
+
+	return &pb.GetAllRolesResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "get all roles success",
+		Data:    roleArr,
+	}, nil
+}
+
+func (rh *RoleHandler) GetRoleById(ctx context.Context, req *pb.GetRoleByIdRequest) (*pb.GetRoleResponse, error) {
+	role, err := rh.roleSvc.FindById(ctx, req.GetId())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - GetRoleById] Error while get role by ID:", parseError.Message)
+		return &pb.GetRoleResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.GetRoleResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "get role success",
+		Data:    entity.ConvertRoleEntityToProto(role),
+	}, nil
+}
+
+func (rh *RoleHandler) CreateRole(ctx context.Context, req *pb.Role) (*pb.GetRoleResponse, error) {
+	role, err := rh.roleSvc.Create(ctx, req.GetName(), req.GetDescription())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - CreateRole] Error while create role:", parseError.Message)
+		return &pb.GetRoleResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.GetRoleResponse{
+		Code:    uint32(http.StatusCreated),
+		Message: "create role success",
+		Data:    entity.ConvertRoleEntityToProto(role),
+	}, nil
+}
+
+func (rh *RoleHandler) UpdateRole(ctx context.Context, req *pb.Role) (*pb.GetRoleResponse, error) {
+	roleDataUpdate := &entity.Role{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+	}
+
+	role, err := rh.roleSvc.Update(ctx, req.GetId(), roleDataUpdate)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - UpdateRole] Error while update role:", parseError.Message)
+		return &pb.GetRoleResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.GetRoleResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "update role success",
+		Data:    entity.ConvertRoleEntityToProto(role),
+	}, nil
+}
+
+func (rh *RoleHandler) DeleteRole(ctx context.Context, req *pb.GetRoleByIdRequest) (*pb.DeleteRoleResponse, error) {
+	if err := rh.roleSvc.Delete(ctx, req.GetId()); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - DeleteRole] Error while delete role:", parseError.Message)
+		return &pb.DeleteRoleResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.DeleteRoleResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "delete role success",
+	}, nil
+}
+
+func (rh *RoleHandler) GetAllPermissions(ctx context.Context, req *emptypb.Empty) (*pb.GetAllPermissionsResponse, error) {
+	permissions, err := rh.roleSvc.FindAllPermissions(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - GetAllPermissions] Error while get all permissions:", parseError.Message)
+		return &pb.GetAllPermissionsResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.GetAllPermissionsResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "get all permissions success",
+		Data:    convertPermissions(permissions),
+	}, nil
+}
+
+func (rh *RoleHandler) CreatePermission(ctx context.Context, req *pb.Permission) (*pb.GetPermissionResponse, error) {
+	permission, err := rh.roleSvc.CreatePermission(ctx, req.GetResource(), req.GetAction(), req.GetDescription())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - CreatePermission] Error while create permission:", parseError.Message)
+		return &pb.GetPermissionResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.GetPermissionResponse{
+		Code:    uint32(http.StatusCreated),
+		Message: "create permission success",
+		Data:    entity.ConvertPermissionEntityToProto(permission),
+	}, nil
+}
+
+func (rh *RoleHandler) UpdatePermission(ctx context.Context, req *pb.Permission) (*pb.GetPermissionResponse, error) {
+	permissionDataUpdate := &entity.Permission{
+		Resource:    req.GetResource(),
+		Action:      req.GetAction(),
+		Description: req.GetDescription(),
+	}
+
+	permission, err := rh.roleSvc.UpdatePermission(ctx, req.GetId(), permissionDataUpdate)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - UpdatePermission] Error while update permission:", parseError.Message)
+		return &pb.GetPermissionResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.GetPermissionResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "update permission success",
+		Data:    entity.ConvertPermissionEntityToProto(permission),
+	}, nil
+}
+
+func (rh *RoleHandler) DeletePermission(ctx context.Context, req *pb.GetPermissionByIdRequest) (*pb.DeletePermissionResponse, error) {
+	if err := rh.roleSvc.DeletePermission(ctx, req.GetId()); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - DeletePermission] Error while delete permission:", parseError.Message)
+		return &pb.DeletePermissionResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.DeletePermissionResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "delete permission success",
+	}, nil
+}
+
+func (rh *RoleHandler) GetRolePermissions(ctx context.Context, req *pb.GetRoleByIdRequest) (*pb.GetRolePermissionsResponse, error) {
+	permissions, err := rh.roleSvc.FindRolePermissions(ctx, req.GetId())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - GetRolePermissions] Error while get role permissions:", parseError.Message)
+		return &pb.GetRolePermissionsResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.GetRolePermissionsResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "get role permissions success",
+		RoleId:  req.GetId(),
+		Data:    convertPermissions(permissions),
+	}, nil
+}
+
+func (rh *RoleHandler) SetRolePermissions(ctx context.Context, req *pb.SetRolePermissionsRequest) (*pb.GetRolePermissionsResponse, error) {
+	permissions, err := rh.roleSvc.SetRolePermissions(ctx, req.GetRoleId(), req.GetPermissionIds())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleHandler - SetRolePermissions] Error while set role permissions:", parseError.Message)
+		return &pb.GetRolePermissionsResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.GetRolePermissionsResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "set role permissions success",
+		RoleId:  req.GetRoleId(),
+		Data:    convertPermissions(permissions),
+	}, nil
+}
+
+func convertPermissions(permissions []*entity.Permission) []*pb.Permission {
+	var permissionArr []*pb.Permission
+	for _, p := range permissions {
+		permissionArr = append(permissionArr, entity.ConvertPermissionEntityToProto(p))
+	}
+
+	return permissionArr
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"tracerstudy-auth-service/modules/role/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PermissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) *PermissionRepository {
+	return &PermissionRepository{
+		db: db,
+	}
+}
+
+type PermissionRepositoryUseCase interface {
+	FindAll(ctx context.Context) ([]*entity.Permission, error)
+	FindById(ctx context.Context, id uint64) (*entity.Permission, error)
+	FindByIds(ctx context.Context, ids []uint64) ([]*entity.Permission, error)
+	FindByRoleId(ctx context.Context, roleId uint32) ([]*entity.Permission, error)
+	Create(ctx context.Context, req *entity.Permission) (*entity.Permission, error)
+	Update(ctx context.Context, permission *entity.Permission, updatedFields map[string]interface{}) (*entity.Permission, error)
+	Delete(ctx context.Context, id uint64) error
+	FindAllGrants(ctx context.Context) ([]*entity.RolePermission, error)
+	CreateWithGrants(ctx context.Context, req *entity.Permission, roleIds []uint32) (*entity.Permission, error)
+	SetRolePermissions(ctx context.Context, roleId uint32, permissionIds []uint64) error
+}
+
+func (r *PermissionRepository) FindAll(ctx context.Context) ([]*entity.Permission, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PermissionRepository - FindAll")
+	defer span.End()
+
+	var permissions []*entity.Permission
+	if err := r.db.Debug().WithContext(ctxSpan).Order("resource, action").Find(&permissions).Error; err != nil {
+		log.Println("ERROR: [PermissionRepository - FindAll] Internal server error:", err)
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (r *PermissionRepository) FindById(ctx context.Context, id uint64) (*entity.Permission, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PermissionRepository - FindById")
+	defer span.End()
+
+	var permission entity.Permission
+	if err := r.db.Debug().WithContext(ctxSpan).Where("id = ?", id).First(&permission).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [PermissionRepository - FindById] Record not found for id", id)
+			return nil, status.Errorf(codes.NotFound, "record not found for id %d", id)
+		}
+		log.Println("ERROR: [PermissionRepository - FindById] Internal server error:", err)
+		return nil, err
+	}
+
+	return &permission, nil
+}
+
+func (r *PermissionRepository) FindByIds(ctx context.Context, ids []uint64) ([]*entity.Permission, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PermissionRepository - FindByIds")
+	defer span.End()
+
+	var permissions []*entity.Permission
+	if len(ids) == 0 {
+		return permissions, nil
+	}
+
+	if err := r.db.Debug().WithContext(ctxSpan).Where("id IN ?", ids).Find(&permissions).Error; err != nil {
+		log.Println("ERROR: [PermissionRepository - FindByIds] Internal server error:", err)
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (r *PermissionRepository) FindByRoleId(ctx context.Context, roleId uint32) ([]*entity.Permission, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PermissionRepository - FindByRoleId")
+	defer span.End()
+
+	var permissions []*entity.Permission
+	err := r.db.Debug().WithContext(ctxSpan).
+		Joins("JOIN "+entity.RolePermissionTableName+" rp ON rp.permission_id = "+entity.PermissionTableName+".id").
+		Where("rp.role_id = ?", roleId).
+		Order("resource, action").
+		Find(&permissions).Error
+	if err != nil {
+		log.Println("ERROR: [PermissionRepository - FindByRoleId] Internal server error:", err)
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (r *PermissionRepository) Create(ctx context.Context, req *entity.Permission) (*entity.Permission, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PermissionRepository - Create")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Create(req).Error; err != nil {
+		log.Println("ERROR: [PermissionRepository - Create] Internal server error:", err)
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (r *PermissionRepository) Update(ctx context.Context, permission *entity.Permission, updatedFields map[string]interface{}) (*entity.Permission, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PermissionRepository - Update")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Model(permission).Updates(updatedFields).Error; err != nil {
+		log.Println("ERROR: [PermissionRepository - Update] Internal server error:", err)
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+// Delete removes the permission together with its grants.
+func (r *PermissionRepository) Delete(ctx context.Context, id uint64) error {
+	ctxSpan, span := trace.StartSpan(ctx, "PermissionRepository - Delete")
+	defer span.End()
+
+	err := r.db.Debug().WithContext(ctxSpan).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("permission_id = ?", id).Delete(&entity.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&entity.Permission{}).Error
+	})
+	if err != nil {
+		log.Println("ERROR: [PermissionRepository - Delete] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PermissionRepository) FindAllGrants(ctx context.Context) ([]*entity.RolePermission, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PermissionRepository - FindAllGrants")
+	defer span.End()
+
+	var grants []*entity.RolePermission
+	if err := r.db.Debug().WithContext(ctxSpan).Find(&grants).Error; err != nil {
+		log.Println("ERROR: [PermissionRepository - FindAllGrants] Internal server error:", err)
+		return nil, err
+	}
+
+	return grants, nil
+}
+
+// CreateWithGrants creates the permission and gives it to each of roleIds
+// in one transaction, so a permission never exists without its grants.
+func (r *PermissionRepository) CreateWithGrants(ctx context.Context, req *entity.Permission, roleIds []uint32) (*entity.Permission, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "PermissionRepository - CreateWithGrants")
+	defer span.End()
+
+	err := r.db.Debug().WithContext(ctxSpan).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(req).Error; err != nil {
+			return err
+		}
+		if len(roleIds) == 0 {
+			return nil
+		}
+
+		grants := make([]*entity.RolePermission, 0, len(roleIds))
+		for _, roleId := range roleIds {
+			grants = append(grants, &entity.RolePermission{RoleId: roleId, PermissionId: req.Id, CreatedAt: req.CreatedAt})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&grants).Error
+	})
+	if err != nil {
+		log.Println("ERROR: [PermissionRepository - CreateWithGrants] Internal server error:", err)
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// SetRolePermissions replaces every grant of the role with permissionIds.
+func (r *PermissionRepository) SetRolePermissions(ctx context.Context, roleId uint32, permissionIds []uint64) error {
+	ctxSpan, span := trace.StartSpan(ctx, "PermissionRepository - SetRolePermissions")
+	defer span.End()
+
+	now := time.Now()
+	grants := make([]*entity.RolePermission, 0, len(permissionIds))
+	for _, permissionId := range permissionIds {
+		grants = append(grants, &entity.RolePermission{RoleId: roleId, PermissionId: permissionId, CreatedAt: now})
+	}
+
+	err := r.db.Debug().WithContext(ctxSpan).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleId).Delete(&entity.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(grants) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&grants).Error
+	})
+	if err != nil {
+		log.Println("ERROR: [PermissionRepository - SetRolePermissions] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"tracerstudy-auth-service/modules/role/entity"
+	userEntity "tracerstudy-auth-service/modules/user/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) *RoleRepository {
+	return &RoleRepository{
+		db: db,
+	}
+}
+
+type RoleRepositoryUseCase interface {
+	FindAll(ctx context.Context) ([]*entity.Role, error)
+	FindById(ctx context.Context, id uint32) (*entity.Role, error)
+	Create(ctx context.Context, req *entity.Role) (*entity.Role, error)
+	Update(ctx context.Context, role *entity.Role, updatedFields map[string]interface{}) (*entity.Role, error)
+	Delete(ctx context.Context, id uint32) error
+	CountUsers(ctx context.Context, id uint32) (int64, error)
+}
+
+func (r *RoleRepository) FindAll(ctx context.Context) ([]*entity.Role, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "RoleRepository - FindAll")
+	defer span.End()
+
+	var roles []*entity.Role
+	if err := r.db.Debug().WithContext(ctxSpan).Order("id").Find(&roles).Error; err != nil {
+		log.Println("ERROR: [RoleRepository - FindAll] Internal server error:", err)
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *RoleRepository) FindById(ctx context.Context, id uint32) (*entity.Role, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "RoleRepository - FindById")
+	defer span.End()
+
+	var role entity.Role
+	if err := r.db.Debug().WithContext(ctxSpan).Where("id = ?", id).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [RoleRepository - FindById] Record not found for id", id)
+			return nil, status.Errorf(codes.NotFound, "record not found for id %d", id)
+		}
+		log.Println("ERROR: [RoleRepository - FindById] Internal server error:", err)
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (r *RoleRepository) Create(ctx context.Context, req *entity.Role) (*entity.Role, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "RoleRepository - Create")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Create(req).Error; err != nil {
+		log.Println("ERROR: [RoleRepository - Create] Internal server error:", err)
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (r *RoleRepository) Update(ctx context.Context, role *entity.Role, updatedFields map[string]interface{}) (*entity.Role, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "RoleRepository - Update")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Model(role).Updates(updatedFields).Error; err != nil {
+		log.Println("ERROR: [RoleRepository - Update] Internal server error:", err)
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// Delete removes the role together with its permission grants.
+func (r *RoleRepository) Delete(ctx context.Context, id uint32) error {
+	ctxSpan, span := trace.StartSpan(ctx, "RoleRepository - Delete")
+	defer span.End()
+
+	err := r.db.Debug().WithContext(ctxSpan).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&entity.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&entity.Role{}).Error
+	})
+	if err != nil {
+		log.Println("ERROR: [RoleRepository - Delete] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
+
+// CountUsers counts the users that currently hold the role.
+func (r *RoleRepository) CountUsers(ctx context.Context, id uint32) (int64, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "RoleRepository - CountUsers")
+	defer span.End()
+
+	var count int64
+	if err := r.db.Debug().WithContext(ctxSpan).Model(&userEntity.User{}).Where("role_id = ?", id).Count(&count).Error; err != nil {
+		log.Println("ERROR: [RoleRepository - CountUsers] Internal server error:", err)
+		return 0, err
+	}
+
+	return count, nil
+}
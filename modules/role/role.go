@@ -0,0 +1,28 @@
+package role
+
+import (
+	"context"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/modules/role/builder"
+	"tracerstudy-auth-service/modules/role/service"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+)
+
+// InitPolicy seeds the default roles and permissions and returns the
+// database-backed policy the interceptor and AuthorizationService share.
+func InitPolicy(ctx context.Context, cfg config.Config, db *gorm.DB) (*service.PolicyService, error) {
+	policy := builder.BuildPolicy(cfg, db)
+	if err := builder.BuildRoleService(cfg, db, policy).Seed(ctx); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func InitGrpc(server *grpc.Server, cfg config.Config, db *gorm.DB, policy *service.PolicyService) {
+	role := builder.BuildRoleHandler(cfg, db, policy)
+	pb.RegisterRoleServiceServer(server, role)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/modules/role/repository"
+)
+
+// PolicyService is the authorization.Policy backed by the permissions and
+// role_permissions tables. The whole table is small, so it is loaded at once
+// and cached for cfg.Policy.CacheTTL. Writes made through RoleService
+// invalidate the cache immediately; other replicas pick them up when their
+// cache expires.
+type PolicyService struct {
+	cfg                  config.Config
+	permissionRepository repository.PermissionRepositoryUseCase
+	mu                   sync.Mutex
+	grants               map[string]map[string]map[uint32]bool
+	loadedAt             time.Time
+}
+
+func NewPolicyService(cfg config.Config, permissionRepository repository.PermissionRepositoryUseCase) *PolicyService {
+	return &PolicyService{
+		cfg:                  cfg,
+		permissionRepository: permissionRepository,
+	}
+}
+
+func (svc *PolicyService) Governs(ctx context.Context, resource, action string) (bool, error) {
+	grants, err := svc.load(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := grants[resource][action]
+	return ok, nil
+}
+
+func (svc *PolicyService) Allows(ctx context.Context, resource, action string, role uint32) (bool, error) {
+	grants, err := svc.load(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return grants[resource][action][role], nil
+}
+
+// Invalidate makes the next decision read the permissions again.
+func (svc *PolicyService) Invalidate() {
+	svc.mu.Lock()
+	svc.loadedAt = time.Time{}
+	svc.mu.Unlock()
+}
+
+func (svc *PolicyService) load(ctx context.Context) (map[string]map[string]map[uint32]bool, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	if svc.grants != nil && time.Since(svc.loadedAt) < svc.cfg.Policy.CacheTTL {
+		return svc.grants, nil
+	}
+
+	grants, err := svc.fetch(ctx)
+	if err != nil {
+		if svc.grants != nil {
+			// deciding on slightly stale rules beats failing every request
+			log.Println("WARNING: [PolicyService - load] Using cached permissions after error:", err)
+			return svc.grants, nil
+		}
+		return nil, err
+	}
+
+	svc.grants = grants
+	svc.loadedAt = time.Now()
+
+	return grants, nil
+}
+
+func (svc *PolicyService) fetch(ctx context.Context) (map[string]map[string]map[uint32]bool, error) {
+	permissions, err := svc.permissionRepository.FindAll(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [PolicyService - fetch] Error while find all permissions:", parseError.Message)
+		return nil, err
+	}
+
+	rolePermissions, err := svc.permissionRepository.FindAllGrants(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [PolicyService - fetch] Error while find all role permissions:", parseError.Message)
+		return nil, err
+	}
+
+	roles := make(map[uint64]map[uint32]bool, len(permissions))
+	grants := make(map[string]map[string]map[uint32]bool)
+	for _, p := range permissions {
+		roles[p.Id] = make(map[uint32]bool)
+		if grants[p.Resource] == nil {
+			grants[p.Resource] = make(map[string]map[uint32]bool)
+		}
+		grants[p.Resource][p.Action] = roles[p.Id]
+	}
+
+	for _, rp := range rolePermissions {
+		if roles[rp.PermissionId] != nil {
+			roles[rp.PermissionId][rp.RoleId] = true
+		}
+	}
+
+	return grants, nil
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"tracerstudy-auth-service/common/authorization"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/role/entity"
+	"tracerstudy-auth-service/modules/role/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type RoleService struct {
+	cfg                  config.Config
+	roleRepository       repository.RoleRepositoryUseCase
+	permissionRepository repository.PermissionRepositoryUseCase
+	policy               *PolicyService
+}
+
+type RoleServiceUseCase interface {
+	FindAll(ctx context.Context) ([]*entity.Role, error)
+	FindById(ctx context.Context, id uint32) (*entity.Role, error)
+	Create(ctx context.Context, name, description string) (*entity.Role, error)
+	Update(ctx context.Context, id uint32, fields *entity.Role) (*entity.Role, error)
+	Delete(ctx context.Context, id uint32) error
+	FindAllPermissions(ctx context.Context) ([]*entity.Permission, error)
+	CreatePermission(ctx context.Context, resource, action, description string) (*entity.Permission, error)
+	UpdatePermission(ctx context.Context, id uint64, fields *entity.Permission) (*entity.Permission, error)
+	DeletePermission(ctx context.Context, id uint64) error
+	FindRolePermissions(ctx context.Context, roleId uint32) ([]*entity.Permission, error)
+	SetRolePermissions(ctx context.Context, roleId uint32, permissionIds []uint64) ([]*entity.Permission, error)
+}
+
+func NewRoleService(cfg config.Config, roleRepository repository.RoleRepositoryUseCase, permissionRepository repository.PermissionRepositoryUseCase, policy *PolicyService) *RoleService {
+	return &RoleService{
+		cfg:                  cfg,
+		roleRepository:       roleRepository,
+		permissionRepository: permissionRepository,
+		policy:               policy,
+	}
+}
+
+// Seed creates the built-in roles and the default permissions that are
+// missing from the database. Permissions that already exist are left alone,
+// so grants changed by a Super Admin survive restarts, while RPCs added in
+// code are never left without a rule.
+func (svc *RoleService) Seed(ctx context.Context) error {
+	for id, name := range authorization.GetDefaultRoles() {
+		_, err := svc.roleRepository.FindById(ctx, id)
+		if err == nil {
+			continue
+		}
+
+		if status.Code(err) != codes.NotFound {
+			parseError := errors.ParseError(err)
+			log.Println("ERROR: [RoleService - Seed] Error while find role by ID:", parseError.Message)
+			return err
+		}
+
+		if _, err := svc.roleRepository.Create(ctx, entity.NewRole(id, name, "")); err != nil {
+			parseError := errors.ParseError(err)
+			log.Println("ERROR: [RoleService - Seed] Error while create role:", parseError.Message)
+			return err
+		}
+	}
+
+	permissions, err := svc.permissionRepository.FindAll(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - Seed] Error while find all permissions:", parseError.Message)
+		return err
+	}
+
+	known := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		known[p.Resource+"/"+p.Action] = true
+	}
+
+	for resource, actions := range authorization.GetAccessibleRoles() {
+		for action, roleIds := range actions {
+			if known[resource+"/"+action] {
+				continue
+			}
+
+			if _, err := svc.permissionRepository.CreateWithGrants(ctx, entity.NewPermission(resource, action, ""), roleIds); err != nil {
+				parseError := errors.ParseError(err)
+				log.Println("ERROR: [RoleService - Seed] Error while create permission:", parseError.Message)
+				return err
+			}
+		}
+	}
+
+	svc.policy.Invalidate()
+
+	return nil
+}
+
+func (svc *RoleService) FindAll(ctx context.Context) ([]*entity.Role, error) {
+	res, err := svc.roleRepository.FindAll(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - FindAll] Error while find all roles:", parseError.Message)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (svc *RoleService) FindById(ctx context.Context, id uint32) (*entity.Role, error) {
+	res, err := svc.roleRepository.FindById(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - FindById] Error while find role by ID:", parseError.Message)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (svc *RoleService) Create(ctx context.Context, name, description string) (*entity.Role, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		log.Println("WARNING: [RoleService - Create] Missing role name")
+		return nil, status.Errorf(codes.InvalidArgument, "role name is required")
+	}
+
+	res, err := svc.roleRepository.Create(ctx, entity.NewRole(0, name, description))
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - Create] Error while create role:", parseError.Message)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (svc *RoleService) Update(ctx context.Context, id uint32, fields *entity.Role) (*entity.Role, error) {
+	role, err := svc.roleRepository.FindById(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - Update] Error while find role by ID:", parseError.Message)
+		return nil, err
+	}
+
+	updatedMap := make(map[string]interface{})
+
+	utils.AddItemToMap(updatedMap, "name", strings.TrimSpace(fields.Name))
+	utils.AddItemToMap(updatedMap, "description", fields.Description)
+	updatedMap["updated_at"] = time.Now()
+
+	res, err := svc.roleRepository.Update(ctx, role, updatedMap)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - Update] Error while update role:", parseError.Message)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (svc *RoleService) Delete(ctx context.Context, id uint32) error {
+	if _, ok := authorization.GetDefaultRoles()[id]; ok {
+		log.Println("WARNING: [RoleService - Delete] Refusing to delete built-in role", id)
+		return status.Errorf(codes.FailedPrecondition, "role %d is built in and cannot be deleted", id)
+	}
+
+	_, err := svc.roleRepository.FindById(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - Delete] Error while find role by ID:", parseError.Message)
+		return err
+	}
+
+	users, err := svc.roleRepository.CountUsers(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - Delete] Error while count role users:", parseError.Message)
+		return err
+	}
+
+	if users > 0 {
+		log.Println("WARNING: [RoleService - Delete] Role is still assigned to users:", id)
+		return status.Errorf(codes.FailedPrecondition, "role %d is still assigned to %d users", id, users)
+	}
+
+	if err := svc.roleRepository.Delete(ctx, id); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - Delete] Error while delete role:", parseError.Message)
+		return err
+	}
+
+	svc.policy.Invalidate()
+
+	return nil
+}
+
+func (svc *RoleService) FindAllPermissions(ctx context.Context) ([]*entity.Permission, error) {
+	res, err := svc.permissionRepository.FindAll(ctx)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - FindAllPermissions] Error while find all permissions:", parseError.Message)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (svc *RoleService) CreatePermission(ctx context.Context, resource, action, description string) (*entity.Permission, error) {
+	resource, action = strings.TrimSpace(resource), strings.TrimSpace(action)
+	if resource == "" || action == "" {
+		log.Println("WARNING: [RoleService - CreatePermission] Missing resource or action")
+		return nil, status.Errorf(codes.InvalidArgument, "resource and action are required")
+	}
+
+	res, err := svc.permissionRepository.Create(ctx, entity.NewPermission(resource, action, description))
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - CreatePermission] Error while create permission:", parseError.Message)
+		return nil, err
+	}
+
+	svc.policy.Invalidate()
+
+	return res, nil
+}
+
+func (svc *RoleService) UpdatePermission(ctx context.Context, id uint64, fields *entity.Permission) (*entity.Permission, error) {
+	permission, err := svc.permissionRepository.FindById(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - UpdatePermission] Error while find permission by ID:", parseError.Message)
+		return nil, err
+	}
+
+	resource, action := strings.TrimSpace(fields.Resource), strings.TrimSpace(fields.Action)
+	renamed := (resource != "" && resource != permission.Resource) || (action != "" && action != permission.Action)
+	if renamed && authorization.IsDefaultPermission(permission.Resource, permission.Action) {
+		log.Println("WARNING: [RoleService - UpdatePermission] Refusing to rename built-in permission", id)
+		return nil, status.Errorf(codes.FailedPrecondition, "permission %d is built in and cannot be renamed", id)
+	}
+
+	updatedMap := make(map[string]interface{})
+
+	utils.AddItemToMap(updatedMap, "resource", resource)
+	utils.AddItemToMap(updatedMap, "action", action)
+	utils.AddItemToMap(updatedMap, "description", fields.Description)
+	updatedMap["updated_at"] = time.Now()
+
+	res, err := svc.permissionRepository.Update(ctx, permission, updatedMap)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - UpdatePermission] Error while update permission:", parseError.Message)
+		return nil, err
+	}
+
+	svc.policy.Invalidate()
+
+	return res, nil
+}
+
+func (svc *RoleService) DeletePermission(ctx context.Context, id uint64) error {
+	permission, err := svc.permissionRepository.FindById(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - DeletePermission] Error while find permission by ID:", parseError.Message)
+		return err
+	}
+
+	if authorization.IsDefaultPermission(permission.Resource, permission.Action) {
+		log.Println("WARNING: [RoleService - DeletePermission] Refusing to delete built-in permission", id)
+		return status.Errorf(codes.FailedPrecondition, "permission %d is built in and cannot be deleted", id)
+	}
+
+	if err := svc.permissionRepository.Delete(ctx, id); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - DeletePermission] Error while delete permission:", parseError.Message)
+		return err
+	}
+
+	svc.policy.Invalidate()
+
+	return nil
+}
+
+func (svc *RoleService) FindRolePermissions(ctx context.Context, roleId uint32) ([]*entity.Permission, error) {
+	if _, err := svc.roleRepository.FindById(ctx, roleId); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - FindRolePermissions] Error while find role by ID:", parseError.Message)
+		return nil, err
+	}
+
+	res, err := svc.permissionRepository.FindByRoleId(ctx, roleId)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - FindRolePermissions] Error while find role permissions:", parseError.Message)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (svc *RoleService) SetRolePermissions(ctx context.Context, roleId uint32, permissionIds []uint64) ([]*entity.Permission, error) {
+	if _, err := svc.roleRepository.FindById(ctx, roleId); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - SetRolePermissions] Error while find role by ID:", parseError.Message)
+		return nil, err
+	}
+
+	unique := make([]uint64, 0, len(permissionIds))
+	seen := make(map[uint64]bool, len(permissionIds))
+	for _, id := range permissionIds {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	permissions, err := svc.permissionRepository.FindByIds(ctx, unique)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - SetRolePermissions] Error while find permissions by ID:", parseError.Message)
+		return nil, err
+	}
+
+	if len(permissions) != len(unique) {
+		log.Println("WARNING: [RoleService - SetRolePermissions] Unknown permission ID for role", roleId)
+		return nil, status.Errorf(codes.InvalidArgument, "unknown permission id")
+	}
+
+	// without them no one could manage roles and permissions anymore
+	if roleId == authorization.RoleSuperAdmin && !grantsRoleService(permissions) {
+		log.Println("WARNING: [RoleService - SetRolePermissions] Refusing to revoke RoleService permissions from Super Admin")
+		return nil, status.Errorf(codes.FailedPrecondition, "Super Admin must keep every %s permission", authorization.RoleSvc)
+	}
+
+	if err := svc.permissionRepository.SetRolePermissions(ctx, roleId, unique); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [RoleService - SetRolePermissions] Error while set role permissions:", parseError.Message)
+		return nil, err
+	}
+
+	svc.policy.Invalidate()
+
+	return svc.permissionRepository.FindByRoleId(ctx, roleId)
+}
+
+// grantsRoleService reports whether permissions include every built-in
+// permission of RoleService.
+func grantsRoleService(permissions []*entity.Permission) bool {
+	resource := authorization.BasePath + "." + authorization.RoleSvc
+
+	granted := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		if p.Resource == resource {
+			granted[p.Action] = true
+		}
+	}
+
+	for action := range authorization.GetAccessibleRoles()[resource] {
+		if !granted[action] {
+			return false
+		}
+	}
+
+	return true
+}
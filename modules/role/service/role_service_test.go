@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"tracerstudy-auth-service/common/authorization"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/modules/role/entity"
+	"tracerstudy-auth-service/modules/role/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRoleRepository struct {
+	repository.RoleRepositoryUseCase
+}
+
+func (r *fakeRoleRepository) FindById(ctx context.Context, id uint32) (*entity.Role, error) {
+	return &entity.Role{Id: id}, nil
+}
+
+type fakePermissionRepository struct {
+	repository.PermissionRepositoryUseCase
+	permissions map[uint64]*entity.Permission
+	grants      map[uint64][]uint32
+	updated     bool
+	deleted     bool
+}
+
+func (r *fakePermissionRepository) FindAll(ctx context.Context) ([]*entity.Permission, error) {
+	var permissions []*entity.Permission
+	for _, p := range r.permissions {
+		permissions = append(permissions, p)
+	}
+	return permissions, nil
+}
+
+func (r *fakePermissionRepository) FindByIds(ctx context.Context, ids []uint64) ([]*entity.Permission, error) {
+	var permissions []*entity.Permission
+	for _, id := range ids {
+		if p, ok := r.permissions[id]; ok {
+			permissions = append(permissions, p)
+		}
+	}
+	return permissions, nil
+}
+
+func (r *fakePermissionRepository) FindByRoleId(ctx context.Context, roleId uint32) ([]*entity.Permission, error) {
+	return nil, nil
+}
+
+func (r *fakePermissionRepository) SetRolePermissions(ctx context.Context, roleId uint32, permissionIds []uint64) error {
+	r.updated = true
+	return nil
+}
+
+func (r *fakePermissionRepository) CreateWithGrants(ctx context.Context, req *entity.Permission, roleIds []uint32) (*entity.Permission, error) {
+	req.Id = uint64(len(r.permissions) + 1)
+	r.permissions[req.Id] = req
+	r.grants[req.Id] = roleIds
+	return req, nil
+}
+
+func (r *fakePermissionRepository) FindById(ctx context.Context, id uint64) (*entity.Permission, error) {
+	permission, ok := r.permissions[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "permission not found")
+	}
+	return permission, nil
+}
+
+func (r *fakePermissionRepository) Update(ctx context.Context, permission *entity.Permission, updatedFields map[string]interface{}) (*entity.Permission, error) {
+	r.updated = true
+	return permission, nil
+}
+
+func (r *fakePermissionRepository) Delete(ctx context.Context, id uint64) error {
+	r.deleted = true
+	return nil
+}
+
+func TestRoleServiceBuiltInPermissions(t *testing.T) {
+	const (
+		builtInId = 1
+		customId  = 2
+	)
+
+	resource := authorization.BasePath + "." + authorization.UserSvc
+
+	tests := []struct {
+		name     string
+		call     func(*RoleService) error
+		wantCode codes.Code
+		wantDone bool
+	}{
+		{
+			name: "rename a built-in permission",
+			call: func(svc *RoleService) error {
+				_, err := svc.UpdatePermission(context.Background(), builtInId, &entity.Permission{Action: "ListUsers"})
+				return err
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "move a built-in permission to another resource",
+			call: func(svc *RoleService) error {
+				_, err := svc.UpdatePermission(context.Background(), builtInId, &entity.Permission{Resource: "other.Service"})
+				return err
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "describe a built-in permission",
+			call: func(svc *RoleService) error {
+				_, err := svc.UpdatePermission(context.Background(), builtInId, &entity.Permission{Resource: resource, Action: "GetAllUsers", Description: "List users"})
+				return err
+			},
+			wantDone: true,
+		},
+		{
+			name: "delete a built-in permission",
+			call: func(svc *RoleService) error {
+				return svc.DeletePermission(context.Background(), builtInId)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "rename a custom permission",
+			call: func(svc *RoleService) error {
+				_, err := svc.UpdatePermission(context.Background(), customId, &entity.Permission{Action: "Export"})
+				return err
+			},
+			wantDone: true,
+		},
+		{
+			name: "delete a custom permission",
+			call: func(svc *RoleService) error {
+				return svc.DeletePermission(context.Background(), customId)
+			},
+			wantDone: true,
+		},
+		{
+			name: "delete an unknown permission",
+			call: func(svc *RoleService) error {
+				return svc.DeletePermission(context.Background(), 3)
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePermissionRepository{permissions: map[uint64]*entity.Permission{
+				builtInId: {Id: builtInId, Resource: resource, Action: "GetAllUsers"},
+				customId:  {Id: customId, Resource: resource, Action: "ExportUsers"},
+			}}
+			svc := NewRoleService(config.Config{}, nil, repo, NewPolicyService(config.Config{}, repo))
+
+			err := tt.call(svc)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("error = %v, want %v", err, tt.wantCode)
+			}
+			if done := repo.updated || repo.deleted; done != tt.wantDone {
+				t.Errorf("permission changed = %v, want %v", done, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestRoleServiceSetSuperAdminPermissions(t *testing.T) {
+	roleResource := authorization.BasePath + "." + authorization.RoleSvc
+	userResource := authorization.BasePath + "." + authorization.UserSvc
+
+	permissions := map[uint64]*entity.Permission{}
+	var roleServiceIds []uint64
+	for action := range authorization.GetAccessibleRoles()[roleResource] {
+		id := uint64(len(permissions) + 1)
+		permissions[id] = &entity.Permission{Id: id, Resource: roleResource, Action: action}
+		roleServiceIds = append(roleServiceIds, id)
+	}
+	userId := uint64(len(permissions) + 1)
+	permissions[userId] = &entity.Permission{Id: userId, Resource: userResource, Action: "GetAllUsers"}
+
+	tests := []struct {
+		name          string
+		roleId        uint32
+		permissionIds []uint64
+		wantCode      codes.Code
+	}{
+		{
+			name:          "Super Admin keeps RoleService",
+			roleId:        authorization.RoleSuperAdmin,
+			permissionIds: append([]uint64{userId}, roleServiceIds...),
+		},
+		{
+			name:          "Super Admin loses one RoleService permission",
+			roleId:        authorization.RoleSuperAdmin,
+			permissionIds: append([]uint64{userId}, roleServiceIds[1:]...),
+			wantCode:      codes.FailedPrecondition,
+		},
+		{
+			name:          "Super Admin loses every permission",
+			roleId:        authorization.RoleSuperAdmin,
+			permissionIds: nil,
+			wantCode:      codes.FailedPrecondition,
+		},
+		{
+			name:          "another role without RoleService",
+			roleId:        authorization.RoleAdmin,
+			permissionIds: []uint64{userId},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePermissionRepository{permissions: permissions}
+			svc := NewRoleService(config.Config{}, &fakeRoleRepository{}, repo, NewPolicyService(config.Config{}, repo))
+
+			_, err := svc.SetRolePermissions(context.Background(), tt.roleId, tt.permissionIds)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("SetRolePermissions() error = %v, want %v", err, tt.wantCode)
+			}
+			if repo.updated != (tt.wantCode == codes.OK) {
+				t.Errorf("grants replaced = %v, want %v", repo.updated, tt.wantCode == codes.OK)
+			}
+		})
+	}
+}
+
+func TestRoleServiceSeed(t *testing.T) {
+	repo := &fakePermissionRepository{
+		permissions: map[uint64]*entity.Permission{
+			1: {Id: 1, Resource: authorization.BasePath + "." + authorization.UserSvc, Action: "GetAllUsers"},
+		},
+		grants: make(map[uint64][]uint32),
+	}
+	svc := NewRoleService(config.Config{}, &fakeRoleRepository{}, repo, NewPolicyService(config.Config{}, repo))
+
+	if err := svc.Seed(context.Background()); err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+
+	want := 0
+	for _, actions := range authorization.GetAccessibleRoles() {
+		want += len(actions)
+	}
+	if len(repo.permissions) != want {
+		t.Fatalf("permissions = %d, want %d", len(repo.permissions), want)
+	}
+	if _, ok := repo.grants[1]; ok {
+		t.Errorf("existing permission was granted again")
+	}
+	for id, p := range repo.permissions {
+		if id == 1 {
+			continue
+		}
+		wantRoles := authorization.GetAccessibleRoles()[p.Resource][p.Action]
+		if len(repo.grants[id]) != len(wantRoles) {
+			t.Errorf("grants of %s/%s = %v, want %v", p.Resource, p.Action, repo.grants[id], wantRoles)
+		}
+	}
+}
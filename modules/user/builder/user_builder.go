@@ -2,6 +2,10 @@ package builder
 
 import (
 	"tracerstudy-auth-service/common/config"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/mailer"
+	"tracerstudy-auth-service/common/password"
+	roleRepo "tracerstudy-auth-service/modules/role/repository"
 	"tracerstudy-auth-service/modules/user/handler"
 	"tracerstudy-auth-service/modules/user/repository"
 	"tracerstudy-auth-service/modules/user/service"
@@ -10,9 +14,13 @@ import (
 	"gorm.io/gorm"
 )
 
-func BuildUserHandler(cfg config.Config, db *gorm.DB, grpcConn *grpc.ClientConn) *handler.UserHandler {
+func BuildUserHandler(cfg config.Config, db *gorm.DB, jwtManager *commonJwt.JWT, passwordHasher password.Hasher, mailer mailer.Mailer, grpcConn *grpc.ClientConn) *handler.UserHandler {
 	userRepo := repository.NewUserRepository(db)
-	userSvc := service.NewUserService(cfg, userRepo)
+	userScopeRepo := repository.NewUserScopeRepository(db)
+	roleRepository := roleRepo.NewRoleRepository(db)
+	emailVerificationRepo := repository.NewEmailVerificationRepository(db)
+	emailVerificationSvc := service.NewEmailVerificationService(cfg, emailVerificationRepo, userRepo, mailer)
+	userSvc := service.NewUserService(cfg, userRepo, userScopeRepo, roleRepository, jwtManager, passwordHasher, emailVerificationSvc)
 
 	return handler.NewUserHandler(cfg, userSvc)
 }
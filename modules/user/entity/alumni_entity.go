@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+	"tracerstudy-auth-service/pb"
+)
+
+const (
+	AlumniTableName = "alumni"
+)
+
+// Alumni is the local identity of an alumnus, provisioned on their first
+// login. The biodata fields are a snapshot taken from the biodata API at
+// the latest login; the API stays the source of truth. SidangHash is the
+// password hash of the graduation date the alumnus was last verified with
+// at VerifiedAt, which lets them log in while the API is down. The date
+// itself is not kept, since it is the alumnus' login secret.
+type Alumni struct {
+	Id           uint64    `gorm:"primaryKey" json:"id"`
+	Nim          string    `gorm:"size:32;uniqueIndex" json:"nim"`
+	Nama         string    `gorm:"size:255" json:"nama"`
+	Kodeprodi    string    `gorm:"size:16" json:"kodeprodi"`
+	Prodi        string    `gorm:"size:255" json:"prodi"`
+	Kodefak      string    `gorm:"size:16" json:"kodefak"`
+	SidangHash   string    `gorm:"size:255" json:"-"`
+	VerifiedAt   time.Time `json:"verified_at"`
+	FirstLoginAt time.Time `json:"first_login_at"`
+	LastLoginAt  time.Time `json:"last_login_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func NewAlumni(biodata *pb.MhsBiodataApi, sidangHash string, loginAt time.Time) *Alumni {
+	return &Alumni{
+		Nim:          biodata.GetNIM(),
+		Nama:         biodata.GetNAMA(),
+		Kodeprodi:    biodata.GetKODEPST(),
+		Prodi:        biodata.GetNAMAPST(),
+		Kodefak:      biodata.GetKODEFAK(),
+		SidangHash:   sidangHash,
+		VerifiedAt:   loginAt,
+		FirstLoginAt: loginAt,
+		LastLoginAt:  loginAt,
+		CreatedAt:    loginAt,
+		UpdatedAt:    loginAt,
+	}
+}
+
+func (a *Alumni) TableName() string {
+	return AlumniTableName
+}
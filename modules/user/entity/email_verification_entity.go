@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	EmailVerificationTokenTableName = "email_verification_tokens"
+)
+
+// EmailVerificationToken proves control of Email, the address the user had
+// when the token was sent. Only the hash of the token is stored.
+type EmailVerificationToken struct {
+	Id        uint64     `json:"id"`
+	UserId    uint64     `gorm:"index" json:"user_id"`
+	Email     string     `json:"email"`
+	TokenHash string     `gorm:"size:64;uniqueIndex" json:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewEmailVerificationToken(userId uint64, email, tokenHash string, expiresAt time.Time) *EmailVerificationToken {
+	return &EmailVerificationToken{
+		UserId:    userId,
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (t *EmailVerificationToken) TableName() string {
+	return EmailVerificationTokenTableName
+}
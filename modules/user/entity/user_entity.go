@@ -12,15 +12,16 @@ const (
 )
 
 type User struct {
-	Id        uint64         `json:"id"`
-	Name      string         `json:"name"`
-	Username  string         `json:"username"`
-	Email     string         `json:"email"`
-	Password  string         `json:"password"`
-	RoleId    uint32         `json:"role_id"`
-	CreatedAt time.Time      `gorm:"type:timestamptz;not_null" json:"created_at"`
-	UpdatedAt time.Time      `gorm:"type:timestamptz;not_null" json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	Id              uint64         `json:"id"`
+	Name            string         `json:"name"`
+	Username        string         `json:"username"`
+	Email           string         `json:"email"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at"`
+	Password        string         `json:"-"`
+	RoleId          uint32         `json:"role_id"`
+	CreatedAt       time.Time      `gorm:"type:timestamptz;not_null" json:"created_at"`
+	UpdatedAt       time.Time      `gorm:"type:timestamptz;not_null" json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
 func NewUser(id uint64, name, username, email, password string, roleId uint32) *User {
@@ -40,15 +41,24 @@ func (u *User) TableName() string {
 	return UserTableName
 }
 
-func ConvertEntityToProto(u *User) *pb.User {
-	return &pb.User{
+func ConvertEntityToProto(u *User) *pb.UserView {
+	user := &pb.UserView{
 		Id:        u.Id,
 		Name:      u.Name,
 		Username:  u.Username,
 		Email:     u.Email,
-		Password:  u.Password,
 		RoleId:    u.RoleId,
 		CreatedAt: u.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: u.UpdatedAt.Format(time.RFC3339),
 	}
+
+	if u.EmailVerifiedAt != nil {
+		user.EmailVerifiedAt = u.EmailVerifiedAt.Format(time.RFC3339)
+	}
+
+	if u.DeletedAt.Valid {
+		user.DeletedAt = u.DeletedAt.Time.Format(time.RFC3339)
+	}
+
+	return user
 }
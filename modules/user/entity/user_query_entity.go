@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	UserSortById        = "id"
+	UserSortByName      = "name"
+	UserSortByUsername  = "username"
+	UserSortByEmail     = "email"
+	UserSortByCreatedAt = "created_at"
+	UserSortByUpdatedAt = "updated_at"
+)
+
+// UserQuery filters, orders and pages the users returned by FindAll. When
+// Cursor is set it takes precedence over Page.
+type UserQuery struct {
+	RoleId         uint32
+	Search         string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	IncludeDeleted bool
+	SortBy         string
+	SortDesc       bool
+	Page           int
+	Limit          int
+	Cursor         string
+}
+
+// UserCursor marks the last user of a page, so the next page can continue
+// after it without counting or skipping rows. Filters is a hash of the
+// filters of the query the cursor was issued for.
+type UserCursor struct {
+	SortBy   string `json:"s"`
+	SortDesc bool   `json:"d"`
+	Filters  string `json:"f"`
+	Value    string `json:"v"`
+	Id       uint64 `json:"i"`
+}
+
+type UserPage struct {
+	Users       []*User
+	TotalRows   int64
+	TotalPages  int64
+	CurrentPage int
+	NextCursor  string
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+	"tracerstudy-auth-service/pb"
+)
+
+const (
+	UserScopeTableName = "user_scopes"
+)
+
+// UserScope assigns a user to a study program (Kodeprodi), a faculty
+// (Kodefak), or a study program within a faculty.
+type UserScope struct {
+	Id        uint64    `gorm:"primaryKey" json:"id"`
+	UserId    uint64    `gorm:"index" json:"user_id"`
+	Kodeprodi string    `gorm:"size:16" json:"kodeprodi"`
+	Kodefak   string    `gorm:"size:16" json:"kodefak"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewUserScope(userId uint64, kodeprodi, kodefak string) *UserScope {
+	return &UserScope{
+		UserId:    userId,
+		Kodeprodi: kodeprodi,
+		Kodefak:   kodefak,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (s *UserScope) TableName() string {
+	return UserScopeTableName
+}
+
+func ConvertUserScopeEntityToProto(s *UserScope) *pb.UserScope {
+	return &pb.UserScope{
+		Kodeprodi: s.Kodeprodi,
+		Kodefak:   s.Kodefak,
+	}
+}
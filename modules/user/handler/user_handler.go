@@ -4,6 +4,8 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 	"tracerstudy-auth-service/common/config"
 	"tracerstudy-auth-service/common/errors"
 	"tracerstudy-auth-service/modules/user/entity"
@@ -12,7 +14,6 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type UserHandler struct {
@@ -28,20 +29,53 @@ func NewUserHandler(config config.Config, userService service.UserServiceUseCase
 	}
 }
 
-func (uh *UserHandler) GetAllUsers(ctx context.Context, req *emptypb.Empty) (*pb.GetAllUsersResponse, error) {
-	user, err := uh.userSvc.FindAll(ctx, req)
+func (uh *UserHandler) GetAllUsers(ctx context.Context, req *pb.GetAllUsersRequest) (*pb.GetAllUsersResponse, error) {
+	query := &entity.UserQuery{
+		RoleId:         req.GetRoleId(),
+		Search:         strings.TrimSpace(req.GetSearch()),
+		IncludeDeleted: req.GetIncludeDeleted(),
+		SortBy:         req.GetSortBy(),
+		Page:           int(req.GetPagination().GetPage()),
+		Limit:          int(req.GetPagination().GetLimit()),
+		Cursor:         req.GetCursor(),
+	}
+
+	switch strings.ToLower(req.GetSortOrder()) {
+	case "", "asc":
+	case "desc":
+		query.SortDesc = true
+	default:
+		log.Println("WARNING: [UserHandler - GetAllUsers] Invalid sort order:", req.GetSortOrder())
+		return &pb.GetAllUsersResponse{
+			Code:    uint32(http.StatusBadRequest),
+			Message: "sort_order must be asc or desc",
+		}, status.Errorf(codes.InvalidArgument, "sort_order must be asc or desc")
+	}
+
+	createdFrom, fromErr := parseOptionalTime(req.GetCreatedFrom())
+	createdTo, toErr := parseOptionalTime(req.GetCreatedTo())
+	if fromErr != nil || toErr != nil {
+		log.Println("WARNING: [UserHandler - GetAllUsers] Invalid created date range:", req.GetCreatedFrom(), req.GetCreatedTo())
+		return &pb.GetAllUsersResponse{
+			Code:    uint32(http.StatusBadRequest),
+			Message: "created_from and created_to must be RFC 3339 timestamps",
+		}, status.Errorf(codes.InvalidArgument, "created_from and created_to must be RFC 3339 timestamps")
+	}
+	query.CreatedFrom, query.CreatedTo = createdFrom, createdTo
+
+	page, err := uh.userSvc.FindAll(ctx, query)
 	if err != nil {
 		parseError := errors.ParseError(err)
 		log.Println("ERROR: [UserHandler - GetAllUser] Error while get all user: ", parseError.Message)
 		// return nil, status.Errorf(parseError.Code, parseError.Message)
 		return &pb.GetAllUsersResponse{
-			Code:    uint32(http.StatusInternalServerError),
+			Code:    parseError.HTTPStatus(),
 			Message: parseError.Message,
 		}, status.Errorf(parseError.Code, parseError.Message)
 	}
 
-	var userArr []*pb.User
-	for _, u := range user {
+	var userArr []*pb.UserView
+	for _, u := range page.Users {
 		userProto := entity.ConvertEntityToProto(u)
 		userArr = append(userArr, userProto)
 	}
@@ -50,6 +84,13 @@ func (uh *UserHandler) GetAllUsers(ctx context.Context, req *emptypb.Empty) (*pb
 		Code:    uint32(http.StatusOK),
 		Message: "get all user success",
 		Data:    userArr,
+		Pagination: &pb.Pagination{
+			TotalRows:   uint32(page.TotalRows),
+			TotalPages:  uint32(page.TotalPages),
+			CurrentPage: uint32(page.CurrentPage),
+			CurrentRows: uint32(len(page.Users)),
+		},
+		NextCursor: page.NextCursor,
 	}, nil
 }
 
@@ -82,7 +123,7 @@ func (uh *UserHandler) GetUserById(ctx context.Context, req *pb.GetUserByIdReque
 	}, nil
 }
 
-func (uh *UserHandler) CreateUser(ctx context.Context, req *pb.User) (*pb.GetUserResponse, error) {
+func (uh *UserHandler) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.GetUserResponse, error) {
 	user, err := uh.userSvc.Create(ctx, req.GetName(), req.GetUsername(), req.GetEmail(), req.GetPassword(), req.GetRoleId())
 
 	if err != nil {
@@ -90,9 +131,9 @@ func (uh *UserHandler) CreateUser(ctx context.Context, req *pb.User) (*pb.GetUse
 		log.Println("ERROR: [UserHandler - CreateUser] Error while create user: ", parseError.Message)
 		// return nil, status.Errorf(parseError.Code, parseError.Message)
 		return &pb.GetUserResponse{
-			Code:    uint32(http.StatusInternalServerError),
+			Code:    parseError.HTTPStatus(),
 			Message: parseError.Message,
-		}, status.Errorf(parseError.Code, parseError.Message)
+		}, errors.Status(err)
 	}
 
 	userProto := entity.ConvertEntityToProto(user)
@@ -104,7 +145,7 @@ func (uh *UserHandler) CreateUser(ctx context.Context, req *pb.User) (*pb.GetUse
 	}, nil
 }
 
-func (uh *UserHandler) UpdateUser(ctx context.Context, req *pb.User) (*pb.GetUserResponse, error) {
+func (uh *UserHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.GetUserResponse, error) {
 	userDataUpdate := &entity.User{
 		Name:     req.GetName(),
 		Username: req.GetUsername(),
@@ -119,7 +160,7 @@ func (uh *UserHandler) UpdateUser(ctx context.Context, req *pb.User) (*pb.GetUse
 		log.Println("ERROR: [UserHandler - UpdateUser] Error while update user: ", parseError.Message)
 		// return nil, status.Errorf(parseError.Code, parseError.Message)
 		return &pb.GetUserResponse{
-			Code:    uint32(http.StatusInternalServerError),
+			Code:    parseError.HTTPStatus(),
 			Message: parseError.Message,
 		}, status.Errorf(parseError.Code, parseError.Message)
 	}
@@ -150,3 +191,71 @@ func (uh *UserHandler) DeleteUser(ctx context.Context, req *pb.GetUserByIdReques
 		Message: "delete user success",
 	}, nil
 }
+
+func (uh *UserHandler) GetUserScopes(ctx context.Context, req *pb.GetUserByIdRequest) (*pb.UserScopesResponse, error) {
+	scopes, err := uh.userSvc.FindScopes(ctx, req.GetId())
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserHandler - GetUserScopes] Error while get user scopes:", parseError.Message)
+		return &pb.UserScopesResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.UserScopesResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "get user scopes success",
+		UserId:  req.GetId(),
+		Data:    convertUserScopes(scopes),
+	}, nil
+}
+
+func (uh *UserHandler) SetUserScopes(ctx context.Context, req *pb.SetUserScopesRequest) (*pb.UserScopesResponse, error) {
+	var scopeDataUpdate []*entity.UserScope
+	for _, s := range req.GetScopes() {
+		scopeDataUpdate = append(scopeDataUpdate, &entity.UserScope{
+			Kodeprodi: s.GetKodeprodi(),
+			Kodefak:   s.GetKodefak(),
+		})
+	}
+
+	scopes, err := uh.userSvc.SetScopes(ctx, req.GetUserId(), scopeDataUpdate)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserHandler - SetUserScopes] Error while set user scopes:", parseError.Message)
+		return &pb.UserScopesResponse{
+			Code:    parseError.HTTPStatus(),
+			Message: parseError.Message,
+		}, status.Errorf(parseError.Code, parseError.Message)
+	}
+
+	return &pb.UserScopesResponse{
+		Code:    uint32(http.StatusOK),
+		Message: "set user scopes success",
+		UserId:  req.GetUserId(),
+		Data:    convertUserScopes(scopes),
+	}, nil
+}
+
+func convertUserScopes(scopes []*entity.UserScope) []*pb.UserScope {
+	var scopeArr []*pb.UserScope
+	for _, s := range scopes {
+		scopeArr = append(scopeArr, entity.ConvertUserScopeEntityToProto(s))
+	}
+
+	return scopeArr
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"tracerstudy-auth-service/modules/user/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AlumniRepository struct {
+	db *gorm.DB
+}
+
+func NewAlumniRepository(db *gorm.DB) *AlumniRepository {
+	return &AlumniRepository{
+		db: db,
+	}
+}
+
+type AlumniRepositoryUseCase interface {
+	FindById(ctx context.Context, id uint64) (*entity.Alumni, error)
+	FindByNim(ctx context.Context, nim string) (*entity.Alumni, error)
+	Upsert(ctx context.Context, req *entity.Alumni) (*entity.Alumni, error)
+	TouchLogin(ctx context.Context, id uint64, at time.Time) error
+}
+
+func (r *AlumniRepository) FindById(ctx context.Context, id uint64) (*entity.Alumni, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "AlumniRepository - FindById")
+	defer span.End()
+
+	var alumni entity.Alumni
+	if err := r.db.Debug().WithContext(ctxSpan).Where("id = ?", id).First(&alumni).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [AlumniRepository - FindById] Record not found for id", id)
+			return nil, status.Errorf(codes.NotFound, "record not found for id %d", id)
+		}
+		log.Println("ERROR: [AlumniRepository - FindById] Internal server error:", err)
+		return nil, err
+	}
+
+	return &alumni, nil
+}
+
+func (r *AlumniRepository) FindByNim(ctx context.Context, nim string) (*entity.Alumni, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "AlumniRepository - FindByNim")
+	defer span.End()
+
+	var alumni entity.Alumni
+	if err := r.db.Debug().WithContext(ctxSpan).Where("nim = ?", nim).First(&alumni).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [AlumniRepository - FindByNim] Record not found for nim", nim)
+			return nil, status.Errorf(codes.NotFound, "record not found for nim %s", nim)
+		}
+		log.Println("ERROR: [AlumniRepository - FindByNim] Internal server error:", err)
+		return nil, err
+	}
+
+	return &alumni, nil
+}
+
+// Upsert creates the alumni on their first login. Later logins refresh the
+// biodata snapshot and the last login time, keeping the id and the first
+// login time.
+func (r *AlumniRepository) Upsert(ctx context.Context, req *entity.Alumni) (*entity.Alumni, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "AlumniRepository - Upsert")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"nama", "kodeprodi", "prodi", "kodefak", "sidang_hash", "verified_at", "last_login_at", "updated_at"}),
+	}).Create(req).Error; err != nil {
+		log.Println("ERROR: [AlumniRepository - Upsert] Internal server error:", err)
+		return nil, err
+	}
+
+	// the insert id is not reported when an existing row was updated
+	return r.FindByNim(ctxSpan, req.Nim)
+}
+
+func (r *AlumniRepository) TouchLogin(ctx context.Context, id uint64, at time.Time) error {
+	ctxSpan, span := trace.StartSpan(ctx, "AlumniRepository - TouchLogin")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Model(&entity.Alumni{}).Where("id = ?", id).Update("last_login_at", at).Error; err != nil {
+		log.Println("ERROR: [AlumniRepository - TouchLogin] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"tracerstudy-auth-service/modules/user/entity"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type EmailVerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationRepository(db *gorm.DB) *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		db: db,
+	}
+}
+
+type EmailVerificationRepositoryUseCase interface {
+	FindByHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error)
+	FindLatestByUserId(ctx context.Context, userId uint64) (*entity.EmailVerificationToken, error)
+	Create(ctx context.Context, req *entity.EmailVerificationToken) (*entity.EmailVerificationToken, error)
+	MarkUsed(ctx context.Context, id uint64) (bool, error)
+	InvalidateUser(ctx context.Context, userId uint64) error
+}
+
+func (r *EmailVerificationRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "EmailVerificationRepository - FindByHash")
+	defer span.End()
+
+	var token entity.EmailVerificationToken
+	if err := r.db.Debug().WithContext(ctxSpan).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("WARNING: [EmailVerificationRepository - FindByHash] Record not found for verification token")
+			return nil, status.Errorf(codes.NotFound, "record not found for verification token")
+		}
+		log.Println("ERROR: [EmailVerificationRepository - FindByHash] Internal server error:", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *EmailVerificationRepository) FindLatestByUserId(ctx context.Context, userId uint64) (*entity.EmailVerificationToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "EmailVerificationRepository - FindLatestByUserId")
+	defer span.End()
+
+	var token entity.EmailVerificationToken
+	if err := r.db.Debug().WithContext(ctxSpan).Where("user_id = ?", userId).Order("created_at DESC").First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "record not found for verification token of user %d", userId)
+		}
+		log.Println("ERROR: [EmailVerificationRepository - FindLatestByUserId] Internal server error:", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *EmailVerificationRepository) Create(ctx context.Context, req *entity.EmailVerificationToken) (*entity.EmailVerificationToken, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "EmailVerificationRepository - Create")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Create(req).Error; err != nil {
+		log.Println("ERROR: [EmailVerificationRepository - Create] Internal server error:", err)
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// MarkUsed consumes the token. It reports false when the token had already
+// been used.
+func (r *EmailVerificationRepository) MarkUsed(ctx context.Context, id uint64) (bool, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "EmailVerificationRepository - MarkUsed")
+	defer span.End()
+
+	res := r.db.Debug().WithContext(ctxSpan).Model(&entity.EmailVerificationToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+	if res.Error != nil {
+		log.Println("ERROR: [EmailVerificationRepository - MarkUsed] Internal server error:", res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// InvalidateUser consumes every outstanding token of the user.
+func (r *EmailVerificationRepository) InvalidateUser(ctx context.Context, userId uint64) error {
+	ctxSpan, span := trace.StartSpan(ctx, "EmailVerificationRepository - InvalidateUser")
+	defer span.End()
+
+	if err := r.db.Debug().WithContext(ctxSpan).Model(&entity.EmailVerificationToken{}).Where("user_id = ? AND used_at IS NULL", userId).Update("used_at", time.Now()).Error; err != nil {
+		log.Println("ERROR: [EmailVerificationRepository - InvalidateUser] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
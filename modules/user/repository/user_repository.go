@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"log"
+	"strings"
+	"time"
 	"tracerstudy-auth-service/modules/user/entity"
 
 	"go.opencensus.io/trace"
@@ -12,6 +14,8 @@ import (
 	"gorm.io/gorm"
 )
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type UserRepository struct {
 	db *gorm.DB
 }
@@ -23,21 +27,43 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 }
 
 type UserRepositoryUseCase interface {
-	FindAll(ctx context.Context, req any) ([]*entity.User, error)
+	FindAll(ctx context.Context, query *entity.UserQuery, after *entity.UserCursor) ([]*entity.User, error)
+	Count(ctx context.Context, query *entity.UserQuery) (int64, error)
 	FindByUsername(ctx context.Context, username string) (*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
 	FindById(ctx context.Context, id uint64) (*entity.User, error)
 	Create(ctx context.Context, req *entity.User) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User, updatedFields map[string]interface{}) (*entity.User, error)
 	Delete(ctx context.Context, id uint64) error
+	MarkEmailVerified(ctx context.Context, id uint64, email string) (bool, error)
 }
 
-func (u *UserRepository) FindAll(ctx context.Context, req any) ([]*entity.User, error) {
+// FindAll returns the users matching query, starting after the given cursor
+// when there is one and at query.Page otherwise. It returns up to one user
+// more than query.Limit, so callers can tell whether another page follows.
+func (u *UserRepository) FindAll(ctx context.Context, query *entity.UserQuery, after *entity.UserCursor) ([]*entity.User, error) {
 	ctxSpan, span := trace.StartSpan(ctx, "UserRepository - FindAll")
 	defer span.End()
 
+	direction := "ASC"
+	if query.SortDesc {
+		direction = "DESC"
+	}
+
+	db := u.filter(u.db.Debug().WithContext(ctxSpan), query)
+
+	if after != nil {
+		db = u.after(db, query, after)
+	} else if query.Page > 1 {
+		db = db.Offset((query.Page - 1) * query.Limit)
+	}
+
+	if query.SortBy != entity.UserSortById {
+		db = db.Order(query.SortBy + " " + direction)
+	}
+
 	var users []*entity.User
-	if err := u.db.Debug().WithContext(ctxSpan).Find(&users).Error; err != nil {
+	if err := db.Order("id " + direction).Limit(query.Limit + 1).Find(&users).Error; err != nil {
 		log.Println("ERROR: [UserRepository - FindAll] Internal server error:", err)
 		return nil, err
 	}
@@ -45,6 +71,66 @@ func (u *UserRepository) FindAll(ctx context.Context, req any) ([]*entity.User,
 	return users, nil
 }
 
+func (u *UserRepository) Count(ctx context.Context, query *entity.UserQuery) (int64, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "UserRepository - Count")
+	defer span.End()
+
+	var count int64
+	if err := u.filter(u.db.Debug().WithContext(ctxSpan), query).Model(&entity.User{}).Count(&count).Error; err != nil {
+		log.Println("ERROR: [UserRepository - Count] Internal server error:", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (u *UserRepository) filter(db *gorm.DB, query *entity.UserQuery) *gorm.DB {
+	if query.IncludeDeleted {
+		db = db.Unscoped()
+	}
+
+	if query.RoleId != 0 {
+		db = db.Where("role_id = ?", query.RoleId)
+	}
+
+	if query.Search != "" {
+		pattern := "%" + likeEscaper.Replace(query.Search) + "%"
+		db = db.Where("(name LIKE ? OR username LIKE ? OR email LIKE ?)", pattern, pattern, pattern)
+	}
+
+	if query.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *query.CreatedFrom)
+	}
+
+	if query.CreatedTo != nil {
+		db = db.Where("created_at <= ?", *query.CreatedTo)
+	}
+
+	return db
+}
+
+// after keeps the users that sort after the cursor, breaking ties on id.
+// The sort column comes from a fixed list, never from the request itself.
+func (u *UserRepository) after(db *gorm.DB, query *entity.UserQuery, cursor *entity.UserCursor) *gorm.DB {
+	op := ">"
+	if query.SortDesc {
+		op = "<"
+	}
+
+	if query.SortBy == entity.UserSortById {
+		return db.Where("id "+op+" ?", cursor.Id)
+	}
+
+	var value interface{} = cursor.Value
+	if query.SortBy == entity.UserSortByCreatedAt || query.SortBy == entity.UserSortByUpdatedAt {
+		t, _ := time.Parse(time.RFC3339Nano, cursor.Value)
+		value = t
+	}
+
+	column := query.SortBy
+	return db.Where("("+column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?))", value, value, cursor.Id)
+}
+
 func (u *UserRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
 	ctxSpan, span := trace.StartSpan(ctx, "UserRepository - FindByUsername")
 	defer span.End()
@@ -131,3 +217,18 @@ func (u *UserRepository) Delete(ctx context.Context, id uint64) error {
 
 	return nil
 }
+
+// MarkEmailVerified records that the user verified email. It reports false
+// when email is no longer the user's address.
+func (u *UserRepository) MarkEmailVerified(ctx context.Context, id uint64, email string) (bool, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "UserRepository - MarkEmailVerified")
+	defer span.End()
+
+	res := u.db.Debug().WithContext(ctxSpan).Model(&entity.User{}).Where("id = ? AND email = ?", id, email).Update("email_verified_at", time.Now())
+	if res.Error != nil {
+		log.Println("ERROR: [UserRepository - MarkEmailVerified] Internal server error:", res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
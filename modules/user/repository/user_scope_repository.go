@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"tracerstudy-auth-service/modules/user/entity"
+
+	"go.opencensus.io/trace"
+	"gorm.io/gorm"
+)
+
+type UserScopeRepository struct {
+	db *gorm.DB
+}
+
+func NewUserScopeRepository(db *gorm.DB) *UserScopeRepository {
+	return &UserScopeRepository{
+		db: db,
+	}
+}
+
+type UserScopeRepositoryUseCase interface {
+	FindByUserId(ctx context.Context, userId uint64) ([]*entity.UserScope, error)
+	SetUserScopes(ctx context.Context, userId uint64, scopes []*entity.UserScope) error
+}
+
+func (r *UserScopeRepository) FindByUserId(ctx context.Context, userId uint64) ([]*entity.UserScope, error) {
+	ctxSpan, span := trace.StartSpan(ctx, "UserScopeRepository - FindByUserId")
+	defer span.End()
+
+	var scopes []*entity.UserScope
+	if err := r.db.Debug().WithContext(ctxSpan).Where("user_id = ?", userId).Order("id").Find(&scopes).Error; err != nil {
+		log.Println("ERROR: [UserScopeRepository - FindByUserId] Internal server error:", err)
+		return nil, err
+	}
+
+	return scopes, nil
+}
+
+// SetUserScopes replaces every scope of the user with scopes.
+func (r *UserScopeRepository) SetUserScopes(ctx context.Context, userId uint64, scopes []*entity.UserScope) error {
+	ctxSpan, span := trace.StartSpan(ctx, "UserScopeRepository - SetUserScopes")
+	defer span.End()
+
+	err := r.db.Debug().WithContext(ctxSpan).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&entity.UserScope{}).Error; err != nil {
+			return err
+		}
+		if len(scopes) == 0 {
+			return nil
+		}
+		return tx.Create(&scopes).Error
+	})
+	if err != nil {
+		log.Println("ERROR: [UserScopeRepository - SetUserScopes] Internal server error:", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/common/password"
+	"tracerstudy-auth-service/modules/user/entity"
+	"tracerstudy-auth-service/modules/user/repository"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AlumniService struct {
+	cfg              config.Config
+	alumniRepository repository.AlumniRepositoryUseCase
+	passwordHasher   password.Hasher
+}
+
+type AlumniServiceUseCase interface {
+	FindById(ctx context.Context, id uint64) (*entity.Alumni, error)
+	Provision(ctx context.Context, biodata *pb.MhsBiodataApi, tanggalSidang string) (*entity.Alumni, error)
+	LoginFromCache(ctx context.Context, nim, tanggalSidang string) (*entity.Alumni, error)
+}
+
+func NewAlumniService(cfg config.Config, alumniRepository repository.AlumniRepositoryUseCase, passwordHasher password.Hasher) *AlumniService {
+	return &AlumniService{
+		cfg:              cfg,
+		alumniRepository: alumniRepository,
+		passwordHasher:   passwordHasher,
+	}
+}
+
+func (svc *AlumniService) FindById(ctx context.Context, id uint64) (*entity.Alumni, error) {
+	res, err := svc.alumniRepository.FindById(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AlumniService - FindById] Error while find alumni by id:", parseError.Message)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Provision records a login of the alumni described by biodata, who were
+// just verified with tanggalSidang, creating their local identity the first
+// time.
+func (svc *AlumniService) Provision(ctx context.Context, biodata *pb.MhsBiodataApi, tanggalSidang string) (*entity.Alumni, error) {
+	sidangHash, err := svc.passwordHasher.Hash(tanggalSidang)
+	if err != nil {
+		log.Println("ERROR: [AlumniService - Provision] Error while hashing tanggal sidang:", err)
+		return nil, status.Errorf(codes.Internal, "failed to hash tanggal sidang")
+	}
+
+	res, err := svc.alumniRepository.Upsert(ctx, entity.NewAlumni(biodata, sidangHash, time.Now()))
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AlumniService - Provision] Error while upsert alumni:", parseError.Message)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// LoginFromCache records a login of an alumnus from their local record,
+// for when the biodata API cannot verify them. It returns nil when they
+// have no usable record, that is none verified within the degraded max
+// age, and PermissionDenied when tanggalSidang does not match the record.
+func (svc *AlumniService) LoginFromCache(ctx context.Context, nim, tanggalSidang string) (*entity.Alumni, error) {
+	alumni, err := svc.alumniRepository.FindByNim(ctx, nim)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			return nil, nil
+		}
+		log.Println("ERROR: [AlumniService - LoginFromCache] Error while find alumni by nim:", parseError.Message)
+		return nil, err
+	}
+
+	if alumni.SidangHash == "" {
+		return nil, nil
+	}
+
+	if time.Since(alumni.VerifiedAt) > svc.cfg.Biodata.DegradedMaxAge {
+		log.Println("INFO: [AlumniService - LoginFromCache] Verification too old for alumni", alumni.Id)
+		return nil, nil
+	}
+
+	match, _, err := svc.passwordHasher.Verify(tanggalSidang, alumni.SidangHash)
+	if err != nil {
+		log.Println("ERROR: [AlumniService - LoginFromCache] Error while verifying tanggal sidang:", err)
+		return nil, status.Errorf(codes.Internal, "failed to verify tanggal sidang")
+	}
+
+	if !match {
+		log.Println("WARNING: [AlumniService - LoginFromCache] Tanggal sidang does not match for alumni", alumni.Id)
+		return nil, status.Errorf(codes.PermissionDenied, "nim or tanggal sidang is incorrect")
+	}
+
+	now := time.Now()
+	if err := svc.alumniRepository.TouchLogin(ctx, alumni.Id, now); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [AlumniService - LoginFromCache] Error while recording login:", parseError.Message)
+		return nil, err
+	}
+	alumni.LastLoginAt = now
+
+	return alumni, nil
+}
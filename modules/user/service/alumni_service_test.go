@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/password"
+	"tracerstudy-auth-service/modules/user/entity"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeAlumniRepository struct {
+	alumni  map[string]*entity.Alumni
+	touched bool
+}
+
+func (r *fakeAlumniRepository) FindById(ctx context.Context, id uint64) (*entity.Alumni, error) {
+	for _, a := range r.alumni {
+		if a.Id == id {
+			return a, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "alumni not found")
+}
+
+func (r *fakeAlumniRepository) FindByNim(ctx context.Context, nim string) (*entity.Alumni, error) {
+	a, ok := r.alumni[nim]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "alumni not found")
+	}
+	return a, nil
+}
+
+func (r *fakeAlumniRepository) Upsert(ctx context.Context, req *entity.Alumni) (*entity.Alumni, error) {
+	req.Id = uint64(len(r.alumni) + 1)
+	r.alumni[req.Nim] = req
+	return req, nil
+}
+
+func (r *fakeAlumniRepository) TouchLogin(ctx context.Context, id uint64, at time.Time) error {
+	r.touched = true
+	return nil
+}
+
+func newTestAlumniService(t *testing.T, repo *fakeAlumniRepository) *AlumniService {
+	t.Helper()
+
+	hasher, err := password.NewHasher(config.Password{
+		HashAlgorithm:     password.AlgorithmArgon2id,
+		Argon2Memory:      64,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+
+	cfg := config.Config{Biodata: config.Biodata{DegradedMaxAge: 24 * time.Hour}}
+	return NewAlumniService(cfg, repo, hasher)
+}
+
+func TestAlumniServiceProvision(t *testing.T) {
+	repo := &fakeAlumniRepository{alumni: make(map[string]*entity.Alumni)}
+	svc := newTestAlumniService(t, repo)
+
+	biodata := &pb.MhsBiodataApi{NIM: "1911521001", NAMA: "Siti", KODEPST: "0401", NAMAPST: "Informatika", KODEFAK: "04"}
+	first, err := svc.Provision(context.Background(), biodata, "2023-08-17")
+	if err != nil {
+		t.Fatalf("Provision() error = %v", err)
+	}
+	second, err := svc.Provision(context.Background(), &pb.MhsBiodataApi{NIM: "1911521002"}, "2023-08-17")
+	if err != nil {
+		t.Fatalf("Provision() error = %v", err)
+	}
+
+	if first.Id == 0 || repo.alumni[biodata.NIM] != first {
+		t.Errorf("Provision() = %+v, want a stored alumni", first)
+	}
+	if first.Nama != "Siti" || first.Kodeprodi != "0401" || first.Prodi != "Informatika" || first.Kodefak != "04" {
+		t.Errorf("Provision() = %+v, want the biodata snapshot", first)
+	}
+	if first.VerifiedAt.IsZero() || !first.LastLoginAt.Equal(first.VerifiedAt) {
+		t.Errorf("Provision() verified at %v, last login at %v, want the login time", first.VerifiedAt, first.LastLoginAt)
+	}
+	if !strings.HasPrefix(first.SidangHash, "$argon2id$") || strings.Contains(first.SidangHash, "2023") {
+		t.Errorf("SidangHash = %q, want an argon2id hash", first.SidangHash)
+	}
+	// there are few sidang dates, so equal dates must not give equal hashes
+	if first.SidangHash == second.SidangHash {
+		t.Errorf("SidangHash of equal dates is equal, want salted hashes")
+	}
+}
+
+func TestAlumniServiceLoginFromCache(t *testing.T) {
+	const (
+		nim           = "1911521001"
+		tanggalSidang = "2023-08-17"
+	)
+
+	tests := []struct {
+		name          string
+		nim           string
+		tanggalSidang string
+		modify        func(*entity.Alumni)
+		wantAlumni    bool
+		wantCode      codes.Code
+	}{
+		{
+			name:          "matching tanggal sidang",
+			nim:           nim,
+			tanggalSidang: tanggalSidang,
+			wantAlumni:    true,
+		},
+		{
+			name:          "wrong tanggal sidang",
+			nim:           nim,
+			tanggalSidang: "2023-08-18",
+			wantCode:      codes.PermissionDenied,
+		},
+		{
+			name:          "unknown nim",
+			nim:           "1911521999",
+			tanggalSidang: tanggalSidang,
+		},
+		{
+			name:          "verification too old",
+			nim:           nim,
+			tanggalSidang: tanggalSidang,
+			modify:        func(a *entity.Alumni) { a.VerifiedAt = time.Now().Add(-48 * time.Hour) },
+		},
+		{
+			name:          "no tanggal sidang recorded",
+			nim:           nim,
+			tanggalSidang: tanggalSidang,
+			modify:        func(a *entity.Alumni) { a.SidangHash = "" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAlumniRepository{alumni: make(map[string]*entity.Alumni)}
+			svc := newTestAlumniService(t, repo)
+
+			alumni, err := svc.Provision(context.Background(), &pb.MhsBiodataApi{NIM: nim}, tanggalSidang)
+			if err != nil {
+				t.Fatalf("Provision() error = %v", err)
+			}
+			if tt.modify != nil {
+				tt.modify(alumni)
+			}
+
+			got, err := svc.LoginFromCache(context.Background(), tt.nim, tt.tanggalSidang)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("LoginFromCache() error = %v, want %v", err, tt.wantCode)
+			}
+			if (got != nil) != tt.wantAlumni {
+				t.Errorf("LoginFromCache() = %v, want an alumni %v", got, tt.wantAlumni)
+			}
+			if repo.touched != tt.wantAlumni {
+				t.Errorf("login recorded = %v, want %v", repo.touched, tt.wantAlumni)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/errors"
+	"tracerstudy-auth-service/common/mailer"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/user/entity"
+	"tracerstudy-auth-service/modules/user/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	emailVerificationTokenSize   = 32
+	emailVerificationMailTimeout = 30 * time.Second
+)
+
+type EmailVerificationService struct {
+	cfg                         config.Config
+	emailVerificationRepository repository.EmailVerificationRepositoryUseCase
+	userRepository              repository.UserRepositoryUseCase
+	mailer                      mailer.Mailer
+}
+
+type EmailVerificationServiceUseCase interface {
+	Send(ctx context.Context, user *entity.User) error
+	Resend(ctx context.Context, email string) error
+	Verify(ctx context.Context, token string) error
+}
+
+func NewEmailVerificationService(cfg config.Config, emailVerificationRepository repository.EmailVerificationRepositoryUseCase, userRepository repository.UserRepositoryUseCase, mailer mailer.Mailer) *EmailVerificationService {
+	return &EmailVerificationService{
+		cfg:                         cfg,
+		emailVerificationRepository: emailVerificationRepository,
+		userRepository:              userRepository,
+		mailer:                      mailer,
+	}
+}
+
+// Send mails a verification link for the user's current email address,
+// replacing any link sent before. The mail is sent in the background.
+func (svc *EmailVerificationService) Send(ctx context.Context, user *entity.User) error {
+	if err := svc.emailVerificationRepository.InvalidateUser(ctx, user.Id); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [EmailVerificationService - Send] Error while invalidating verification tokens:", parseError.Message)
+		return err
+	}
+
+	token, err := utils.GenerateRandomToken(emailVerificationTokenSize)
+	if err != nil {
+		log.Println("ERROR: [EmailVerificationService - Send] Error while generating verification token:", err)
+		return status.Errorf(codes.Internal, "failed to generate verification token")
+	}
+
+	verificationToken := entity.NewEmailVerificationToken(user.Id, user.Email, utils.HashToken(token), time.Now().Add(svc.cfg.Email.TokenDuration))
+	if _, err := svc.emailVerificationRepository.Create(ctx, verificationToken); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [EmailVerificationService - Send] Error while create verification token:", parseError.Message)
+		return err
+	}
+
+	msg := &mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your Tracer Study email address",
+		Body:    svc.verificationMailBody(user.Name, token),
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), emailVerificationMailTimeout)
+		defer cancel()
+
+		if err := svc.mailer.Send(ctx, msg); err != nil {
+			log.Println("ERROR: [EmailVerificationService - Send] Error while sending verification mail:", err)
+		}
+	}()
+
+	return nil
+}
+
+// Resend mails a new link to the unverified user registered with email,
+// unless they were sent one within the resend interval.
+func (svc *EmailVerificationService) Resend(ctx context.Context, email string) error {
+	user, err := svc.userRepository.FindByEmail(ctx, email)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			log.Println("INFO: [EmailVerificationService - Resend] Verification requested for unknown email")
+			return nil
+		}
+		log.Println("ERROR: [EmailVerificationService - Resend] Error while find user by email:", parseError.Message)
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	latest, err := svc.emailVerificationRepository.FindLatestByUserId(ctx, user.Id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code != codes.NotFound {
+			log.Println("ERROR: [EmailVerificationService - Resend] Error while find latest verification token:", parseError.Message)
+			return err
+		}
+	} else if time.Since(latest.CreatedAt) < svc.cfg.Email.ResendInterval {
+		log.Println("INFO: [EmailVerificationService - Resend] Verification resent too soon for user", user.Id)
+		return nil
+	}
+
+	return svc.Send(ctx, user)
+}
+
+// Verify marks the address a token was sent to as verified, provided it is
+// still the user's address.
+func (svc *EmailVerificationService) Verify(ctx context.Context, token string) error {
+	invalid := status.Errorf(codes.InvalidArgument, "verification token is invalid or has expired")
+
+	verificationToken, err := svc.emailVerificationRepository.FindByHash(ctx, utils.HashToken(token))
+	if err != nil {
+		parseError := errors.ParseError(err)
+		if parseError.Code == codes.NotFound {
+			return invalid
+		}
+		log.Println("ERROR: [EmailVerificationService - Verify] Error while find verification token:", parseError.Message)
+		return err
+	}
+
+	if verificationToken.UsedAt != nil || time.Now().After(verificationToken.ExpiresAt) {
+		log.Println("WARNING: [EmailVerificationService - Verify] Used or expired verification token for user", verificationToken.UserId)
+		return invalid
+	}
+
+	marked, err := svc.emailVerificationRepository.MarkUsed(ctx, verificationToken.Id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [EmailVerificationService - Verify] Error while marking verification token as used:", parseError.Message)
+		return err
+	}
+
+	if !marked {
+		log.Println("WARNING: [EmailVerificationService - Verify] Verification token used concurrently for user", verificationToken.UserId)
+		return invalid
+	}
+
+	verified, err := svc.userRepository.MarkEmailVerified(ctx, verificationToken.UserId, verificationToken.Email)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [EmailVerificationService - Verify] Error while marking email as verified:", parseError.Message)
+		return err
+	}
+
+	if !verified {
+		log.Println("WARNING: [EmailVerificationService - Verify] Email changed since the token was sent for user", verificationToken.UserId)
+		return invalid
+	}
+
+	return nil
+}
+
+func (svc *EmailVerificationService) verificationMailBody(name, token string) string {
+	return fmt.Sprintf(
+		"Hello %s,\n\nPlease confirm this email address for your Tracer Study account by opening the link below within %s:\n\n%s\n\nIf you did not create an account, you can ignore this email.\n",
+		name, svc.cfg.Email.TokenDuration, mailer.Link(svc.cfg.Email.VerifyURL, token),
+	)
+}
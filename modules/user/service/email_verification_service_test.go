@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	"tracerstudy-auth-service/common/mailer"
+	"tracerstudy-auth-service/common/utils"
+	"tracerstudy-auth-service/modules/user/entity"
+	"tracerstudy-auth-service/modules/user/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeEmailVerificationRepository struct {
+	repository.EmailVerificationRepositoryUseCase
+	tokens      []*entity.EmailVerificationToken
+	invalidated int
+}
+
+func (r *fakeEmailVerificationRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.EmailVerificationToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "verification token not found")
+}
+
+func (r *fakeEmailVerificationRepository) FindLatestByUserId(ctx context.Context, userId uint64) (*entity.EmailVerificationToken, error) {
+	var latest *entity.EmailVerificationToken
+	for _, t := range r.tokens {
+		if t.UserId == userId && (latest == nil || t.CreatedAt.After(latest.CreatedAt)) {
+			latest = t
+		}
+	}
+	if latest == nil {
+		return nil, status.Errorf(codes.NotFound, "verification token not found")
+	}
+	return latest, nil
+}
+
+func (r *fakeEmailVerificationRepository) Create(ctx context.Context, req *entity.EmailVerificationToken) (*entity.EmailVerificationToken, error) {
+	req.Id = uint64(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, req)
+	return req, nil
+}
+
+func (r *fakeEmailVerificationRepository) MarkUsed(ctx context.Context, id uint64) (bool, error) {
+	for _, t := range r.tokens {
+		if t.Id == id && t.UsedAt == nil {
+			now := time.Now()
+			t.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeEmailVerificationRepository) InvalidateUser(ctx context.Context, userId uint64) error {
+	r.invalidated++
+	return nil
+}
+
+type fakeMailer struct {
+	sent chan *mailer.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	m.sent <- msg
+	return nil
+}
+
+func TestEmailVerificationServiceResend(t *testing.T) {
+	const email = "budi@example.com"
+	verified := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		email       string
+		verifiedAt  *time.Time
+		previousAge time.Duration
+		wantToken   bool
+	}{
+		{
+			name:      "first request",
+			email:     email,
+			wantToken: true,
+		},
+		{
+			name:        "request within the resend interval",
+			email:       email,
+			previousAge: 30 * time.Second,
+		},
+		{
+			name:        "request after the resend interval",
+			email:       email,
+			previousAge: 2 * time.Minute,
+			wantToken:   true,
+		},
+		{
+			name:       "already verified",
+			email:      email,
+			verifiedAt: &verified,
+		},
+		{
+			name:  "unknown email",
+			email: "siti@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeEmailVerificationRepository{}
+			if tt.previousAge != 0 {
+				repo.tokens = append(repo.tokens, &entity.EmailVerificationToken{Id: 1, UserId: 1, Email: email, CreatedAt: time.Now().Add(-tt.previousAge)})
+			}
+			users := &fakeUserRepository{users: []*entity.User{{Id: 1, Name: "Budi", Email: email, EmailVerifiedAt: tt.verifiedAt}}}
+			mail := &fakeMailer{sent: make(chan *mailer.Message, 1)}
+
+			cfg := config.Config{Email: config.EmailVerification{
+				VerifyURL:      "https://tracer.example.com/verify",
+				TokenDuration:  24 * time.Hour,
+				ResendInterval: time.Minute,
+			}}
+			svc := NewEmailVerificationService(cfg, repo, users, mail)
+
+			before := len(repo.tokens)
+			if err := svc.Resend(context.Background(), tt.email); err != nil {
+				t.Fatalf("Resend() error = %v", err)
+			}
+
+			if created := len(repo.tokens) > before; created != tt.wantToken {
+				t.Fatalf("token created = %v, want %v", created, tt.wantToken)
+			}
+			if invalidated := repo.invalidated > 0; invalidated != tt.wantToken {
+				t.Errorf("previous tokens invalidated = %v, want %v", invalidated, tt.wantToken)
+			}
+
+			if !tt.wantToken {
+				select {
+				case msg := <-mail.sent:
+					t.Errorf("mail sent to %s, want none", msg.To)
+				case <-time.After(50 * time.Millisecond):
+				}
+				return
+			}
+
+			select {
+			case msg := <-mail.sent:
+				if msg.To != email || !strings.Contains(msg.Body, cfg.Email.VerifyURL+"?token=") {
+					t.Errorf("mail = %+v, want a verification link to %s", msg, email)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("no mail sent")
+			}
+		})
+	}
+}
+
+func TestEmailVerificationServiceVerify(t *testing.T) {
+	const (
+		token = "verification-token"
+		email = "budi@example.com"
+	)
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name         string
+		modify       func(*entity.EmailVerificationToken)
+		token        string
+		currentEmail string
+		wantCode     codes.Code
+		wantVerified bool
+	}{
+		{
+			name:         "valid token",
+			token:        token,
+			currentEmail: email,
+			wantVerified: true,
+		},
+		{
+			name:         "unknown token",
+			token:        "other-token",
+			currentEmail: email,
+			wantCode:     codes.InvalidArgument,
+		},
+		{
+			name:         "used token",
+			modify:       func(v *entity.EmailVerificationToken) { v.UsedAt = &past },
+			token:        token,
+			currentEmail: email,
+			wantCode:     codes.InvalidArgument,
+		},
+		{
+			name:         "expired token",
+			modify:       func(v *entity.EmailVerificationToken) { v.ExpiresAt = past },
+			token:        token,
+			currentEmail: email,
+			wantCode:     codes.InvalidArgument,
+		},
+		{
+			name:         "email changed since the token was sent",
+			token:        token,
+			currentEmail: "budi.new@example.com",
+			wantCode:     codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verificationToken := entity.NewEmailVerificationToken(1, email, utils.HashToken(token), time.Now().Add(time.Hour))
+			verificationToken.Id = 1
+			if tt.modify != nil {
+				tt.modify(verificationToken)
+			}
+			repo := &fakeEmailVerificationRepository{tokens: []*entity.EmailVerificationToken{verificationToken}}
+			user := &entity.User{Id: 1, Email: tt.currentEmail}
+			svc := NewEmailVerificationService(config.Config{}, repo, &fakeUserRepository{users: []*entity.User{user}}, &fakeMailer{})
+
+			err := svc.Verify(context.Background(), tt.token)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantCode)
+			}
+			if verified := user.EmailVerifiedAt != nil; verified != tt.wantVerified {
+				t.Errorf("email verified = %v, want %v", verified, tt.wantVerified)
+			}
+		})
+	}
+}
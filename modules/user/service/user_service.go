@@ -2,46 +2,219 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 	"tracerstudy-auth-service/common/config"
 	"tracerstudy-auth-service/common/errors"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/password"
 	"tracerstudy-auth-service/common/utils"
+	roleRepo "tracerstudy-auth-service/modules/role/repository"
 	"tracerstudy-auth-service/modules/user/entity"
 	"tracerstudy-auth-service/modules/user/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultUsersPageSize = 10
+	maxUsersPageSize     = 100
 )
 
 type UserService struct {
-	cfg            config.Config
-	userRepository repository.UserRepositoryUseCase
+	cfg                  config.Config
+	userRepository       repository.UserRepositoryUseCase
+	userScopeRepository  repository.UserScopeRepositoryUseCase
+	roleRepository       roleRepo.RoleRepositoryUseCase
+	jwtManager           *commonJwt.JWT
+	passwordHasher       password.Hasher
+	passwordPolicy       *password.Policy
+	emailVerificationSvc EmailVerificationServiceUseCase
 }
 
 type UserServiceUseCase interface {
-	FindAll(ctx context.Context, req any) ([]*entity.User, error)
+	FindAll(ctx context.Context, query *entity.UserQuery) (*entity.UserPage, error)
 	FindById(ctx context.Context, id uint64) (*entity.User, error)
 	FindByUsername(ctx context.Context, username string) (*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
 	Create(ctx context.Context, name, username, email, password string, roleId uint32) (*entity.User, error)
 	Update(ctx context.Context, id uint64, fields *entity.User) (*entity.User, error)
 	Delete(ctx context.Context, id uint64) error
+	Authenticate(ctx context.Context, user *entity.User, givenPassword string) (bool, error)
+	ChangePassword(ctx context.Context, id uint64, currentPassword, newPassword string) error
+	ResetPassword(ctx context.Context, id uint64, newPassword string) error
+	ValidatePassword(ctx context.Context, id uint64, password string) error
+	FindScopes(ctx context.Context, id uint64) ([]*entity.UserScope, error)
+	SetScopes(ctx context.Context, id uint64, scopes []*entity.UserScope) ([]*entity.UserScope, error)
+	ClaimScopes(ctx context.Context, id uint64) (commonJwt.Scopes, error)
 }
 
-func NewUserService(cfg config.Config, userRepository repository.UserRepositoryUseCase) *UserService {
+func NewUserService(
+	cfg config.Config,
+	userRepository repository.UserRepositoryUseCase,
+	userScopeRepository repository.UserScopeRepositoryUseCase,
+	roleRepository roleRepo.RoleRepositoryUseCase,
+	jwtManager *commonJwt.JWT,
+	passwordHasher password.Hasher,
+	emailVerificationService EmailVerificationServiceUseCase,
+) *UserService {
 	return &UserService{
-		cfg:            cfg,
-		userRepository: userRepository,
+		cfg:                  cfg,
+		userRepository:       userRepository,
+		userScopeRepository:  userScopeRepository,
+		roleRepository:       roleRepository,
+		jwtManager:           jwtManager,
+		passwordHasher:       passwordHasher,
+		passwordPolicy:       password.NewPolicy(cfg.Password),
+		emailVerificationSvc: emailVerificationService,
 	}
 }
 
-func (svc *UserService) FindAll(ctx context.Context, req any) ([]*entity.User, error) {
-	res, err := svc.userRepository.FindAll(ctx, req)
+func (svc *UserService) FindAll(ctx context.Context, query *entity.UserQuery) (*entity.UserPage, error) {
+	after, err := svc.normalizeUserQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := svc.userRepository.FindAll(ctx, query, after)
 	if err != nil {
 		parseError := errors.ParseError(err)
 		log.Println("ERROR: [UserService - FindAll] Error while find all user: ", parseError.Message)
 		return nil, err
 	}
 
-	return res, nil
+	page := &entity.UserPage{
+		Users:       users,
+		CurrentPage: query.Page,
+	}
+
+	if len(users) > query.Limit {
+		page.Users = users[:query.Limit]
+		page.NextCursor, err = encodeUserCursor(query, page.Users[query.Limit-1])
+		if err != nil {
+			log.Println("ERROR: [UserService - FindAll] Error while encoding cursor: ", err)
+			return nil, err
+		}
+	}
+
+	// counting is skipped for cursor pages, which serve large exports
+	if after == nil {
+		page.TotalRows, err = svc.userRepository.Count(ctx, query)
+		if err != nil {
+			parseError := errors.ParseError(err)
+			log.Println("ERROR: [UserService - FindAll] Error while count users: ", parseError.Message)
+			return nil, err
+		}
+		page.TotalPages = (page.TotalRows + int64(query.Limit) - 1) / int64(query.Limit)
+	}
+
+	return page, nil
+}
+
+// normalizeUserQuery applies the defaults to query and decodes its cursor.
+func (svc *UserService) normalizeUserQuery(query *entity.UserQuery) (*entity.UserCursor, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultUsersPageSize
+	}
+	if query.Limit > maxUsersPageSize {
+		query.Limit = maxUsersPageSize
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	if query.SortBy == "" {
+		query.SortBy = entity.UserSortById
+	}
+
+	switch query.SortBy {
+	case entity.UserSortById, entity.UserSortByName, entity.UserSortByUsername, entity.UserSortByEmail,
+		entity.UserSortByCreatedAt, entity.UserSortByUpdatedAt:
+	default:
+		log.Println("WARNING: [UserService - FindAll] Unsupported sort column:", query.SortBy)
+		return nil, status.Errorf(codes.InvalidArgument, "users cannot be sorted by %s", query.SortBy)
+	}
+
+	if query.Cursor == "" {
+		return nil, nil
+	}
+
+	cursor, err := decodeUserCursor(query.Cursor)
+	if err != nil || cursor.SortBy != query.SortBy || cursor.SortDesc != query.SortDesc || cursor.Filters != userQueryFilters(query) {
+		log.Println("WARNING: [UserService - FindAll] Invalid cursor:", err)
+		return nil, status.Errorf(codes.InvalidArgument, "cursor is invalid for the requested filters and sort")
+	}
+
+	return cursor, nil
+}
+
+func encodeUserCursor(query *entity.UserQuery, last *entity.User) (string, error) {
+	cursor := &entity.UserCursor{
+		SortBy:   query.SortBy,
+		SortDesc: query.SortDesc,
+		Filters:  userQueryFilters(query),
+		Id:       last.Id,
+	}
+
+	switch query.SortBy {
+	case entity.UserSortByName:
+		cursor.Value = last.Name
+	case entity.UserSortByUsername:
+		cursor.Value = last.Username
+	case entity.UserSortByEmail:
+		cursor.Value = last.Email
+	case entity.UserSortByCreatedAt:
+		cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	case entity.UserSortByUpdatedAt:
+		cursor.Value = last.UpdatedAt.Format(time.RFC3339Nano)
+	}
+
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// userQueryFilters hashes the filters of query, so a cursor cannot be
+// replayed with other filters than it was issued for.
+func userQueryFilters(query *entity.UserQuery) string {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	filters := fmt.Sprintf("%d\x00%s\x00%s\x00%s\x00%t", query.RoleId, query.Search, formatTime(query.CreatedFrom), formatTime(query.CreatedTo), query.IncludeDeleted)
+
+	return utils.HashToken(filters)[:16]
+}
+
+func decodeUserCursor(s string) (*entity.UserCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor entity.UserCursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, err
+	}
+
+	if cursor.SortBy == entity.UserSortByCreatedAt || cursor.SortBy == entity.UserSortByUpdatedAt {
+		if _, err := time.Parse(time.RFC3339Nano, cursor.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cursor, nil
 }
 
 func (svc *UserService) FindById(ctx context.Context, id uint64) (*entity.User, error) {
@@ -77,12 +250,28 @@ func (svc *UserService) FindByEmail(ctx context.Context, email string) (*entity.
 	return res, nil
 }
 
-func (svc *UserService) Create(ctx context.Context, name, username, email, password string, roleId uint32) (*entity.User, error) {
+func (svc *UserService) Create(ctx context.Context, name, username, email, newPassword string, roleId uint32) (*entity.User, error) {
+	if err := svc.validateRole(ctx, roleId); err != nil {
+		return nil, err
+	}
+
+	owner := password.Owner{Username: username, Email: email}
+	if err := svc.passwordPolicy.Check("password", newPassword, owner); err != nil {
+		log.Println("WARNING: [UserService - Create] Password rejected by policy:", errors.ParseError(err).Message)
+		return nil, err
+	}
+
+	hash, err := svc.passwordHasher.Hash(newPassword)
+	if err != nil {
+		log.Println("ERROR: [UserService - Create] Error while hashing password:", err)
+		return nil, status.Errorf(codes.Internal, "failed to hash password")
+	}
+
 	user := &entity.User{
 		Name:      name,
 		Username:  username,
 		Email:     email,
-		Password:  utils.HashPassword(password),
+		Password:  hash,
 		RoleId:    roleId,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -95,6 +284,11 @@ func (svc *UserService) Create(ctx context.Context, name, username, email, passw
 		return nil, err
 	}
 
+	// the account exists either way; the user can ask for another link
+	if err := svc.emailVerificationSvc.Send(ctx, res); err != nil {
+		log.Println("ERROR: [UserService - Create] Error while sending verification email: ", err)
+	}
+
 	return res, nil
 }
 
@@ -106,6 +300,12 @@ func (svc *UserService) Update(ctx context.Context, id uint64, fields *entity.Us
 		return nil, err
 	}
 
+	if fields.RoleId != 0 {
+		if err := svc.validateRole(ctx, fields.RoleId); err != nil {
+			return nil, err
+		}
+	}
+
 	updatedMap := make(map[string]interface{})
 
 	utils.AddItemToMap(updatedMap, "name", fields.Name)
@@ -113,6 +313,15 @@ func (svc *UserService) Update(ctx context.Context, id uint64, fields *entity.Us
 	utils.AddItemToMap(updatedMap, "email", fields.Email)
 	utils.AddItemToMap(updatedMap, "role_id", fields.RoleId)
 
+	// a new address has to be verified again
+	emailChanged := fields.Email != "" && !strings.EqualFold(fields.Email, user.Email)
+	if emailChanged {
+		updatedMap["email_verified_at"] = nil
+	}
+
+	// tokens carry the role, so changing it invalidates them
+	roleChanged := fields.RoleId != 0 && fields.RoleId != user.RoleId
+
 	res, err := svc.userRepository.Update(ctx, user, updatedMap)
 	if err != nil {
 		parseError := errors.ParseError(err)
@@ -120,9 +329,24 @@ func (svc *UserService) Update(ctx context.Context, id uint64, fields *entity.Us
 		return nil, err
 	}
 
+	if roleChanged {
+		if err := svc.jwtManager.RevokeSubject(ctx, commonJwt.UserSubject(id)); err != nil {
+			log.Println("ERROR: [UserService - Update] Error while revoking user tokens: ", err)
+			return nil, err
+		}
+	}
+
+	if emailChanged {
+		if err := svc.emailVerificationSvc.Send(ctx, res); err != nil {
+			log.Println("ERROR: [UserService - Update] Error while sending verification email: ", err)
+		}
+	}
+
 	return res, nil
 }
 
+// Delete soft-deletes the user, which is also how an account is
+// deactivated: the user can no longer log in and their tokens are revoked.
 func (svc *UserService) Delete(ctx context.Context, id uint64) error {
 	_, err := svc.userRepository.FindById(ctx, id)
 	if err != nil {
@@ -138,5 +362,240 @@ func (svc *UserService) Delete(ctx context.Context, id uint64) error {
 		return err
 	}
 
+	if err := svc.jwtManager.RevokeSubject(ctx, commonJwt.UserSubject(id)); err != nil {
+		log.Println("ERROR: [UserService - Delete] Error while revoking user tokens: ", err)
+		return err
+	}
+
 	return nil
 }
+
+// Authenticate checks givenPassword against the user's stored hash. A
+// matching hash made with an outdated algorithm or parameters is replaced,
+// but failing to replace it does not fail the login.
+func (svc *UserService) Authenticate(ctx context.Context, user *entity.User, givenPassword string) (bool, error) {
+	match, needsRehash, err := svc.passwordHasher.Verify(givenPassword, user.Password)
+	if err != nil {
+		log.Println("ERROR: [UserService - Authenticate] Error while verifying password for user", user.Id, ":", err)
+		return false, status.Errorf(codes.Internal, "failed to verify password")
+	}
+
+	if !match || !needsRehash {
+		return match, nil
+	}
+
+	hash, err := svc.passwordHasher.Hash(givenPassword)
+	if err != nil {
+		log.Println("ERROR: [UserService - Authenticate] Error while rehashing password:", err)
+		return true, nil
+	}
+
+	if _, err := svc.userRepository.Update(ctx, user, map[string]interface{}{"password": hash}); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - Authenticate] Error while saving rehashed password: ", parseError.Message)
+	}
+
+	return true, nil
+}
+
+// ChangePassword replaces the password of a user who proved the current one,
+// then revokes every token issued to the user before this second so the
+// caller can issue itself a new one.
+func (svc *UserService) ChangePassword(ctx context.Context, id uint64, currentPassword, newPassword string) error {
+	user, err := svc.userRepository.FindById(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - ChangePassword] Error while find user by ID: ", parseError.Message)
+		return err
+	}
+
+	match, _, err := svc.passwordHasher.Verify(currentPassword, user.Password)
+	if err != nil {
+		log.Println("ERROR: [UserService - ChangePassword] Error while verifying password:", err)
+		return status.Errorf(codes.Internal, "failed to verify password")
+	}
+
+	if !match {
+		log.Println("WARNING: [UserService - ChangePassword] Current password mismatch for user", id)
+		return status.Errorf(codes.Unauthenticated, "current password is incorrect")
+	}
+
+	if err := svc.validateNewPassword(user, currentPassword, newPassword); err != nil {
+		log.Println("WARNING: [UserService - ChangePassword] Password rejected by policy:", errors.ParseError(err).Message)
+		return err
+	}
+
+	hash, err := svc.passwordHasher.Hash(newPassword)
+	if err != nil {
+		log.Println("ERROR: [UserService - ChangePassword] Error while hashing password:", err)
+		return status.Errorf(codes.Internal, "failed to hash password")
+	}
+
+	changedAt := time.Now()
+	if _, err := svc.userRepository.Update(ctx, user, map[string]interface{}{"password": hash, "updated_at": changedAt}); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - ChangePassword] Error while update password: ", parseError.Message)
+		return err
+	}
+
+	if err := svc.jwtManager.RevokeSubjectBefore(ctx, commonJwt.UserSubject(id), changedAt); err != nil {
+		log.Println("ERROR: [UserService - ChangePassword] Error while revoking user tokens: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// ResetPassword revokes every token issued to a user who proved control of
+// their mailbox, then replaces their password.
+func (svc *UserService) ResetPassword(ctx context.Context, id uint64, newPassword string) error {
+	user, err := svc.userRepository.FindById(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - ResetPassword] Error while find user by ID: ", parseError.Message)
+		return err
+	}
+
+	if err := svc.validateNewPassword(user, "", newPassword); err != nil {
+		log.Println("WARNING: [UserService - ResetPassword] Password rejected by policy:", errors.ParseError(err).Message)
+		return err
+	}
+
+	hash, err := svc.passwordHasher.Hash(newPassword)
+	if err != nil {
+		log.Println("ERROR: [UserService - ResetPassword] Error while hashing password:", err)
+		return status.Errorf(codes.Internal, "failed to hash password")
+	}
+
+	// revoking first means an error always leaves the old password in place
+	if err := svc.jwtManager.RevokeSubject(ctx, commonJwt.UserSubject(id)); err != nil {
+		log.Println("ERROR: [UserService - ResetPassword] Error while revoking user tokens: ", err)
+		return err
+	}
+
+	if _, err := svc.userRepository.Update(ctx, user, map[string]interface{}{"password": hash, "updated_at": time.Now()}); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - ResetPassword] Error while update password: ", parseError.Message)
+		return err
+	}
+
+	return nil
+}
+
+// ValidatePassword checks that password is acceptable as the user's new
+// password without changing anything.
+func (svc *UserService) ValidatePassword(ctx context.Context, id uint64, newPassword string) error {
+	user, err := svc.userRepository.FindById(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - ValidatePassword] Error while find user by ID: ", parseError.Message)
+		return err
+	}
+
+	return svc.validateNewPassword(user, "", newPassword)
+}
+
+func (svc *UserService) FindScopes(ctx context.Context, id uint64) ([]*entity.UserScope, error) {
+	if _, err := svc.userRepository.FindById(ctx, id); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - FindScopes] Error while find user by ID: ", parseError.Message)
+		return nil, err
+	}
+
+	res, err := svc.userScopeRepository.FindByUserId(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - FindScopes] Error while find user scopes: ", parseError.Message)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SetScopes replaces the prodi and faculty assignments of a user. Tokens
+// carry the scopes, so the user's current tokens are revoked.
+func (svc *UserService) SetScopes(ctx context.Context, id uint64, scopes []*entity.UserScope) ([]*entity.UserScope, error) {
+	if _, err := svc.userRepository.FindById(ctx, id); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - SetScopes] Error while find user by ID: ", parseError.Message)
+		return nil, err
+	}
+
+	var newScopes []*entity.UserScope
+	for _, s := range scopes {
+		kodeprodi, kodefak := strings.TrimSpace(s.Kodeprodi), strings.TrimSpace(s.Kodefak)
+		if kodeprodi == "" && kodefak == "" {
+			log.Println("WARNING: [UserService - SetScopes] Scope without kodeprodi or kodefak for user", id)
+			return nil, status.Errorf(codes.InvalidArgument, "each scope needs a kodeprodi or a kodefak")
+		}
+		newScopes = append(newScopes, entity.NewUserScope(id, kodeprodi, kodefak))
+	}
+
+	if err := svc.userScopeRepository.SetUserScopes(ctx, id, newScopes); err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - SetScopes] Error while set user scopes: ", parseError.Message)
+		return nil, err
+	}
+
+	if err := svc.jwtManager.RevokeSubject(ctx, commonJwt.UserSubject(id)); err != nil {
+		log.Println("ERROR: [UserService - SetScopes] Error while revoking user tokens: ", err)
+		return nil, err
+	}
+
+	return newScopes, nil
+}
+
+// ClaimScopes returns the scopes to put into the user's tokens.
+func (svc *UserService) ClaimScopes(ctx context.Context, id uint64) (commonJwt.Scopes, error) {
+	var claimScopes commonJwt.Scopes
+
+	scopes, err := svc.userScopeRepository.FindByUserId(ctx, id)
+	if err != nil {
+		parseError := errors.ParseError(err)
+		log.Println("ERROR: [UserService - ClaimScopes] Error while find user scopes: ", parseError.Message)
+		return claimScopes, err
+	}
+
+	for _, s := range scopes {
+		if s.Kodeprodi != "" {
+			claimScopes.Kodeprodi = append(claimScopes.Kodeprodi, s.Kodeprodi)
+		}
+		if s.Kodefak != "" {
+			claimScopes.Kodefak = append(claimScopes.Kodefak, s.Kodefak)
+		}
+	}
+
+	return claimScopes, nil
+}
+
+func (svc *UserService) validateRole(ctx context.Context, roleId uint32) error {
+	_, err := svc.roleRepository.FindById(ctx, roleId)
+	if err == nil {
+		return nil
+	}
+
+	if status.Code(err) == codes.NotFound {
+		log.Println("WARNING: [UserService - validateRole] Role does not exist:", roleId)
+		return status.Errorf(codes.InvalidArgument, "role %d does not exist", roleId)
+	}
+
+	parseError := errors.ParseError(err)
+	log.Println("ERROR: [UserService - validateRole] Error while find role by ID: ", parseError.Message)
+	return err
+}
+
+// validateNewPassword applies the password policy to a password chosen by
+// an existing user. An empty currentPassword skips the reuse check.
+func (svc *UserService) validateNewPassword(user *entity.User, currentPassword, newPassword string) error {
+	owner := password.Owner{Username: user.Username, Email: user.Email}
+	violations := svc.passwordPolicy.Validate("new_password", newPassword, owner)
+
+	if currentPassword != "" && newPassword == currentPassword {
+		violations = append(violations, password.Violation{
+			Field:       "new_password",
+			Description: "must differ from the current password",
+		})
+	}
+
+	return password.Error(violations)
+}
@@ -0,0 +1,493 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/config"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/password"
+	"tracerstudy-auth-service/modules/user/entity"
+	"tracerstudy-auth-service/modules/user/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeUserRepository struct {
+	repository.UserRepositoryUseCase
+	users   []*entity.User
+	after   *entity.UserCursor
+	counted bool
+	updated map[string]interface{}
+}
+
+func (r *fakeUserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "user not found")
+}
+
+func (r *fakeUserRepository) MarkEmailVerified(ctx context.Context, id uint64, email string) (bool, error) {
+	for _, u := range r.users {
+		if u.Id == id && u.Email == email {
+			now := time.Now()
+			u.EmailVerifiedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeUserRepository) FindById(ctx context.Context, id uint64) (*entity.User, error) {
+	for _, u := range r.users {
+		if u.Id == id {
+			return u, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "user not found")
+}
+
+func (r *fakeUserRepository) Update(ctx context.Context, user *entity.User, updatedFields map[string]interface{}) (*entity.User, error) {
+	r.updated = updatedFields
+	return user, nil
+}
+
+type fakeRevocationStore struct {
+	commonJwt.RevocationStore
+	revoked bool
+}
+
+func (s *fakeRevocationStore) RevokeSubject(ctx context.Context, subject string, revokedAt time.Time) error {
+	s.revoked = true
+	return nil
+}
+
+func (r *fakeUserRepository) FindAll(ctx context.Context, query *entity.UserQuery, after *entity.UserCursor) ([]*entity.User, error) {
+	r.after = after
+	if len(r.users) > query.Limit+1 {
+		return r.users[:query.Limit+1], nil
+	}
+	return r.users, nil
+}
+
+func (r *fakeUserRepository) Count(ctx context.Context, query *entity.UserQuery) (int64, error) {
+	r.counted = true
+	return int64(len(r.users)), nil
+}
+
+type fakeUserScopeRepository struct {
+	repository.UserScopeRepositoryUseCase
+	scopes []*entity.UserScope
+	set    bool
+}
+
+func (r *fakeUserScopeRepository) FindByUserId(ctx context.Context, userId uint64) ([]*entity.UserScope, error) {
+	return r.scopes, nil
+}
+
+func (r *fakeUserScopeRepository) SetUserScopes(ctx context.Context, userId uint64, scopes []*entity.UserScope) error {
+	r.scopes = scopes
+	r.set = true
+	return nil
+}
+
+func TestUserCursor(t *testing.T) {
+	created := time.Date(2024, 2, 29, 8, 30, 15, 123456789, time.FixedZone("WIB", 7*60*60))
+	last := &entity.User{
+		Id:        42,
+		Name:      "Budi Santoso",
+		Username:  "budi",
+		Email:     "budi@example.com",
+		CreatedAt: created,
+		UpdatedAt: created.Add(time.Hour),
+	}
+
+	tests := []struct {
+		sortBy    string
+		sortDesc  bool
+		wantValue string
+	}{
+		{sortBy: entity.UserSortById},
+		{sortBy: entity.UserSortByName, wantValue: "Budi Santoso"},
+		{sortBy: entity.UserSortByUsername, sortDesc: true, wantValue: "budi"},
+		{sortBy: entity.UserSortByEmail, wantValue: "budi@example.com"},
+		{sortBy: entity.UserSortByCreatedAt, sortDesc: true, wantValue: "2024-02-29T08:30:15.123456789+07:00"},
+		{sortBy: entity.UserSortByUpdatedAt, wantValue: "2024-02-29T09:30:15.123456789+07:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			encoded, err := encodeUserCursor(&entity.UserQuery{SortBy: tt.sortBy, SortDesc: tt.sortDesc}, last)
+			if err != nil {
+				t.Fatalf("encodeUserCursor() error = %v", err)
+			}
+
+			cursor, err := decodeUserCursor(encoded)
+			if err != nil {
+				t.Fatalf("decodeUserCursor() error = %v", err)
+			}
+
+			want := entity.UserCursor{SortBy: tt.sortBy, SortDesc: tt.sortDesc, Filters: userQueryFilters(&entity.UserQuery{}), Value: tt.wantValue, Id: last.Id}
+			if *cursor != want {
+				t.Errorf("decodeUserCursor() = %+v, want %+v", *cursor, want)
+			}
+		})
+	}
+}
+
+func TestDecodeUserCursorInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "padded base64", cursor: base64.URLEncoding.EncodeToString([]byte(`{"s":"id","i":1}`))},
+		{name: "not json", cursor: encode("id:1")},
+		{name: "malformed time", cursor: encode(`{"s":"created_at","v":"yesterday","i":1}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeUserCursor(tt.cursor); err == nil {
+				t.Errorf("decodeUserCursor(%q) succeeded", tt.cursor)
+			}
+		})
+	}
+}
+
+func TestUserServiceFindAll(t *testing.T) {
+	var users []*entity.User
+	for i := 1; i <= 25; i++ {
+		users = append(users, &entity.User{Id: uint64(i), Name: "user"})
+	}
+
+	nameCursor, err := encodeUserCursor(&entity.UserQuery{SortBy: entity.UserSortByName}, users[9])
+	if err != nil {
+		t.Fatalf("encodeUserCursor() error = %v", err)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	filtered := func() *entity.UserQuery {
+		return &entity.UserQuery{RoleId: 5, Search: "budi", CreatedFrom: &from, SortBy: entity.UserSortById}
+	}
+	filteredCursor, err := encodeUserCursor(filtered(), users[9])
+	if err != nil {
+		t.Fatalf("encodeUserCursor() error = %v", err)
+	}
+	withCursor := func(query *entity.UserQuery, cursor string) *entity.UserQuery {
+		query.Cursor = cursor
+		return query
+	}
+	otherFrom := from.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		query          *entity.UserQuery
+		wantCode       codes.Code
+		wantUsers      int
+		wantNextCursor bool
+		wantAfter      *entity.UserCursor
+		wantCounted    bool
+		wantLimit      int
+	}{
+		{
+			name:           "first page with defaults",
+			query:          &entity.UserQuery{},
+			wantUsers:      10,
+			wantNextCursor: true,
+			wantCounted:    true,
+			wantLimit:      defaultUsersPageSize,
+		},
+		{
+			name:        "last page",
+			query:       &entity.UserQuery{Limit: 30},
+			wantUsers:   25,
+			wantCounted: true,
+		},
+		{
+			name:        "limit is capped",
+			query:       &entity.UserQuery{Limit: 1000},
+			wantUsers:   25,
+			wantCounted: true,
+			wantLimit:   maxUsersPageSize,
+		},
+		{
+			name:           "cursor page skips counting",
+			query:          &entity.UserQuery{SortBy: entity.UserSortByName, Cursor: nameCursor},
+			wantUsers:      10,
+			wantNextCursor: true,
+			wantAfter:      &entity.UserCursor{SortBy: entity.UserSortByName, Filters: userQueryFilters(&entity.UserQuery{}), Value: "user", Id: 10},
+		},
+		{
+			name:           "cursor with the filters it was issued for",
+			query:          withCursor(filtered(), filteredCursor),
+			wantUsers:      10,
+			wantNextCursor: true,
+			wantAfter:      &entity.UserCursor{SortBy: entity.UserSortById, Filters: userQueryFilters(filtered()), Id: 10},
+		},
+		{
+			name:     "cursor of another role",
+			query:    withCursor(&entity.UserQuery{RoleId: 2, Search: "budi", CreatedFrom: &from}, filteredCursor),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "cursor of another search",
+			query:    withCursor(&entity.UserQuery{RoleId: 5, Search: "siti", CreatedFrom: &from}, filteredCursor),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "cursor of another creation date",
+			query:    withCursor(&entity.UserQuery{RoleId: 5, Search: "budi", CreatedFrom: &otherFrom}, filteredCursor),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "cursor replayed with deleted users",
+			query:    withCursor(&entity.UserQuery{RoleId: 5, Search: "budi", CreatedFrom: &from, IncludeDeleted: true}, filteredCursor),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "cursor replayed without filters",
+			query:    withCursor(&entity.UserQuery{}, filteredCursor),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "cursor of another sort",
+			query:    &entity.UserQuery{SortBy: entity.UserSortByEmail, Cursor: nameCursor},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "cursor of another direction",
+			query:    &entity.UserQuery{SortBy: entity.UserSortByName, SortDesc: true, Cursor: nameCursor},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unsupported sort column",
+			query:    &entity.UserQuery{SortBy: "password"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepository{users: users}
+			svc := NewUserService(config.Config{}, repo, nil, nil, nil, nil, nil)
+
+			page, err := svc.FindAll(context.Background(), tt.query)
+			if tt.wantCode != codes.OK {
+				if status.Code(err) != tt.wantCode {
+					t.Fatalf("FindAll() error = %v, want %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindAll() error = %v", err)
+			}
+
+			if len(page.Users) != tt.wantUsers {
+				t.Errorf("FindAll() returned %d users, want %d", len(page.Users), tt.wantUsers)
+			}
+			if (page.NextCursor != "") != tt.wantNextCursor {
+				t.Errorf("FindAll() next cursor = %q, want one %v", page.NextCursor, tt.wantNextCursor)
+			}
+			if repo.counted != tt.wantCounted {
+				t.Errorf("FindAll() counted = %v, want %v", repo.counted, tt.wantCounted)
+			}
+			if (repo.after == nil) != (tt.wantAfter == nil) || (repo.after != nil && *repo.after != *tt.wantAfter) {
+				t.Errorf("FindAll() searched after %+v, want %+v", repo.after, tt.wantAfter)
+			}
+			if tt.wantLimit != 0 && tt.query.Limit != tt.wantLimit {
+				t.Errorf("FindAll() limit = %d, want %d", tt.query.Limit, tt.wantLimit)
+			}
+			if tt.wantCounted && page.TotalPages != (25+int64(tt.query.Limit)-1)/int64(tt.query.Limit) {
+				t.Errorf("FindAll() total pages = %d with limit %d", page.TotalPages, tt.query.Limit)
+			}
+		})
+	}
+}
+
+func newTestHasher(t *testing.T) password.Hasher {
+	t.Helper()
+
+	hasher, err := password.NewHasher(config.Password{
+		HashAlgorithm:     password.AlgorithmArgon2id,
+		Argon2Memory:      64,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+
+	return hasher
+}
+
+func TestUserServiceChangePassword(t *testing.T) {
+	hasher := newTestHasher(t)
+	current, err := hasher.Hash("Kopi-Tubruk-42")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		currentPassword string
+		newPassword     string
+		wantCode        codes.Code
+	}{
+		{name: "changed", currentPassword: "Kopi-Tubruk-42", newPassword: "Teh-Tarik-2024"},
+		{name: "wrong current password", currentPassword: "Kopi-Susu-42", newPassword: "Teh-Tarik-2024", wantCode: codes.Unauthenticated},
+		{name: "reused password", currentPassword: "Kopi-Tubruk-42", newPassword: "Kopi-Tubruk-42", wantCode: codes.InvalidArgument},
+		{name: "too short", currentPassword: "Kopi-Tubruk-42", newPassword: "Teh-42", wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeRevocationStore{}
+			jwtManager := commonJwt.NewJWT(config.JWTConfig{}, commonJwt.NewHMACSigningKey("", []byte("secret")), store)
+			repo := &fakeUserRepository{users: []*entity.User{{Id: 1, Username: "budi", Email: "budi@example.com", Password: current}}}
+			svc := NewUserService(config.Config{Password: config.Password{MinLength: 12}}, repo, nil, nil, jwtManager, hasher, nil)
+
+			err := svc.ChangePassword(context.Background(), 1, tt.currentPassword, tt.newPassword)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("ChangePassword() error = %v, want %v", err, tt.wantCode)
+			}
+			changed := tt.wantCode == codes.OK
+			if updated := repo.updated != nil; updated != changed {
+				t.Errorf("password replaced = %v, want %v", updated, changed)
+			}
+			if store.revoked != changed {
+				t.Errorf("tokens revoked = %v, want %v", store.revoked, changed)
+			}
+			if changed {
+				if match, _, _ := hasher.Verify(tt.newPassword, repo.updated["password"].(string)); !match {
+					t.Errorf("saved hash does not match the new password")
+				}
+			}
+		})
+	}
+}
+
+func TestUserServiceResetPassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		store       *fakeRevocationStore
+		wantErr     bool
+		wantUpdated bool
+	}{
+		{
+			name:        "tokens are revoked and the password replaced",
+			store:       &fakeRevocationStore{},
+			wantUpdated: true,
+		},
+		{
+			name:    "failed revocation keeps the old password",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher := newTestHasher(t)
+
+			var store commonJwt.RevocationStore
+			if tt.store != nil {
+				store = tt.store
+			}
+			jwtManager := commonJwt.NewJWT(config.JWTConfig{}, commonJwt.NewHMACSigningKey("", []byte("secret")), store)
+
+			repo := &fakeUserRepository{users: []*entity.User{{Id: 1, Username: "budi", Email: "budi@example.com"}}}
+			svc := NewUserService(config.Config{}, repo, nil, nil, jwtManager, hasher, nil)
+
+			err := svc.ResetPassword(context.Background(), 1, "Kopi-Tubruk-42")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResetPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if updated := repo.updated != nil; updated != tt.wantUpdated {
+				t.Errorf("password replaced = %v, want %v", updated, tt.wantUpdated)
+			}
+			if tt.store != nil && !tt.store.revoked {
+				t.Errorf("tokens were not revoked")
+			}
+		})
+	}
+}
+
+func TestUserServiceSetScopes(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          uint64
+		scopes      []*entity.UserScope
+		wantCode    codes.Code
+		wantRevoked bool
+	}{
+		{
+			name:        "prodi and faculty",
+			id:          1,
+			scopes:      []*entity.UserScope{{Kodeprodi: " 0401 "}, {Kodefak: "05"}},
+			wantRevoked: true,
+		},
+		{
+			name:     "scope without prodi or faculty",
+			id:       1,
+			scopes:   []*entity.UserScope{{Kodeprodi: "0401"}, {Kodeprodi: " "}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown user",
+			id:       2,
+			scopes:   []*entity.UserScope{{Kodeprodi: "0401"}},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeRevocationStore{}
+			scopeRepo := &fakeUserScopeRepository{}
+			jwtManager := commonJwt.NewJWT(config.JWTConfig{}, commonJwt.NewHMACSigningKey("", []byte("secret")), store)
+			svc := NewUserService(config.Config{}, &fakeUserRepository{users: []*entity.User{{Id: 1}}}, scopeRepo, nil, jwtManager, nil, nil)
+
+			got, err := svc.SetScopes(context.Background(), tt.id, tt.scopes)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("SetScopes() error = %v, want %v", err, tt.wantCode)
+			}
+			if store.revoked != tt.wantRevoked || scopeRepo.set != tt.wantRevoked {
+				t.Errorf("SetScopes() saved = %v, revoked = %v, want %v", scopeRepo.set, store.revoked, tt.wantRevoked)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+			if len(got) != 2 || got[0].Kodeprodi != "0401" || got[0].UserId != tt.id || got[1].Kodefak != "05" {
+				t.Errorf("SetScopes() = %+v, want trimmed scopes of user %d", got, tt.id)
+			}
+		})
+	}
+}
+
+func TestUserServiceClaimScopes(t *testing.T) {
+	scopeRepo := &fakeUserScopeRepository{scopes: []*entity.UserScope{
+		{Kodeprodi: "0401"},
+		{Kodefak: "05"},
+		{Kodeprodi: "0402", Kodefak: "04"},
+	}}
+	svc := NewUserService(config.Config{}, nil, scopeRepo, nil, nil, nil, nil)
+
+	got, err := svc.ClaimScopes(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ClaimScopes() error = %v", err)
+	}
+	want := commonJwt.Scopes{Kodeprodi: []string{"0401", "0402"}, Kodefak: []string{"05", "04"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ClaimScopes() = %+v, want %+v", got, want)
+	}
+}
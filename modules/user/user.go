@@ -2,6 +2,9 @@ package user
 
 import (
 	"tracerstudy-auth-service/common/config"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+	"tracerstudy-auth-service/common/mailer"
+	"tracerstudy-auth-service/common/password"
 	"tracerstudy-auth-service/modules/user/builder"
 	"tracerstudy-auth-service/pb"
 
@@ -9,7 +12,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func InitGrpc(server *grpc.Server, cfg config.Config, db *gorm.DB, grpcConn *grpc.ClientConn) {
-	user := builder.BuildUserHandler(cfg, db, grpcConn)
+func InitGrpc(server *grpc.Server, cfg config.Config, db *gorm.DB, jwtManager *commonJwt.JWT, passwordHasher password.Hasher, mailer mailer.Mailer, grpcConn *grpc.ClientConn) {
+	user := builder.BuildUserHandler(cfg, db, jwtManager, passwordHasher, mailer, grpcConn)
 	pb.RegisterUserServiceServer(server, user)
 }
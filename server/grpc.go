@@ -9,7 +9,7 @@ import (
 	"syscall"
 	"time"
 
-	roles "tracerstudy-auth-service/common/authorization"
+	"tracerstudy-auth-service/common/authorization"
 	commonJwt "tracerstudy-auth-service/common/jwt"
 	"tracerstudy-auth-service/server/interceptor"
 
@@ -43,16 +43,17 @@ func NewGrpc(port string, options ...grpc.ServerOption) *Grpc {
 	}
 }
 
-func NewGrpcServer(port string, jwtManager *commonJwt.JWT) *Grpc {
+func NewGrpcServer(port string, jwtManager *commonJwt.JWT, policy authorization.Policy, extraOptions ...grpc.ServerOption) *Grpc {
 	// var options grpc.ServerOption
 	// options := grpc_middleware.WithUnaryServerChain()
 	// add option unary interceptor
 	// jwtManager := commonJwt.NewJWT(secretKey, tokenDuration)
 	// authInterceptor := interceptor.NewAuthInterceptor(jwtManager, accessibleRoles())
-	authInterceptor := interceptor.NewAuthInterceptor(jwtManager, roles.GetAccessibleRoles())
+	authInterceptor := interceptor.NewAuthInterceptor(jwtManager, policy)
 	options := []grpc.ServerOption{
-		grpc.UnaryInterceptor(authInterceptor.Unary()),
+		grpc.ChainUnaryInterceptor(authInterceptor.Unary(), interceptor.RedactUnary()),
 	}
+	options = append(options, extraOptions...)
 	server := NewGrpc(port, options...)
 	return server
 }
@@ -1,9 +1,16 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
+
+	"tracerstudy-auth-service/common/certs"
+	"tracerstudy-auth-service/common/config"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -30,13 +37,53 @@ func Dial(name string, opts ...DialOption) (*grpc.ClientConn, error) {
 	return conn, nil
 }
 
-// dial with ssl
+// WithUnaryInterceptor adds interceptor to the calls made on the
+// connection.
+func WithUnaryInterceptor(interceptor grpc.UnaryClientInterceptor) DialOption {
+	return func(name string) (grpc.DialOption, error) {
+		return grpc.WithChainUnaryInterceptor(interceptor), nil
+	}
+}
+
+// WithTLS dials over TLS instead of plaintext, verifying the server against
+// the CAs in caFile, or the system roots when it is empty, under
+// serverName, or the dialed host when it is empty, and presenting the
+// configured client certificate. Every handshake uses the files as last
+// loaded by reloader.
+func WithTLS(reloader *certs.Reloader, cfg config.TLS, caFile, serverName string) DialOption {
+	return func(name string) (grpc.DialOption, error) {
+		newConfig, err := reloader.ClientConfig(cfg, caFile, serverName)
+		if err != nil {
+			return nil, err
+		}
+		return grpc.WithTransportCredentials(&reloadingTLS{
+			TransportCredentials: credentials.NewTLS(newConfig()),
+			newConfig:            newConfig,
+		}), nil
+	}
+}
+
+// reloadingTLS builds fresh TLS credentials for each client handshake,
+// since credentials.NewTLS keeps the config it was created with.
+type reloadingTLS struct {
+	credentials.TransportCredentials
+	newConfig func() *tls.Config
+}
 
-func InitGRPCConn(addr string, ssl bool, cert string) *grpc.ClientConn {
-	// if ssl true, dial with ssl
+func (c *reloadingTLS) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return credentials.NewTLS(c.newConfig()).ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *reloadingTLS) Clone() credentials.TransportCredentials {
+	return &reloadingTLS{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		newConfig:            c.newConfig,
+	}
+}
 
-	// else
-	conn, err := Dial(addr)
+// InitGRPCConn dials addr in plaintext, or over TLS when given WithTLS.
+func InitGRPCConn(addr string, opts ...DialOption) *grpc.ClientConn {
+	conn, err := Dial(addr, opts...)
 	if err != nil {
 		panic(fmt.Sprintf("ERROR: dial error: %v", err))
 	}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"tracerstudy-auth-service/common/upstream"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	HealthPath = "/health"
+
+	upstreamServicePrefix = "upstream."
+)
+
+type HealthStatus struct {
+	Status    string            `json:"status"`
+	Upstreams map[string]string `json:"upstreams"`
+}
+
+// RegisterHealth serves the gRPC health protocol. The service itself is
+// always SERVING; each upstream is reported as "upstream.<name>", and is
+// NOT_SERVING while its circuit breaker is open.
+func RegisterHealth(server *grpc.Server, upstreams *upstream.Registry) {
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	setStatus := func(name string, state upstream.State) {
+		status := healthpb.HealthCheckResponse_SERVING
+		if state == upstream.StateOpen {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		healthServer.SetServingStatus(upstreamServicePrefix+name, status)
+	}
+
+	upstreams.OnStateChange(setStatus)
+	for _, b := range upstreams.Breakers() {
+		setStatus(b.Name(), b.State())
+	}
+
+	healthpb.RegisterHealthServer(server, healthServer)
+}
+
+// NewHealthRestHandler reports the circuit breaker state of every upstream.
+// The status is "degraded" while any breaker is not closed.
+func NewHealthRestHandler(upstreams *upstream.Registry) func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		res := HealthStatus{
+			Status:    "ok",
+			Upstreams: make(map[string]string),
+		}
+
+		for _, b := range upstreams.Breakers() {
+			state := b.State()
+			if state != upstream.StateClosed {
+				res.Status = "degraded"
+			}
+			res.Upstreams[b.Name()] = state.String()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			log.Println("ERROR: [HealthRestHandler] Error while encoding health status:", err)
+		}
+	}
+}
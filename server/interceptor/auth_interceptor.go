@@ -5,6 +5,7 @@ import (
 	"log"
 	"strings"
 
+	"tracerstudy-auth-service/common/authorization"
 	commonJwt "tracerstudy-auth-service/common/jwt"
 	"tracerstudy-auth-service/common/utils"
 
@@ -14,14 +15,14 @@ import (
 )
 
 type AuthInterceptor struct {
-	jwtManager      *commonJwt.JWT
-	accessibleRoles map[string][]uint32
+	jwtManager *commonJwt.JWT
+	policy     authorization.Policy
 }
 
-func NewAuthInterceptor(jwtManager *commonJwt.JWT, accessibleRoles map[string][]uint32) *AuthInterceptor {
+func NewAuthInterceptor(jwtManager *commonJwt.JWT, policy authorization.Policy) *AuthInterceptor {
 	return &AuthInterceptor{
-		jwtManager:      jwtManager,
-		accessibleRoles: accessibleRoles,
+		jwtManager: jwtManager,
+		policy:     policy,
 	}
 }
 
@@ -38,11 +39,28 @@ func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 }
 
 func (a *AuthInterceptor) authorize(ctx context.Context, method string) error {
-	accessibleRoles, ok := a.accessibleRoles[method]
-	if !ok {
+	resource, action := authorization.SplitFullMethod(method)
+	if authorization.IsPublic(resource, action) {
 		return nil
 	}
 
+	governed, err := a.policy.Governs(ctx, resource, action)
+	if err != nil {
+		log.Println("ERROR: [Auth Interceptor - Authorize] Error while evaluating policy:", err)
+		return status.Errorf(codes.Internal, "error while evaluating policy")
+	}
+
+	if !governed {
+		if !authorization.IsOwnResource(resource) {
+			// such as the gRPC health service
+			return nil
+		}
+
+		// a missing permission must not make an RPC public
+		log.Println("ERROR: [Auth Interceptor - Authorize] No permission defined for", method)
+		return status.Errorf(codes.PermissionDenied, "no permission to access this RPC")
+	}
+
 	authHeader, err := utils.GetMetadataAuthorization(ctx)
 	if err != nil {
 		log.Println("ERROR: [Auth Interceptor - Authorize] Error while getting metadata authorization:", err)
@@ -57,16 +75,20 @@ func (a *AuthInterceptor) authorize(ctx context.Context, method string) error {
 
 	accessToken := parts[1]
 
-	claims, err := a.jwtManager.Verify(accessToken)
+	claims, err := a.jwtManager.Verify(ctx, accessToken)
 	if err != nil {
 		log.Println("ERROR: [Auth Interceptor - Authorize] Access token is invalid:", err)
 		return status.Errorf(codes.Unauthenticated, "access token is invalid: %v", err)
 	}
 
-	for _, role := range accessibleRoles {
-		if role == claims.Role {
-			return nil
-		}
+	allowed, err := a.policy.Allows(ctx, resource, action, claims.Role)
+	if err != nil {
+		log.Println("ERROR: [Auth Interceptor - Authorize] Error while evaluating policy:", err)
+		return status.Errorf(codes.Internal, "error while evaluating policy")
+	}
+
+	if allowed {
+		return nil
 	}
 
 	log.Println("ERROR: [Auth Interceptor - Authorize] No permission to access this RPC")
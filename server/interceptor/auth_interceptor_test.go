@@ -0,0 +1,132 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+	"tracerstudy-auth-service/common/authorization"
+	"tracerstudy-auth-service/common/config"
+	commonJwt "tracerstudy-auth-service/common/jwt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakePolicy allows the roles listed per method, and governs only those.
+type fakePolicy map[string][]uint32
+
+func (p fakePolicy) Governs(ctx context.Context, resource, action string) (bool, error) {
+	_, ok := p["/"+resource+"/"+action]
+	return ok, nil
+}
+
+func (p fakePolicy) Allows(ctx context.Context, resource, action string, role uint32) (bool, error) {
+	for _, r := range p["/"+resource+"/"+action] {
+		if r == role {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestAuthInterceptorAuthorize(t *testing.T) {
+	const (
+		getAllUsers   = "/tracer_study_grpc.UserService/GetAllUsers"
+		loginUser     = "/tracer_study_grpc.AuthService/LoginUser"
+		ungovernedRpc = "/tracer_study_grpc.UserService/ExportUsers"
+		healthCheck   = "/grpc.health.v1.Health/Check"
+	)
+
+	jwtManager := commonJwt.NewJWT(config.JWTConfig{TokenDuration: time.Minute}, commonJwt.NewHMACSigningKey("", []byte("secret")), nil)
+	bearer := func(role uint32) string {
+		token, err := jwtManager.GenerateToken("user:1", role, commonJwt.Scopes{})
+		if err != nil {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		return "Bearer " + token
+	}
+
+	policy := fakePolicy{getAllUsers: {authorization.RoleSuperAdmin, authorization.RoleAdmin}}
+
+	tests := []struct {
+		name     string
+		method   string
+		header   string
+		wantCode codes.Code
+	}{
+		{
+			name:     "allowed role",
+			method:   getAllUsers,
+			header:   bearer(authorization.RoleAdmin),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "role without the permission",
+			method:   getAllUsers,
+			header:   bearer(authorization.RoleAlumni),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "missing token",
+			method:   getAllUsers,
+			header:   "",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "wrong scheme",
+			method:   getAllUsers,
+			header:   "Basic dXNlcjpwYXNz",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "invalid token",
+			method:   getAllUsers,
+			header:   "Bearer not-a-token",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "public RPC needs no token",
+			method:   loginUser,
+			header:   "",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "own RPC without a permission is denied",
+			method:   ungovernedRpc,
+			header:   bearer(authorization.RoleSuperAdmin),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "other services without a permission are allowed",
+			method:   healthCheck,
+			header:   "",
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.header))
+			}
+
+			err := NewAuthInterceptor(jwtManager, policy).authorize(ctx, tt.method)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("authorize() error = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestPublicActionsHaveNoPermission(t *testing.T) {
+	// a public RPC with a seeded permission would silently ignore it
+	for resource, actions := range authorization.GetAccessibleRoles() {
+		for action := range actions {
+			if authorization.IsPublic(resource, action) {
+				t.Errorf("%s/%s is public but has a default permission", resource, action)
+			}
+		}
+	}
+}
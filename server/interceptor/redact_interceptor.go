@@ -0,0 +1,23 @@
+package interceptor
+
+import (
+	"context"
+
+	"tracerstudy-auth-service/common/redact"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// RedactUnary clears credential fields from every response before it is
+// sent, which also covers responses forwarded by the REST gateway.
+func RedactUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if msg, ok := resp.(proto.Message); ok {
+			redact.Message(msg)
+		}
+
+		return resp, err
+	}
+}
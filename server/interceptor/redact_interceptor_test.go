@@ -0,0 +1,61 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"tracerstudy-auth-service/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRedactUnary(t *testing.T) {
+	failed := status.Errorf(codes.Internal, "failed")
+
+	tests := []struct {
+		name    string
+		resp    any
+		err     error
+		wantErr error
+	}{
+		{
+			name: "response with a password",
+			resp: &pb.CreateUserRequest{Username: "budi", Password: "Kopi-Tubruk-42"},
+		},
+		{
+			name:    "failed handler",
+			resp:    &pb.CreateUserRequest{Username: "budi", Password: "Kopi-Tubruk-42"},
+			err:     failed,
+			wantErr: failed,
+		},
+		{
+			name: "no response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(ctx context.Context, req any) (any, error) {
+				return tt.resp, tt.err
+			}
+
+			resp, err := RedactUnary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/tracer_study_grpc.UserService/CreateUser"}, handler)
+			if err != tt.wantErr {
+				t.Fatalf("RedactUnary() error = %v, want %v", err, tt.wantErr)
+			}
+			if resp != tt.resp {
+				t.Fatalf("RedactUnary() response = %v, want the handler's", resp)
+			}
+
+			if user, ok := resp.(*pb.CreateUserRequest); ok {
+				if user.Password != "" {
+					t.Errorf("password = %q, want it cleared", user.Password)
+				}
+				if user.Username != "budi" {
+					t.Errorf("username = %q, want it kept", user.Username)
+				}
+			}
+		})
+	}
+}
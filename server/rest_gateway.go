@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"tracerstudy-auth-service/common/redact"
+	"tracerstudy-auth-service/common/throttle"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 type Rest struct {
@@ -40,6 +43,7 @@ func NewRest(port string) *Rest {
 					},
 				}),
 				runtime.WithErrorHandler(customErrorHandler),
+				runtime.WithForwardResponseOption(redactResponse),
 			),
 			port: port,
 	}
@@ -70,12 +74,22 @@ func allowCORS(h http.Handler) http.Handler {
 	})
 }
 
+// redactResponse clears credential fields from responses the gateway
+// forwards, in case they bypassed the gRPC interceptor.
+func redactResponse(ctx context.Context, w http.ResponseWriter, resp proto.Message) error {
+	redact.Message(resp)
+	return nil
+}
+
 func customErrorHandler(ctx context.Context, mux *runtime.ServeMux, mrs runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
 	const fallback = `{"error": {"code":13,"message":"failed to marshal error message"}, "meta":null}`
 
 	s := status.Convert(err)
 
 	w.Header().Set("Content-type", mrs.ContentType("application/json"))
+	if wait, ok := throttle.RetryAfter(err); ok {
+		w.Header().Set("Retry-After", throttle.RetryAfterSeconds(wait))
+	}
 	w.WriteHeader(runtime.HTTPStatusFromCode(s.Code()))
 	jsonErr := json.NewEncoder(w).Encode(Error{
 		Error: ErrorData{